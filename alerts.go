@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/metrics"
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+const (
+	// maxAlertListings caps how many listings one webhook message describes
+	maxAlertListings = 10
+	// maxAlertMatches caps how many matching auctions one run considers
+	maxAlertMatches = 200
+)
+
+// alertEvaluator checks alert rules against the live auction house and
+// delivers their webhooks
+type alertEvaluator struct {
+	auctions store.AuctionRepository
+	alerts   store.AlertRepository
+	client   *http.Client
+	baseURL  string
+	// itemQuery is appended to item page links to select the realm
+	itemQuery string
+}
+
+// start evaluates every enabled rule every interval. It returns immediately.
+func (e *alertEvaluator) start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			e.evaluateAll(context.Background())
+			<-ticker.C
+		}
+	}()
+}
+
+func (e *alertEvaluator) evaluateAll(ctx context.Context) {
+	rules, err := e.alerts.AlertRules(ctx, true)
+	if err != nil {
+		metrics.QueryErrors.Inc("alert_evaluator")
+		log.Printf("Error loading alert rules: %v", err)
+		return
+	}
+
+	for _, rule := range rules {
+		if err := e.evaluate(ctx, rule); err != nil {
+			log.Printf("Error evaluating alert %d: %v", rule.ID, err)
+		}
+	}
+
+	// Auctions last at most 48 hours, so older deliveries can never repeat
+	if err := e.alerts.PruneDeliveries(ctx, time.Now().Add(-72*time.Hour)); err != nil {
+		metrics.QueryErrors.Inc("alert_delivery_prune")
+		log.Printf("Error pruning alert deliveries: %v", err)
+	}
+}
+
+// evaluate sends one webhook describing every matching auction the rule has
+// not already fired for. Deliveries are only recorded once the webhook
+// succeeds, so a failed delivery is retried on the next run.
+func (e *alertEvaluator) evaluate(ctx context.Context, rule store.AlertRule) error {
+	page, err := e.auctions.ListAuctions(ctx, store.AuctionQuery{
+		Filter: rule.Filter(),
+		Sort:   "unit_buyout",
+		Page:   1,
+		Limit:  maxAlertMatches,
+	})
+	if err != nil {
+		return err
+	}
+	if len(page.Auctions) == 0 {
+		return nil
+	}
+
+	delivered, err := e.alerts.DeliveredAuctions(ctx, rule.ID)
+	if err != nil {
+		return err
+	}
+
+	var fresh []store.AuctionItem
+	var ids []int
+	for _, auction := range page.Auctions {
+		if !delivered[auction.ID] {
+			fresh = append(fresh, auction)
+			ids = append(ids, auction.ID)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	if err := e.sendWebhook(rule, fresh); err != nil {
+		return err
+	}
+	if err := e.alerts.RecordDeliveries(ctx, rule.ID, ids, time.Now()); err != nil {
+		return err
+	}
+	log.Printf("Alert %d fired for %d auctions", rule.ID, len(fresh))
+	return nil
+}
+
+// sendWebhook posts a message that renders in Discord (content and embeds)
+// and Slack (text) incoming webhooks
+func (e *alertEvaluator) sendWebhook(rule store.AlertRule, auctions []store.AuctionItem) error {
+	title := rule.Name
+	if title == "" {
+		title = fmt.Sprintf("Alert #%d", rule.ID)
+	}
+
+	shown := auctions
+	if len(shown) > maxAlertListings {
+		shown = shown[:maxAlertListings]
+	}
+
+	lines := make([]string, 0, len(shown)+1)
+	for _, auction := range shown {
+		lines = append(lines, fmt.Sprintf("%dx %s for %s (%s each) by %s, %s left",
+			auction.Count, auction.ItemName, wow.FormatGold(auction.BuyoutPrice),
+			wow.FormatGold(auction.UnitBuyout), auction.OwnerName, auction.TimeLeft))
+	}
+	if extra := len(auctions) - len(shown); extra > 0 {
+		lines = append(lines, fmt.Sprintf("...and %d more", extra))
+	}
+
+	summary := fmt.Sprintf("%s: %d new listing(s) at or under %s per unit",
+		title, len(auctions), wow.FormatGold(rule.MaxUnitBuyout))
+	description := strings.Join(lines, "\n")
+
+	embed := map[string]interface{}{
+		"title":       title,
+		"description": description,
+		"color":       wow.QualityColors[shown[0].Quality],
+	}
+	if e.baseURL != "" {
+		embed["url"] = strings.TrimRight(e.baseURL, "/") + "/items/" + strconv.Itoa(shown[0].ItemEntry) + e.itemQuery
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"username": "Auction House",
+		"content":  summary,
+		"text":     summary + "\n" + description,
+		"embeds":   []interface{}{embed},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Post(rule.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
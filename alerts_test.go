@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// webhookRecorder is a webhook endpoint that records the summary of every
+// message it accepts
+type webhookRecorder struct {
+	mu       sync.Mutex
+	status   int
+	messages []string
+}
+
+func (h *webhookRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.status != 0 {
+		w.WriteHeader(h.status)
+		return
+	}
+	var payload struct {
+		Content string `json:"content"`
+	}
+	json.NewDecoder(r.Body).Decode(&payload)
+	h.messages = append(h.messages, payload.Content)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newAlertTestStore() *store.Memory {
+	m := store.NewMemory()
+	expires := int(time.Now().Add(12 * time.Hour).Unix())
+	m.AddItem(store.ItemTemplate{Entry: 2589, Name: "Linen Cloth", Quality: 1})
+	m.AddAuction(store.AuctionItem{ID: 1, HouseID: wow.HouseAlliance, OwnerName: "Alice", ItemEntry: 2589, Count: 20, BuyoutPrice: 1000, Time: expires})
+	m.AddAuction(store.AuctionItem{ID: 2, HouseID: wow.HouseHorde, OwnerName: "Bob", ItemEntry: 2589, Count: 10, BuyoutPrice: 2000, Time: expires})
+	return m
+}
+
+func TestAlertEvaluatorDeliversOnce(t *testing.T) {
+	hook := &webhookRecorder{}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+
+	m := newAlertTestStore()
+	ctx := context.Background()
+	if _, err := m.CreateAlertRule(ctx, store.AlertRule{Name: "Linen", ItemEntry: 2589, MaxUnitBuyout: 100, WebhookURL: server.URL, Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	e := &alertEvaluator{auctions: m, alerts: m, client: server.Client()}
+
+	e.evaluateAll(ctx)
+	e.evaluateAll(ctx)
+	if len(hook.messages) != 1 || !strings.HasPrefix(hook.messages[0], "Linen: 1 new listing(s)") {
+		t.Fatalf("messages = %q, want one message for the 50c listing", hook.messages)
+	}
+
+	m.AddAuction(store.AuctionItem{ID: 3, HouseID: wow.HouseHorde, OwnerName: "Bob", ItemEntry: 2589, Count: 1, BuyoutPrice: 40, Time: int(time.Now().Add(time.Hour).Unix())})
+	e.evaluateAll(ctx)
+	if len(hook.messages) != 2 || !strings.HasPrefix(hook.messages[1], "Linen: 1 new listing(s)") {
+		t.Errorf("messages = %q, want a second message for the new listing only", hook.messages)
+	}
+}
+
+func TestAlertEvaluatorRetriesFailedDelivery(t *testing.T) {
+	hook := &webhookRecorder{status: http.StatusInternalServerError}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+
+	m := newAlertTestStore()
+	ctx := context.Background()
+	rule, err := m.CreateAlertRule(ctx, store.AlertRule{ItemEntry: 2589, MaxUnitBuyout: 500, WebhookURL: server.URL, Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &alertEvaluator{auctions: m, alerts: m, client: server.Client()}
+
+	if err := e.evaluate(ctx, rule); err == nil {
+		t.Fatal("evaluate() succeeded against a failing webhook")
+	}
+	if delivered, _ := m.DeliveredAuctions(ctx, rule.ID); len(delivered) != 0 {
+		t.Errorf("delivered = %v after a failed webhook, want none", delivered)
+	}
+
+	hook.status = 0
+	if err := e.evaluate(ctx, rule); err != nil {
+		t.Fatal(err)
+	}
+	if len(hook.messages) != 1 || !strings.HasPrefix(hook.messages[0], "Alert #1: 2 new listing(s)") {
+		t.Errorf("messages = %q", hook.messages)
+	}
+}
+
+func TestTakeSnapshot(t *testing.T) {
+	m := newAlertTestStore()
+	ctx := context.Background()
+
+	if err := takeSnapshot(ctx, m, m); err != nil {
+		t.Fatal(err)
+	}
+	points, err := m.ItemHistory(ctx, 2589, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 1 || points[0].Listings != 2 || points[0].Quantity != 30 || points[0].MinBuyout != 50 {
+		t.Errorf("history = %+v", points)
+	}
+}
@@ -18,7 +18,7 @@
             version = "1.0.0";
             src = ./.;
             vendorHash = "sha256-kA5ITxwaDC3wTlfKpJYXHq5L3mnv+sYAOihBqQBVAXI=";
-            doCheck = false;
+            doCheck = true;
             meta = with pkgs.lib; {
               description = "Web-based auction house viewer for AzerothCore servers";
               homepage = "https://github.com/scottjab/azerothcore-web-ah";
@@ -99,7 +99,19 @@
                   name = mkOption {
                     type = types.str;
                     default = "acore_characters";
-                    description = "Database name.";
+                    description = "Characters database name.";
+                  };
+
+                  worldName = mkOption {
+                    type = types.str;
+                    default = "acore_world";
+                    description = "World database name. Set WORLD_DB_HOST and friends in the environment file if it lives on another server.";
+                  };
+
+                  authName = mkOption {
+                    type = types.str;
+                    default = "acore_auth";
+                    description = "Auth database name, read for the realm list. Set REALM_<id>_DB_NAME in the environment file for each realm after the first.";
                   };
                 };
 
@@ -149,6 +161,8 @@
                       "DB_PORT=${toString cfg.database.port}"
                       "DB_USER=${cfg.database.user}"
                       "DB_NAME=${cfg.database.name}"
+                      "WORLD_DB_NAME=${cfg.database.worldName}"
+                      "AUTH_DB_NAME=${cfg.database.authName}"
                     ] ++ (lib.optional (cfg.database.password != "") "DB_PASSWORD=${cfg.database.password}")
                       ++ (lib.optional (cfg.environmentFile != null) "ENV_FILE=${cfg.environmentFile}");
                     EnvironmentFile = lib.optional (cfg.environmentFile != null) cfg.environmentFile;
@@ -176,6 +190,8 @@
                       user = cfg.database.user;
                       password = cfg.database.password;
                       name = cfg.database.name;
+                      worldName = cfg.database.worldName;
+                      authName = cfg.database.authName;
                     };
                     server = {
                       port = cfg.port;
@@ -199,7 +215,7 @@
           shellHook = ''
             echo "AzerothCore Web AH Development Environment"
             echo "Available commands:"
-            echo "  go run main.go - Run the application"
+            echo "  go run . - Run the application"
             echo "  go test ./... - Run tests"
             echo "  go mod tidy - Clean up dependencies"
           '';
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/metrics"
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// startHistoryScanner snapshots the live auction house of a realm every
+// interval and prunes snapshots older than retention. It returns immediately.
+func startHistoryScanner(realm string, auctions store.AuctionRepository, history store.HistoryRepository, interval, retention time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		ctx := context.Background()
+		for {
+			if err := takeSnapshot(ctx, auctions, history); err != nil {
+				metrics.QueryErrors.Inc("price_snapshot")
+				log.Printf("Error taking price snapshot of %s: %v", realm, err)
+			}
+			if err := history.PruneSnapshots(ctx, time.Now().Add(-retention)); err != nil {
+				metrics.QueryErrors.Inc("price_snapshot_prune")
+				log.Printf("Error pruning price snapshots of %s: %v", realm, err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+func takeSnapshot(ctx context.Context, auctions store.AuctionRepository, history store.HistoryRepository) error {
+	byEntry, err := auctions.BuyoutListings(ctx)
+	if err != nil {
+		return err
+	}
+
+	prices := make(map[int]store.PricePoint, len(byEntry))
+	total := 0
+	for entry, listings := range byEntry {
+		prices[entry] = store.SummarizeListings(listings)
+		total += len(listings)
+	}
+
+	if err := history.SaveSnapshot(ctx, time.Now(), total, prices); err != nil {
+		return err
+	}
+	log.Printf("Recorded price snapshot of %d auctions across %d items", total, len(prices))
+	return nil
+}
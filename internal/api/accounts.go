@@ -0,0 +1,298 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// unknownAccountSalt stands in for the salt of usernames without an account,
+// so that they take as long to reject as wrong passwords
+var unknownAccountSalt = make([]byte, wow.SRP6Size)
+
+// accountInfo is the account of a session as the API reports it
+type accountInfo struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	GMLevel  int    `json:"gm_level"`
+	Admin    bool   `json:"admin"`
+}
+
+func (s *Server) accountInfo(sess session) accountInfo {
+	return accountInfo{ID: sess.AccountID, Username: sess.Username, GMLevel: sess.GMLevel, Admin: s.isAdmin(sess)}
+}
+
+var loginPage = template.Must(template.New("login").Parse(loginTemplate))
+
+func (s *Server) handleLoginPage(w http.ResponseWriter, r *http.Request) {
+	renderPage(w, r, loginPage, pageData(pageLocale(w, r), nil))
+}
+
+// handleLogin signs in with the username and password of a game account,
+// checked against the SRP6 verifier the auth server keeps, and sets the
+// session cookie. Banned accounts are refused. The session keeps the gmlevel
+// of the account on the admin realm.
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if !sameOrigin(r) {
+		writeError(w, r, http.StatusForbidden, codeForbidden, "Cross-origin request refused")
+		return
+	}
+	var body struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "invalid JSON body")
+		return
+	}
+	if body.Username == "" || body.Password == "" {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Username and password required")
+		return
+	}
+
+	account, err := s.accounts.Account(r.Context(), body.Username, s.adminRealm)
+	if errors.Is(err, store.ErrNotFound) {
+		wow.CheckSRP6Password(body.Username, body.Password, unknownAccountSalt, nil)
+		writeError(w, r, http.StatusUnauthorized, codeUnauthorized, "Invalid username or password")
+		return
+	}
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	if !wow.CheckSRP6Password(account.Username, body.Password, account.Salt, account.Verifier) {
+		writeError(w, r, http.StatusUnauthorized, codeUnauthorized, "Invalid username or password")
+		return
+	}
+	if account.Banned {
+		writeError(w, r, http.StatusForbidden, codeForbidden, "Account is banned")
+		return
+	}
+
+	expires := time.Now().Add(s.sessionTTL)
+	sess := session{AccountID: account.ID, Username: account.Username, GMLevel: account.GMLevel, Expires: expires.Unix()}
+	s.setSessionCookie(w, s.encodeSession(sess), expires)
+	log.Printf("[%s] Account %s signed in", requestID(r), account.Username)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.accountInfo(sess))
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	s.setSessionCookie(w, "", time.Unix(0, 0))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetAccount reports the account signed in
+func (s *Server) handleGetAccount(w http.ResponseWriter, r *http.Request) {
+	sess, ok := s.session(r)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, codeUnauthorized, "Not signed in")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.accountInfo(sess))
+}
+
+// handleGetMyAuctions lists the auctions of the characters on the account
+// signed in, with the parameters of /api/auctions
+func (s *Server) handleGetMyAuctions(w http.ResponseWriter, r *http.Request, rm *realm) {
+	sess, ok := s.session(r)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, codeUnauthorized, "Not signed in")
+		return
+	}
+	q, err := parseAuctionQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+	q.Filter.House, err = parseHouse(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid house")
+		return
+	}
+	q.Locale, err = parseLocale(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid locale")
+		return
+	}
+	q.Filter.Account = sess.AccountID
+
+	page, err := rm.Auctions.ListAuctions(r.Context(), q)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"auctions":    page.Auctions,
+		"page":        q.Page,
+		"limit":       q.Limit,
+		"total":       page.Total,
+		"next_cursor": page.NextCursor,
+		"sort":        q.Sort,
+		"order":       orderName(q.Desc),
+		"house":       q.Filter.House,
+		"locale":      q.Locale,
+	})
+}
+
+const loginTemplate = `<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.T.signIn}} - {{.T.title}}</title>
+    <style>
+        * {
+            margin: 0;
+            padding: 0;
+            box-sizing: border-box;
+        }
+
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            background: linear-gradient(135deg, #1e3c72 0%, #2a5298 100%);
+            color: #333;
+            min-height: 100vh;
+        }
+
+        .container {
+            max-width: 420px;
+            margin: 0 auto;
+            padding: 20px;
+        }
+
+        .header {
+            text-align: center;
+            margin-bottom: 30px;
+            color: white;
+        }
+
+        .header h1 {
+            font-size: 2.5rem;
+            margin-bottom: 10px;
+            text-shadow: 2px 2px 4px rgba(0,0,0,0.5);
+        }
+
+        .header a {
+            color: white;
+            opacity: 0.9;
+        }
+
+        .panel {
+            background: rgba(255, 255, 255, 0.95);
+            border-radius: 10px;
+            overflow: hidden;
+            box-shadow: 0 4px 6px rgba(0,0,0,0.1);
+        }
+
+        .panel-body {
+            padding: 20px;
+        }
+
+        form {
+            display: flex;
+            flex-direction: column;
+            gap: 10px;
+        }
+
+        input {
+            padding: 8px 10px;
+            border: 1px solid #ccc;
+            border-radius: 4px;
+        }
+
+        button {
+            padding: 8px 14px;
+            background: #2a5298;
+            color: white;
+            border: none;
+            border-radius: 4px;
+            cursor: pointer;
+        }
+
+        .note {
+            color: #666;
+            font-size: 0.9rem;
+        }
+
+        .error {
+            background: #ffebee;
+            color: #c62828;
+            padding: 10px 15px;
+            border-radius: 5px;
+            margin-top: 10px;
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>{{.T.signIn}}</h1>
+            <p><a href="/">&larr; {{.T.backToAuctions}}</a></p>
+        </div>
+
+        <div class="panel">
+            <div class="panel-body">
+                <form onsubmit="signIn(event)">
+                    <label for="username">{{.T.username}}</label>
+                    <input type="text" id="username" autocomplete="username" required>
+                    <label for="password">{{.T.password}}</label>
+                    <input type="password" id="password" autocomplete="current-password" required>
+                    <button type="submit">{{.T.signIn}}</button>
+                    <p class="note">{{.T.loginNote}}</p>
+                </form>
+                <div id="signInError"></div>
+            </div>
+        </div>
+    </div>
+
+    <script>
+        const T = {{.T}};
+
+        // next is where to go once signed in; only paths of this site are
+        // followed
+        function next() {
+            const path = new URLSearchParams(location.search).get('next') || '/';
+            return /^\/(?![\/\\])/.test(path) ? path : '/';
+        }
+
+        async function signIn(event) {
+            event.preventDefault();
+            const error = document.getElementById('signInError');
+            try {
+                const response = await fetch('/api/login', {
+                    method: 'POST',
+                    headers: {'Content-Type': 'application/json'},
+                    body: JSON.stringify({
+                        username: document.getElementById('username').value,
+                        password: document.getElementById('password').value
+                    })
+                });
+                if (!response.ok) {
+                    throw new Error((await response.json()).error.message);
+                }
+                location.href = next();
+            } catch (e) {
+                error.innerHTML = '<div class="error">' + T.errorSignIn + ': ' + escapeHTML(e.message) + '</div>';
+            }
+        }
+
+        function escapeHTML(text) {
+            const div = document.createElement('div');
+            div.textContent = text;
+            return div.innerHTML;
+        }
+    </script>
+</body>
+</html>`
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// newAccountServer returns a server offering sign-in to Alice's player
+// account, a game master's, a banned account and a visitor who is a game
+// master of another realm only, with alerts. It has no admin token.
+func newAccountServer(t *testing.T, audit store.AuditRepository) *Server {
+	m := newTestStore()
+	for _, a := range []store.Account{
+		{ID: 1, Username: "PLAYER"},
+		{ID: 2, Username: "GM", GMLevel: 3},
+		{ID: 3, Username: "BANNED", Banned: true},
+		{ID: 4, Username: "VISITOR"},
+	} {
+		a.Salt = make([]byte, wow.SRP6Size)
+		a.Salt[0] = byte(a.ID)
+		a.Verifier = wow.SRP6Verifier(a.Username, strings.ToLower(a.Username)+"pass", a.Salt)
+		m.AddAccount(a)
+	}
+	m.AddGMLevel(4, 2, 3)
+	m.AddCharacter(10, 1)
+	m.AddCharacter(11, 2)
+	m.AddAuctionatorClass(store.AuctionatorClass{Class: 7, Subclass: 5, Name: "Cloth", Bonding: 1, MaxCount: 10, StackCount: 20})
+	return newTestServer(t, Config{
+		Realms:      testRealms(Realm{Auctions: m, Alerts: m}),
+		Auctionator: m,
+		Audit:       audit,
+		Accounts:    m,
+		SessionKey:  []byte("test session key"),
+	})
+}
+
+// login signs in and returns the session cookie, failing the test unless the
+// status is want
+func login(t *testing.T, s *Server, username, password string, want int) *http.Cookie {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"username": username, "password": password})
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/login", strings.NewReader(string(body))))
+	if rec.Code != want {
+		t.Fatalf("login %s: status = %d, want %d; body: %s", username, rec.Code, want, rec.Body.String())
+	}
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == sessionCookie {
+			return c
+		}
+	}
+	return nil
+}
+
+// sessionRequest serves a request carrying the session cookie
+func sessionRequest(s *Server, method, target, body string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNewRequiresSessionKey(t *testing.T) {
+	m := newTestStore()
+	if _, err := New(Config{Realms: testRealms(Realm{Auctions: m}), Accounts: m}); err == nil {
+		t.Error("New() without a session key succeeded")
+	}
+}
+
+func TestLogin(t *testing.T) {
+	s := newAccountServer(t, nil)
+
+	if c := login(t, s, "nobody", "playerpass", http.StatusUnauthorized); c != nil {
+		t.Error("unknown account got a session")
+	}
+	if c := login(t, s, "player", "wrong", http.StatusUnauthorized); c != nil {
+		t.Error("wrong password got a session")
+	}
+	if c := login(t, s, "banned", "bannedpass", http.StatusForbidden); c != nil {
+		t.Error("banned account got a session")
+	}
+	login(t, s, "", "", http.StatusBadRequest)
+
+	// Account names and passwords are case-insensitive, as in the game
+	cookie := login(t, s, "player", "PlayerPass", http.StatusOK)
+	if cookie == nil || !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode {
+		t.Fatalf("session cookie = %+v, want an HttpOnly SameSite=Lax cookie", cookie)
+	}
+
+	rec := sessionRequest(s, http.MethodGet, "/api/account", "", cookie)
+	var account accountInfo
+	if err := json.NewDecoder(rec.Body).Decode(&account); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("account status = %d, decoding: %v", rec.Code, err)
+	}
+	if want := (accountInfo{ID: 1, Username: "PLAYER"}); account != want {
+		t.Errorf("account = %+v, want %+v", account, want)
+	}
+
+	if rec := sessionRequest(s, http.MethodGet, "/api/account", "", nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("without a session: status = %d, want 401", rec.Code)
+	}
+	// A game master's session with the player's signature
+	payload, _, _ := strings.Cut(s.encodeSession(session{AccountID: 2, Username: "GM", GMLevel: 3, Expires: time.Now().Add(time.Hour).Unix()}), ".")
+	_, signature, _ := strings.Cut(cookie.Value, ".")
+	forged := *cookie
+	forged.Value = payload + "." + signature
+	if rec := sessionRequest(s, http.MethodGet, "/api/account", "", &forged); rec.Code != http.StatusUnauthorized {
+		t.Errorf("forged session: status = %d, want 401", rec.Code)
+	}
+	expired := *cookie
+	expired.Value = s.encodeSession(session{AccountID: 1, Username: "PLAYER", Expires: time.Now().Add(-time.Minute).Unix()})
+	if rec := sessionRequest(s, http.MethodGet, "/api/account", "", &expired); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expired session: status = %d, want 401", rec.Code)
+	}
+
+	page := sessionRequest(s, http.MethodGet, "/", "", cookie).Body.String()
+	if !strings.Contains(page, "<strong>PLAYER</strong>") || !strings.Contains(page, `data-tab="mine"`) {
+		t.Error("home page does not show the account signed in and its auctions")
+	}
+	if page := sessionRequest(s, http.MethodGet, "/", "", nil).Body.String(); !strings.Contains(page, `href="/login"`) || strings.Contains(page, `data-tab="mine"`) {
+		t.Error("home page without a session does not offer signing in")
+	}
+
+	rec = sessionRequest(s, http.MethodPost, "/api/logout", "", cookie)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("logout status = %d, want 204", rec.Code)
+	}
+	if c := rec.Result().Cookies(); len(c) != 1 || c[0].Name != sessionCookie || c[0].MaxAge >= 0 {
+		t.Errorf("logout cookies = %+v, want the session cookie deleted", c)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/login", strings.NewReader(`{"username": "player", "password": "playerpass"}`))
+	req.Header.Set("Origin", "https://elsewhere.example")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("cross-origin login: status = %d, want 403", rec.Code)
+	}
+}
+
+func TestMyAuctions(t *testing.T) {
+	s := newAccountServer(t, nil)
+	if rec := sessionRequest(s, http.MethodGet, "/api/my-auctions", "", nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("without a session: status = %d, want 401", rec.Code)
+	}
+
+	cookie := login(t, s, "PLAYER", "playerpass", http.StatusOK)
+	rec := sessionRequest(s, http.MethodGet, "/api/my-auctions?sort=buyout", "", cookie)
+	var page store.AuctionPage
+	if err := json.NewDecoder(rec.Body).Decode(&page); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, decoding: %v", rec.Code, err)
+	}
+	// Alice's auctions, and none of Bob's on the game master's account
+	if page.Total != 2 || len(page.Auctions) != 2 || page.Auctions[0].ID != 2 || page.Auctions[1].ID != 1 {
+		t.Errorf("my auctions = %+v, want Alice's auctions 2 and 1", page.Auctions)
+	}
+
+	rec = sessionRequest(s, http.MethodGet, "/api/my-auctions?house=horde", "", cookie)
+	if err := json.NewDecoder(rec.Body).Decode(&page); err != nil || page.Total != 0 {
+		t.Errorf("my Horde auctions = %+v (%v), want none", page.Auctions, err)
+	}
+}
+
+func TestGameMasterAdmin(t *testing.T) {
+	audit := store.NewMemory()
+	s := newAccountServer(t, audit)
+
+	player := login(t, s, "PLAYER", "playerpass", http.StatusOK)
+	if rec := sessionRequest(s, http.MethodGet, "/api/admin/auctionator/classes", "", player); rec.Code != http.StatusUnauthorized {
+		t.Errorf("player: status = %d, want 401", rec.Code)
+	}
+	// Being a game master of another realm does not count
+	visitor := login(t, s, "VISITOR", "visitorpass", http.StatusOK)
+	if rec := sessionRequest(s, http.MethodGet, "/api/admin/auctionator/classes", "", visitor); rec.Code != http.StatusUnauthorized {
+		t.Errorf("game master of realm 2: status = %d, want 401", rec.Code)
+	}
+	// Without a token configured, no token is accepted
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/auctionator/classes", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("empty token: status = %d, want 401", rec.Code)
+	}
+
+	gm := login(t, s, "GM", "gmpass", http.StatusOK)
+	if rec := sessionRequest(s, http.MethodGet, "/api/admin/auctionator/classes", "", gm); rec.Code != http.StatusOK {
+		t.Errorf("game master: status = %d, want 200", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/api/admin/auctionator/classes/7/5", strings.NewReader(`{"max_count": 5}`))
+	req.AddCookie(gm)
+	req.Header.Set("Origin", "https://elsewhere.example")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("cross-origin change: status = %d, want 403", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/api/admin/auctionator/classes/7/5", strings.NewReader(`{"max_count": 5}`))
+	req.AddCookie(gm)
+	req.Header.Set("Origin", "http://"+req.Host)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("same-origin change: status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+
+	entries, _ := audit.AuditLog(t.Context(), 10)
+	if len(entries) != 1 || entries[0].Actor != "GM" {
+		t.Errorf("audit log = %+v, want the change by GM", entries)
+	}
+}
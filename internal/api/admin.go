@@ -0,0 +1,156 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/metrics"
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// requireAdmin serves next only to requests carrying the admin token as a
+// bearer token, or signed in with an account whose gmlevel allows admin
+// access. Browsers send the session cookie along with requests of other
+// sites too, so changes are only accepted from this site's own pages.
+func (s *Server) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.hasAdminToken(r) {
+			next(w, r)
+			return
+		}
+		if _, ok := s.adminSession(r); ok {
+			if r.Method != http.MethodGet && !sameOrigin(r) {
+				writeError(w, r, http.StatusForbidden, codeForbidden, "Cross-origin request refused")
+				return
+			}
+			next(w, r)
+			return
+		}
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, r, http.StatusUnauthorized, codeUnauthorized, "Admin token required")
+	}
+}
+
+// hasAdminToken reports whether a request carries the admin token
+func (s *Server) hasAdminToken(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && s.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1
+}
+
+// requireAdminRealm is requireAdmin for realm handlers
+func (s *Server) requireAdminRealm(next realmHandler) realmHandler {
+	return func(w http.ResponseWriter, r *http.Request, rm *realm) {
+		s.requireAdmin(func(w http.ResponseWriter, r *http.Request) {
+			next(w, r, rm)
+		})(w, r)
+	}
+}
+
+// adminActor names who made an admin request for the audit log: the account
+// signed in, or the address of requests made with the token, which admins
+// share.
+func (s *Server) adminActor(r *http.Request) string {
+	if !s.hasAdminToken(r) {
+		if sess, ok := s.adminSession(r); ok {
+			return sess.Username
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// auditAvailable reports whether admin changes can be recorded, writing an
+// error response if not. Changes are refused rather than made unrecorded.
+func (s *Server) auditAvailable(w http.ResponseWriter, r *http.Request) bool {
+	if s.audit == nil {
+		writeError(w, r, http.StatusServiceUnavailable, codeUnavailable, "Audit log is not available")
+		return false
+	}
+	return true
+}
+
+// recordAudit records a change made through the admin API. before and after
+// are encoded as JSON, and left out when nil. The change has been made by
+// then, so failures are only logged.
+func (s *Server) recordAudit(r *http.Request, action, target string, before, after interface{}) {
+	entry := store.AuditEntry{
+		Time:      time.Now(),
+		Actor:     s.adminActor(r),
+		Action:    action,
+		Target:    target,
+		RequestID: requestID(r),
+	}
+	if before != nil {
+		entry.Before, _ = json.Marshal(before)
+	}
+	if after != nil {
+		entry.After, _ = json.Marshal(after)
+	}
+	if err := s.audit.RecordAudit(r.Context(), entry); err != nil {
+		metrics.QueryErrors.Inc("audit")
+		log.Printf("[%s] recording %s of %s: %v", entry.RequestID, action, target, err)
+	}
+}
+
+// handleGetAudit lists the latest changes made through the admin API
+func (s *Server) handleGetAudit(w http.ResponseWriter, r *http.Request) {
+	p := queryParser{values: r.URL.Query()}
+	limit := defaultPageSize
+	if n := p.int("limit"); n > 0 {
+		limit = min(n, maxPageSize)
+	}
+	if p.err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, p.err.Error())
+		return
+	}
+
+	entries, err := s.audit.AuditLog(r.Context(), limit)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"entries": entries,
+		"limit":   limit,
+	})
+}
+
+func (s *Server) handleGetItemCache(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.items.Status())
+}
+
+// handleRefreshItemCache reloads the item templates, for use after the world
+// database has been changed
+func (s *Server) handleRefreshItemCache(w http.ResponseWriter, r *http.Request) {
+	status, err := s.items.Refresh(r.Context())
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	log.Printf("[%s] Reloaded %d item templates", requestID(r), status.Items)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// adminPageData tells the admin pages how they can be signed in to: with the
+// admin token, or with a game account, which may already be signed in
+func (s *Server) adminPageData(r *http.Request) map[string]interface{} {
+	_, sessionAdmin := s.adminSession(r)
+	return map[string]interface{}{
+		"SessionAdmin": sessionAdmin,
+		"TokenSignIn":  s.adminToken != "",
+		"Login":        s.accounts != nil,
+	}
+}
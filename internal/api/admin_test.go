@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// fakeCatalog is an item cache that counts its refreshes
+type fakeCatalog struct {
+	refreshes int
+}
+
+func (c *fakeCatalog) Refresh(ctx context.Context) (store.ItemCacheStatus, error) {
+	c.refreshes++
+	return c.Status(), nil
+}
+
+func (c *fakeCatalog) Status() store.ItemCacheStatus {
+	return store.ItemCacheStatus{Items: 3 * c.refreshes, LoadedAt: now}
+}
+
+// adminRequest serves an admin API request with the given bearer token
+func adminRequest(s *Server, method, target, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRefreshItemCache(t *testing.T) {
+	items := &fakeCatalog{}
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()}), Items: items, AdminToken: "s3cret"})
+
+	for _, token := range []string{"", "wrong"} {
+		rec := adminRequest(s, http.MethodPost, "/api/admin/items/refresh", token)
+		if rec.Code != http.StatusUnauthorized || items.refreshes != 0 {
+			t.Errorf("token %q: status = %d, refreshes = %d, want 401 and none", token, rec.Code, items.refreshes)
+		}
+	}
+
+	rec := adminRequest(s, http.MethodPost, "/api/admin/items/refresh", "s3cret")
+	var status store.ItemCacheStatus
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("refresh status = %d, decoding: %v", rec.Code, err)
+	}
+	if items.refreshes != 1 || status.Items != 3 || !status.LoadedAt.Equal(now) {
+		t.Errorf("refresh = %+v after %d refreshes", status, items.refreshes)
+	}
+
+	rec = get(t, s, "/metrics", http.StatusOK, nil)
+	for _, want := range []string{
+		"ah_item_cache_templates 3",
+		"ah_item_cache_loaded_timestamp_seconds " + strconv.FormatInt(now.Unix(), 10),
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+}
+
+func TestAdminDisabledWithoutToken(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()}), Items: &fakeCatalog{}})
+
+	rec := adminRequest(s, http.MethodGet, "/api/admin/items", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,648 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// Audit log actions of the auction house bot endpoints
+const (
+	auditAHBotUpdate  = "ahbot.update"
+	auditAHBotDisable = "ahbot.disable_item"
+	auditAHBotEnable  = "ahbot.enable_item"
+)
+
+var adminPage = template.Must(template.New("admin").Parse(adminTemplate))
+
+func (s *Server) handleAdminPage(w http.ResponseWriter, r *http.Request) {
+	renderPage(w, r, adminPage, pageData(pageLocale(w, r), s.adminPageData(r)))
+}
+
+// ahbotHouse is the bot configuration of one auction house with the live
+// auctions it can be compared against
+type ahbotHouse struct {
+	store.AHBotConfig
+	Live store.HouseDistribution `json:"live"`
+}
+
+// handleGetAHBot lists the bot configuration of every auction house next to
+// the realm's live distribution of auctions. The configuration is shared by
+// every realm of the world database.
+func (s *Server) handleGetAHBot(w http.ResponseWriter, r *http.Request, rm *realm) {
+	configs, err := s.ahbot.AHBotConfigs(r.Context())
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	live, err := rm.Auctions.Distribution(r.Context())
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	byHouse := make(map[int]store.HouseDistribution, len(live))
+	for _, d := range live {
+		byHouse[d.House] = d
+	}
+	houses := make([]ahbotHouse, len(configs))
+	for i, config := range configs {
+		houses[i] = ahbotHouse{AHBotConfig: config, Live: byHouse[config.AuctionHouse]}
+		houses[i].Live.House = config.AuctionHouse
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"houses": houses,
+	})
+}
+
+// handleUpdateAHBot changes the bot configuration of one auction house.
+// Settings left out of the body keep their values.
+func (s *Server) handleUpdateAHBot(w http.ResponseWriter, r *http.Request) {
+	if !s.auditAvailable(w, r) {
+		return
+	}
+	house, err := wow.ParseHouse(r.PathValue("house"))
+	if err != nil || house == 0 {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid house")
+		return
+	}
+
+	before, err := s.ahbot.AHBotConfig(r.Context(), house)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "Auction house bot config not found")
+		return
+	}
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	config := before
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "invalid JSON body")
+		return
+	}
+	config.AuctionHouse = house
+	if err := config.Validate(); err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+
+	config, err = s.ahbot.UpdateAHBotConfig(r.Context(), config)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "Auction house bot config not found")
+		return
+	}
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	s.recordAudit(r, auditAHBotUpdate, fmt.Sprintf("house %d", house), before, config)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(config)
+}
+
+func (s *Server) handleGetDisabledItems(w http.ResponseWriter, r *http.Request) {
+	items, err := s.ahbot.AHBotDisabledItems(r.Context())
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"items": items,
+	})
+}
+
+// handleDisableItem stops the bot listing the item whose entry is in the
+// body
+func (s *Server) handleDisableItem(w http.ResponseWriter, r *http.Request) {
+	if !s.auditAvailable(w, r) {
+		return
+	}
+	var body struct {
+		Entry int `json:"entry"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "invalid JSON body")
+		return
+	}
+	if body.Entry <= 0 {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid item entry")
+		return
+	}
+
+	if err := s.ahbot.DisableAHBotItem(r.Context(), body.Entry); err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	s.recordAudit(r, auditAHBotDisable, "item "+strconv.Itoa(body.Entry), nil, body)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleEnableItem lets the bot list an item again
+func (s *Server) handleEnableItem(w http.ResponseWriter, r *http.Request) {
+	if !s.auditAvailable(w, r) {
+		return
+	}
+	entry, ok := itemEntry(w, r)
+	if !ok {
+		return
+	}
+
+	err := s.ahbot.EnableAHBotItem(r.Context(), entry)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "Item is not disabled")
+		return
+	}
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	s.recordAudit(r, auditAHBotEnable, "item "+strconv.Itoa(entry), map[string]int{"entry": entry}, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+const adminTemplate = `<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.T.ahbotTitle}} - {{.T.title}}</title>
+    <style>
+        * {
+            margin: 0;
+            padding: 0;
+            box-sizing: border-box;
+        }
+
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            background: linear-gradient(135deg, #1e3c72 0%, #2a5298 100%);
+            color: #333;
+            min-height: 100vh;
+        }
+
+        .container {
+            max-width: 1400px;
+            margin: 0 auto;
+            padding: 20px;
+        }
+
+        .header {
+            text-align: center;
+            margin-bottom: 30px;
+            color: white;
+        }
+
+        .header h1 {
+            font-size: 2.5rem;
+            margin-bottom: 10px;
+            text-shadow: 2px 2px 4px rgba(0,0,0,0.5);
+        }
+
+        .header a {
+            color: white;
+            opacity: 0.9;
+        }
+
+        .panel {
+            background: rgba(255, 255, 255, 0.95);
+            border-radius: 10px;
+            overflow: hidden;
+            box-shadow: 0 4px 6px rgba(0,0,0,0.1);
+            margin-bottom: 20px;
+        }
+
+        .panel-header {
+            background: #2a5298;
+            color: white;
+            padding: 15px 20px;
+            font-weight: bold;
+        }
+
+        .panel-body {
+            padding: 15px 20px;
+        }
+
+        .toolbar {
+            display: flex;
+            flex-wrap: wrap;
+            gap: 10px;
+            align-items: center;
+            margin-bottom: 15px;
+        }
+
+        .table-container {
+            overflow-x: auto;
+        }
+
+        table {
+            width: 100%;
+            border-collapse: collapse;
+            margin-bottom: 15px;
+        }
+
+        th, td {
+            padding: 8px 12px;
+            text-align: left;
+            border-bottom: 1px solid #ddd;
+        }
+
+        th {
+            background: #f8f9fa;
+            font-weight: 600;
+        }
+
+        input {
+            padding: 6px 8px;
+            border: 1px solid #ccc;
+            border-radius: 4px;
+        }
+
+        input[type=number] {
+            width: 80px;
+        }
+
+        button {
+            padding: 6px 14px;
+            background: #2a5298;
+            color: white;
+            border: none;
+            border-radius: 4px;
+            cursor: pointer;
+        }
+
+        button.secondary {
+            background: #888;
+        }
+
+        .quality-0 { color: #9d9d9d; }
+        .quality-1 { color: #ffffff; text-shadow: 1px 1px 2px rgba(0,0,0,0.8); }
+        .quality-2 { color: #1eff00; text-shadow: 1px 1px 2px rgba(0,0,0,0.5); }
+        .quality-3 { color: #0070dd; }
+        .quality-4 { color: #a335ee; }
+        .quality-5 { color: #ff8000; }
+        .quality-6 { color: #e6cc80; }
+
+        .live {
+            color: #666;
+            font-size: 0.9rem;
+        }
+
+        .invalid {
+            color: #c62828;
+            font-weight: bold;
+        }
+
+        .note {
+            color: #666;
+            font-size: 0.9rem;
+        }
+
+        .loading {
+            text-align: center;
+            padding: 20px;
+            color: #666;
+        }
+
+        .error {
+            background: #ffebee;
+            color: #c62828;
+            padding: 10px 15px;
+            border-radius: 5px;
+            margin: 10px 0;
+        }
+
+        .success {
+            color: #2e7d32;
+        }
+
+        .hidden {
+            display: none;
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>{{.T.ahbotTitle}}</h1>
+            <p><a href="/">&larr; {{.T.backToAuctions}}</a></p>
+        </div>
+
+        <div class="panel" id="signIn">
+            <div class="panel-body">
+                {{if .TokenSignIn}}
+                <form class="toolbar" onsubmit="signIn(event)">
+                    <label for="token">{{.T.adminToken}}</label>
+                    <input type="password" id="token" autocomplete="current-password">
+                    <button type="submit">{{.T.signIn}}</button>
+                </form>
+                {{end}}
+                {{if .Login}}
+                <p class="note"><a href="/login?next=/admin">{{.T.signInWithAccount}}</a></p>
+                {{end}}
+                <div id="signInError"></div>
+            </div>
+        </div>
+
+        <div id="admin" class="hidden">
+            <div class="toolbar">
+                <button class="secondary" onclick="signOut()">{{.T.signOut}}</button>
+                <span class="note">{{.T.ahbotRestartNote}}</span>
+            </div>
+            <div id="houses"><div class="loading">{{.T.loading}}</div></div>
+
+            <div class="panel">
+                <div class="panel-header">{{.T.disabledItems}}</div>
+                <div class="panel-body">
+                    <form class="toolbar" onsubmit="disableItem(event)">
+                        <label for="disableEntry">{{.T.itemEntry}}</label>
+                        <input type="number" id="disableEntry" min="1" required>
+                        <button type="submit">{{.T.add}}</button>
+                    </form>
+                    <div id="disabledError"></div>
+                    <div class="table-container">
+                        <table>
+                            <thead>
+                                <tr><th>{{.T.itemEntry}}</th><th>{{.T.item}}</th><th></th></tr>
+                            </thead>
+                            <tbody id="disabledBody"></tbody>
+                        </table>
+                    </div>
+                </div>
+            </div>
+
+            <div class="panel">
+                <div class="panel-header">{{.T.auditLog}}</div>
+                <div class="panel-body table-container">
+                    <table>
+                        <thead>
+                            <tr><th>{{.T.time}}</th><th>{{.T.actor}}</th><th>{{.T.action}}</th><th>{{.T.target}}</th></tr>
+                        </thead>
+                        <tbody id="auditBody"></tbody>
+                    </table>
+                </div>
+            </div>
+        </div>
+    </div>
+
+    <script>
+        const T = {{.T}};
+        const realm = new URLSearchParams(location.search).get('realm');
+        const realmBase = realm ? '/api/realms/' + encodeURIComponent(realm) : '/api';
+        const qualityNames = [T.qualityPoor, T.qualityCommon, T.qualityUncommon, T.qualityRare,
+            T.qualityEpic, T.qualityLegendary, T.qualityArtifact];
+        // Per-quality settings other than the percentages, by JSON field
+        const priceFields = [
+            ['min_price', T.minPrice], ['max_price', T.maxPrice],
+            ['min_bid_price', T.minBidPrice], ['max_bid_price', T.maxBidPrice],
+            ['max_stack', T.maxStack], ['buyer_price', T.buyerPrice]
+        ];
+        let houses = [];
+
+        // Game masters signed in with their account need no token
+        let sessionAdmin = {{.SessionAdmin}};
+
+        document.addEventListener('DOMContentLoaded', function() {
+            if (sessionAdmin || sessionStorage.getItem('adminToken')) {
+                loadAll();
+            }
+        });
+
+        function signIn(event) {
+            event.preventDefault();
+            sessionStorage.setItem('adminToken', document.getElementById('token').value);
+            loadAll();
+        }
+
+        function signOut() {
+            sessionStorage.removeItem('adminToken');
+            if (sessionAdmin) {
+                sessionAdmin = false;
+                fetch('/api/logout', {method: 'POST'});
+            }
+            document.getElementById('admin').classList.add('hidden');
+            document.getElementById('signIn').classList.remove('hidden');
+        }
+
+        // api calls the admin API, throwing the error message of failed
+        // requests. A rejected token signs out.
+        async function api(method, path, body) {
+            const headers = {'Content-Type': 'application/json'};
+            if (sessionStorage.getItem('adminToken')) {
+                headers['Authorization'] = 'Bearer ' + sessionStorage.getItem('adminToken');
+            }
+            const response = await fetch(path, {
+                method: method,
+                headers: headers,
+                body: body === undefined ? undefined : JSON.stringify(body)
+            });
+            if (response.status === 401) {
+                signOut();
+                document.getElementById('signInError').innerHTML = '<div class="error">' + T.adminTokenRejected + '</div>';
+                throw new Error(T.adminTokenRejected);
+            }
+            if (!response.ok) {
+                throw new Error((await response.json()).error.message);
+            }
+            return response.status === 204 ? null : response.json();
+        }
+
+        async function loadAll() {
+            try {
+                await loadHouses();
+            } catch (error) {
+                console.error('Error loading auction house bot:', error);
+                return;
+            }
+            document.getElementById('signInError').innerHTML = '';
+            document.getElementById('signIn').classList.add('hidden');
+            document.getElementById('admin').classList.remove('hidden');
+            loadDisabledItems();
+            loadAudit();
+        }
+
+        async function loadHouses() {
+            const container = document.getElementById('houses');
+            try {
+                houses = (await api('GET', realmBase + '/admin/ahbot')).houses;
+            } catch (error) {
+                container.innerHTML = '<div class="error">' + T.errorAHBot + ': ' + escapeHTML(error.message) + '</div>';
+                throw error;
+            }
+            if (houses.length === 0) {
+                container.innerHTML = '<div class="panel"><div class="panel-body loading">' + T.ahbotNotInstalled + '</div></div>';
+                return;
+            }
+            container.innerHTML = houses.map(houseForm).join('');
+            houses.forEach(h => updateTotal(h.auction_house));
+        }
+
+        function numberInput(house, field, value) {
+            return '<input type="number" min="0" data-house="' + house + '" data-field="' + field + '" value="' + value + '"' +
+                ' oninput="updateTotal(' + house + ')">';
+        }
+
+        // liveShare formats a live count with its share of the house's listings
+        function liveShare(count, total) {
+            const percent = total > 0 ? Math.round(count * 100 / total) : 0;
+            return '<span class="live">' + count + ' (' + percent + '%)</span>';
+        }
+
+        function houseForm(h) {
+            const id = h.auction_house;
+            let rows = '';
+            for (let q = 0; q < qualityNames.length; q++) {
+                rows += '<tr>' +
+                    '<td class="quality-' + q + '">' + qualityNames[q] + '</td>' +
+                    '<td>' + numberInput(id, 'trade_goods_percent.' + q, h.trade_goods_percent[q]) + '</td>' +
+                    '<td>' + liveShare(h.live.trade_goods[q], h.live.total) + '</td>' +
+                    '<td>' + numberInput(id, 'items_percent.' + q, h.items_percent[q]) + '</td>' +
+                    '<td>' + liveShare(h.live.items[q], h.live.total) + '</td>' +
+                    priceFields.map(f => '<td>' + numberInput(id, f[0] + '.' + q, h[f[0]][q]) + '</td>').join('') +
+                    '</tr>';
+            }
+            return '<div class="panel">' +
+                '<div class="panel-header">' + escapeHTML(h.name || String(id)) + ' (' + id + ')</div>' +
+                '<div class="panel-body">' +
+                '<div class="toolbar">' +
+                '<label>' + T.minItems + ' ' + numberInput(id, 'min_items', h.min_items) + '</label>' +
+                '<label>' + T.maxItems + ' ' + numberInput(id, 'max_items', h.max_items) + '</label>' +
+                '<label>' + T.biddingInterval + ' ' + numberInput(id, 'buyer_bidding_interval', h.buyer_bidding_interval) + '</label>' +
+                '<label>' + T.bidsPerInterval + ' ' + numberInput(id, 'buyer_bids_per_interval', h.buyer_bids_per_interval) + '</label>' +
+                '<span class="live">' + T.liveListings + ': ' + h.live.total + '</span>' +
+                '</div>' +
+                '<div class="table-container"><table>' +
+                '<thead><tr><th>' + T.quality + '</th>' +
+                '<th>' + T.tradeGoods + ' %</th><th>' + T.live + '</th>' +
+                '<th>' + T.otherItems + ' %</th><th>' + T.live + '</th>' +
+                priceFields.map(f => '<th>' + f[1] + '</th>').join('') +
+                '</tr></thead>' +
+                '<tbody>' + rows + '</tbody>' +
+                '</table></div>' +
+                '<div class="toolbar">' +
+                '<button onclick="saveHouse(' + id + ')">' + T.save + '</button>' +
+                '<span id="total-' + id + '"></span>' +
+                '<span id="status-' + id + '"></span>' +
+                '</div>' +
+                '</div></div>';
+        }
+
+        // readHouse collects the settings entered for a house
+        function readHouse(id) {
+            const config = {};
+            document.querySelectorAll('input[data-house="' + id + '"]').forEach(function(input) {
+                const [field, index] = input.dataset.field.split('.');
+                const value = parseInt(input.value, 10) || 0;
+                if (index === undefined) {
+                    config[field] = value;
+                } else {
+                    config[field] = config[field] || [];
+                    config[field][parseInt(index, 10)] = value;
+                }
+            });
+            return config;
+        }
+
+        function updateTotal(id) {
+            const config = readHouse(id);
+            const total = config.trade_goods_percent.concat(config.items_percent).reduce((a, b) => a + b, 0);
+            const element = document.getElementById('total-' + id);
+            element.textContent = T.percentTotal.replace('{n}', total);
+            element.className = total === 100 ? '' : 'invalid';
+        }
+
+        async function saveHouse(id) {
+            const status = document.getElementById('status-' + id);
+            try {
+                await api('PUT', '/api/admin/ahbot/' + id, readHouse(id));
+                status.innerHTML = '<span class="success">' + T.saved + '</span>';
+                loadAudit();
+            } catch (error) {
+                status.innerHTML = '<span class="invalid">' + escapeHTML(error.message) + '</span>';
+            }
+        }
+
+        async function loadDisabledItems() {
+            const tbody = document.getElementById('disabledBody');
+            try {
+                const items = (await api('GET', '/api/admin/ahbot/disabled-items')).items;
+                if (items.length === 0) {
+                    tbody.innerHTML = '<tr><td colspan="3" class="loading">' + T.noDisabledItems + '</td></tr>';
+                    return;
+                }
+                tbody.innerHTML = items.map(item => '<tr>' +
+                    '<td>' + item.entry + '</td>' +
+                    '<td><a href="/items/' + item.entry + '">' + escapeHTML(item.name || '-') + '</a></td>' +
+                    '<td><button class="secondary" onclick="enableItem(' + item.entry + ')">' + T.remove + '</button></td>' +
+                    '</tr>').join('');
+            } catch (error) {
+                tbody.innerHTML = '<tr><td colspan="3" class="error">' + escapeHTML(error.message) + '</td></tr>';
+            }
+        }
+
+        async function disableItem(event) {
+            event.preventDefault();
+            const input = document.getElementById('disableEntry');
+            const errors = document.getElementById('disabledError');
+            try {
+                await api('POST', '/api/admin/ahbot/disabled-items', {entry: parseInt(input.value, 10)});
+                input.value = '';
+                errors.innerHTML = '';
+                loadDisabledItems();
+                loadAudit();
+            } catch (error) {
+                errors.innerHTML = '<div class="error">' + escapeHTML(error.message) + '</div>';
+            }
+        }
+
+        async function enableItem(entry) {
+            const errors = document.getElementById('disabledError');
+            try {
+                await api('DELETE', '/api/admin/ahbot/disabled-items/' + entry);
+                errors.innerHTML = '';
+                loadDisabledItems();
+                loadAudit();
+            } catch (error) {
+                errors.innerHTML = '<div class="error">' + escapeHTML(error.message) + '</div>';
+            }
+        }
+
+        async function loadAudit() {
+            const tbody = document.getElementById('auditBody');
+            try {
+                const entries = (await api('GET', '/api/admin/audit')).entries;
+                if (entries.length === 0) {
+                    tbody.innerHTML = '<tr><td colspan="4" class="loading">' + T.noAuditEntries + '</td></tr>';
+                    return;
+                }
+                tbody.innerHTML = entries.map(e => '<tr>' +
+                    '<td>' + new Date(e.time).toLocaleString() + '</td>' +
+                    '<td>' + escapeHTML(e.actor) + '</td>' +
+                    '<td>' + escapeHTML(e.action) + '</td>' +
+                    '<td>' + escapeHTML(e.target) + '</td>' +
+                    '</tr>').join('');
+            } catch (error) {
+                tbody.innerHTML = '<tr><td colspan="4" class="loading">' + T.auditUnavailable + '</td></tr>';
+            }
+        }
+
+        function escapeHTML(text) {
+            const div = document.createElement('div');
+            div.textContent = text;
+            return div.innerHTML;
+        }
+    </script>
+</body>
+</html>`
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// adminJSON serves an admin API request with a JSON body and the admin token
+func adminJSON(s *Server, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+// newAHBotServer returns a server whose bot is configured for the Alliance
+// auction house with mod_auctionhousebot's defaults
+func newAHBotServer(t *testing.T, audit store.AuditRepository) (*Server, *store.Memory) {
+	m := newTestStore()
+	m.AddAHBotConfig(store.AHBotConfig{
+		AuctionHouse:         wow.HouseAlliance,
+		Name:                 "Alliance",
+		MaxItems:             1000,
+		TradeGoodsPercent:    [store.AHBotQualities]int{0, 27, 12, 10, 1, 0, 0},
+		ItemsPercent:         [store.AHBotQualities]int{0, 10, 30, 8, 2, 0, 0},
+		MaxPrice:             [store.AHBotQualities]int{150, 250, 1400, 1750, 4550, 5550, 6550},
+		MaxBidPrice:          [store.AHBotQualities]int{100, 100, 100, 100, 100, 100, 100},
+		BuyerBiddingInterval: 1,
+	})
+	return newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m}), AdminToken: "s3cret", AHBot: m, Audit: audit}), m
+}
+
+func TestGetAHBot(t *testing.T) {
+	s, _ := newAHBotServer(t, nil)
+
+	if rec := adminRequest(s, http.MethodGet, "/api/admin/ahbot", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("without token: status = %d, want 401", rec.Code)
+	}
+
+	rec := adminRequest(s, http.MethodGet, "/api/realms/1/admin/ahbot", "s3cret")
+	var resp struct {
+		Houses []ahbotHouse `json:"houses"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, decoding: %v", rec.Code, err)
+	}
+	if len(resp.Houses) != 1 {
+		t.Fatalf("houses = %+v, want the Alliance house", resp.Houses)
+	}
+	// Two live Linen Cloth auctions, which are common trade goods
+	live := resp.Houses[0].Live
+	if live.House != wow.HouseAlliance || live.Total != 2 || live.TradeGoods[1] != 2 {
+		t.Errorf("live = %+v, want 2 common trade goods", live)
+	}
+
+	rec = adminRequest(s, http.MethodGet, "/admin", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "mod_auctionhousebot") {
+		t.Errorf("admin page status = %d", rec.Code)
+	}
+}
+
+func TestUpdateAHBot(t *testing.T) {
+	// Without an audit log, changes are refused
+	s, _ := newAHBotServer(t, nil)
+	if rec := adminJSON(s, http.MethodPut, "/api/admin/ahbot/alliance", `{"max_items": 500}`); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("without audit log: status = %d, want 503", rec.Code)
+	}
+
+	audit := store.NewMemory()
+	s, m := newAHBotServer(t, audit)
+	for body, want := range map[string]int{
+		`{"items_percent": [0, 10, 31, 8, 2, 0, 0]}`: http.StatusBadRequest,
+		`{"min_items": 2000}`:                        http.StatusBadRequest,
+		`{"max_items": "many"}`:                      http.StatusBadRequest,
+	} {
+		if rec := adminJSON(s, http.MethodPut, "/api/admin/ahbot/alliance", body); rec.Code != want {
+			t.Errorf("%s: status = %d, want %d", body, rec.Code, want)
+		}
+	}
+	if rec := adminJSON(s, http.MethodPut, "/api/admin/ahbot/horde", `{}`); rec.Code != http.StatusNotFound {
+		t.Errorf("unconfigured house: status = %d, want 404", rec.Code)
+	}
+
+	body := `{"max_items": 500, "trade_goods_percent": [0, 26, 12, 10, 1, 0, 0], "items_percent": [0, 11, 30, 8, 2, 0, 0], "name": "Renamed"}`
+	rec := adminJSON(s, http.MethodPut, "/api/admin/ahbot/2", body)
+	var config store.AHBotConfig
+	if err := json.NewDecoder(rec.Body).Decode(&config); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("update status = %d, decoding: %v", rec.Code, err)
+	}
+	saved, _ := m.AHBotConfig(t.Context(), wow.HouseAlliance)
+	if saved != config || saved.MaxItems != 500 || saved.ItemsPercent[1] != 11 || saved.MaxPrice[6] != 6550 || saved.Name != "Alliance" {
+		t.Errorf("saved config = %+v", saved)
+	}
+
+	entries, _ := audit.AuditLog(t.Context(), 10)
+	if len(entries) != 1 || entries[0].Action != auditAHBotUpdate || entries[0].Target != "house 2" || entries[0].RequestID == "" {
+		t.Fatalf("audit log = %+v, want the update", entries)
+	}
+	var before store.AHBotConfig
+	if err := json.Unmarshal(entries[0].Before, &before); err != nil || before.MaxItems != 1000 {
+		t.Errorf("audited before = %s", entries[0].Before)
+	}
+}
+
+func TestAHBotDisabledItems(t *testing.T) {
+	audit := store.NewMemory()
+	s, _ := newAHBotServer(t, audit)
+
+	for _, body := range []string{`{"entry": 0}`, `nonsense`} {
+		if rec := adminJSON(s, http.MethodPost, "/api/admin/ahbot/disabled-items", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", body, rec.Code)
+		}
+	}
+	for _, entry := range []string{"19019", "2589"} {
+		if rec := adminJSON(s, http.MethodPost, "/api/admin/ahbot/disabled-items", `{"entry": `+entry+`}`); rec.Code != http.StatusNoContent {
+			t.Errorf("disabling %s: status = %d", entry, rec.Code)
+		}
+	}
+	if rec := adminJSON(s, http.MethodDelete, "/api/admin/ahbot/disabled-items/19019", ""); rec.Code != http.StatusNoContent {
+		t.Errorf("enabling: status = %d", rec.Code)
+	}
+	if rec := adminJSON(s, http.MethodDelete, "/api/admin/ahbot/disabled-items/19019", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("enabling twice: status = %d, want 404", rec.Code)
+	}
+
+	rec := adminRequest(s, http.MethodGet, "/api/admin/ahbot/disabled-items", "s3cret")
+	var resp struct {
+		Items []store.DisabledItem `json:"items"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, decoding: %v", rec.Code, err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0] != (store.DisabledItem{Entry: 2589, Name: "Linen Cloth"}) {
+		t.Errorf("disabled items = %+v, want Linen Cloth", resp.Items)
+	}
+
+	rec = adminRequest(s, http.MethodGet, "/api/admin/audit?limit=2", "s3cret")
+	var log struct {
+		Entries []store.AuditEntry `json:"entries"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&log); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("audit status = %d, decoding: %v", rec.Code, err)
+	}
+	if len(log.Entries) != 2 || log.Entries[0].Action != auditAHBotEnable || log.Entries[1].Target != "item 2589" {
+		t.Errorf("audit log = %+v, want the latest two changes newest first", log.Entries)
+	}
+}
@@ -0,0 +1,279 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// defaultWebhookHosts are the hosts alert webhooks may target unless
+// configured otherwise: Discord's and Slack's
+var defaultWebhookHosts = []string{"discord.com", "discordapp.com", "hooks.slack.com"}
+
+// validateAlertRule checks a rule submitted by a client, returning a message
+// that is safe to show them
+func validateAlertRule(rule store.AlertRule, allowedHosts []string) error {
+	if rule.ItemEntry <= 0 && strings.TrimSpace(rule.NamePattern) == "" {
+		return errors.New("item_entry or name_pattern is required")
+	}
+	if rule.MaxUnitBuyout <= 0 {
+		return errors.New("max_unit_buyout must be greater than zero")
+	}
+	if rule.MinQuantity < 0 || rule.House < 0 {
+		return errors.New("min_quantity and house must not be negative")
+	}
+
+	u, err := url.Parse(rule.WebhookURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return errors.New("webhook_url must be an http or https URL")
+	}
+	for _, host := range allowedHosts {
+		if strings.EqualFold(u.Hostname(), host) {
+			return nil
+		}
+	}
+	return errors.New("webhook_url host is not allowed")
+}
+
+// decodeAlertRule reads and validates a rule from a request body. New rules
+// are enabled unless the body says otherwise. Responses never carry the
+// webhook URL, so a body without one keeps webhookURL, the rule's current
+// URL.
+func (s *Server) decodeAlertRule(r *http.Request, webhookURL string) (store.AlertRule, error) {
+	rule := store.AlertRule{Enabled: true}
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		return rule, errors.New("invalid JSON body")
+	}
+	if rule.WebhookURL == "" {
+		rule.WebhookURL = webhookURL
+	}
+	rule.Name = strings.TrimSpace(rule.Name)
+	rule.NamePattern = strings.TrimSpace(rule.NamePattern)
+	return rule, validateAlertRule(rule, s.webhookHosts)
+}
+
+// alertsAvailable reports whether the alert endpoints can be served, writing
+// an error response if not
+func alertsAvailable(w http.ResponseWriter, r *http.Request, rm *realm) bool {
+	if rm.Alerts == nil {
+		writeError(w, r, http.StatusServiceUnavailable, codeUnavailable, "Alerts are not available")
+		return false
+	}
+	return true
+}
+
+// alertCaller is who a request to the alert API comes from
+type alertCaller struct {
+	// accountID is the signed-in account, or 0 for the admin token
+	accountID int
+	// admin callers see and change every rule of the realm
+	admin bool
+}
+
+// owns reports whether the caller made rule
+func (c alertCaller) owns(rule store.AlertRule) bool {
+	return c.accountID != 0 && c.accountID == rule.AccountID
+}
+
+// canSee reports whether the caller may read and change rule
+func (c alertCaller) canSee(rule store.AlertRule) bool {
+	return c.admin || c.owns(rule)
+}
+
+// show returns rule as the caller may see it. Only its owner gets the
+// webhook URL, whose path is the token that posts to the channel.
+func (c alertCaller) show(rule store.AlertRule) store.AlertRule {
+	if !c.owns(rule) {
+		rule.WebhookURL = ""
+	}
+	return rule
+}
+
+// authorizeAlerts checks that a request may use the alert API and returns
+// who made it, writing an error response if not. It must carry the admin
+// token or come from a signed-in account, and changes made with a session
+// must come from this site's own pages. Accounts that may use the admin API
+// manage every rule; other accounts only their own.
+func (s *Server) authorizeAlerts(w http.ResponseWriter, r *http.Request) (alertCaller, bool) {
+	if s.hasAdminToken(r) {
+		return alertCaller{admin: true}, true
+	}
+	if sess, ok := s.session(r); ok {
+		if r.Method != http.MethodGet && !sameOrigin(r) {
+			writeError(w, r, http.StatusForbidden, codeForbidden, "Cross-origin request refused")
+			return alertCaller{}, false
+		}
+		return alertCaller{accountID: sess.AccountID, admin: s.isAdmin(sess)}, true
+	}
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	writeError(w, r, http.StatusUnauthorized, codeUnauthorized, "Sign in or an admin token is required")
+	return alertCaller{}, false
+}
+
+func alertRuleID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid alert id")
+		return 0, false
+	}
+	return id, true
+}
+
+// callerAlertRule returns the rule with the id in the path, writing an error
+// response if it is malformed, missing or another account's. Other accounts'
+// rules are reported missing, so their IDs do not give them away.
+func callerAlertRule(w http.ResponseWriter, r *http.Request, rm *realm, caller alertCaller) (store.AlertRule, bool) {
+	id, ok := alertRuleID(w, r)
+	if !ok {
+		return store.AlertRule{}, false
+	}
+	rule, err := rm.Alerts.AlertRule(r.Context(), id)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && !caller.canSee(rule)) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "Alert not found")
+		return rule, false
+	}
+	if err != nil {
+		writeQueryError(w, r, err)
+		return rule, false
+	}
+	return rule, true
+}
+
+func writeAlertRule(w http.ResponseWriter, status int, rule store.AlertRule) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(rule)
+}
+
+// handleListAlerts lists the rules of the realm the caller can see
+func (s *Server) handleListAlerts(w http.ResponseWriter, r *http.Request, rm *realm) {
+	if !alertsAvailable(w, r, rm) {
+		return
+	}
+	caller, ok := s.authorizeAlerts(w, r)
+	if !ok {
+		return
+	}
+
+	rules, err := rm.Alerts.AlertRules(r.Context(), false)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	visible := []store.AlertRule{}
+	for _, rule := range rules {
+		if caller.canSee(rule) {
+			visible = append(visible, caller.show(rule))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"alerts": visible,
+	})
+}
+
+func (s *Server) handleGetAlert(w http.ResponseWriter, r *http.Request, rm *realm) {
+	if !alertsAvailable(w, r, rm) {
+		return
+	}
+	caller, ok := s.authorizeAlerts(w, r)
+	if !ok {
+		return
+	}
+	rule, ok := callerAlertRule(w, r, rm, caller)
+	if !ok {
+		return
+	}
+	writeAlertRule(w, http.StatusOK, caller.show(rule))
+}
+
+// handleCreateAlert stores a new rule owned by the signed-in account
+func (s *Server) handleCreateAlert(w http.ResponseWriter, r *http.Request, rm *realm) {
+	if !alertsAvailable(w, r, rm) {
+		return
+	}
+	caller, ok := s.authorizeAlerts(w, r)
+	if !ok {
+		return
+	}
+
+	rule, err := s.decodeAlertRule(r, "")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+	rule.AccountID = caller.accountID
+
+	rule, err = rm.Alerts.CreateAlertRule(r.Context(), rule)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	writeAlertRule(w, http.StatusCreated, caller.show(rule))
+}
+
+// handleUpdateAlert replaces a rule. Deliveries already made are kept, so
+// tightening a rule does not re-send auctions it already fired for.
+func (s *Server) handleUpdateAlert(w http.ResponseWriter, r *http.Request, rm *realm) {
+	if !alertsAvailable(w, r, rm) {
+		return
+	}
+	caller, ok := s.authorizeAlerts(w, r)
+	if !ok {
+		return
+	}
+	current, ok := callerAlertRule(w, r, rm, caller)
+	if !ok {
+		return
+	}
+
+	rule, err := s.decodeAlertRule(r, current.WebhookURL)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+	rule.ID = current.ID
+	rule.AccountID = current.AccountID
+
+	rule, err = rm.Alerts.UpdateAlertRule(r.Context(), rule)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "Alert not found")
+		return
+	}
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	writeAlertRule(w, http.StatusOK, caller.show(rule))
+}
+
+func (s *Server) handleDeleteAlert(w http.ResponseWriter, r *http.Request, rm *realm) {
+	if !alertsAvailable(w, r, rm) {
+		return
+	}
+	caller, ok := s.authorizeAlerts(w, r)
+	if !ok {
+		return
+	}
+	rule, ok := callerAlertRule(w, r, rm, caller)
+	if !ok {
+		return
+	}
+
+	err := rm.Alerts.DeleteAlertRule(r.Context(), rule.ID)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "Alert not found")
+		return
+	}
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,249 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// send serves a request with a JSON body and decodes a JSON response into v,
+// failing the test unless the status is want
+func send(t *testing.T, h http.Handler, method, target, body string, want int, v interface{}) {
+	t.Helper()
+	sendAs(t, h, nil, method, target, body, want, v)
+}
+
+// sendAs is send with the request authorized by auth, such as withAdminToken,
+// unless it is nil
+func sendAs(t *testing.T, h http.Handler, auth func(*http.Request), method, target, body string, want int, v interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if auth != nil {
+		auth(req)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != want {
+		t.Fatalf("%s %s status = %d, want %d; body: %s", method, target, rec.Code, want, rec.Body.String())
+	}
+	if v != nil {
+		if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s decoding response: %v", method, target, err)
+		}
+	}
+}
+
+// withAdminToken authorizes a request with the admin token of the alert test
+// servers
+func withAdminToken(r *http.Request) {
+	r.Header.Set("Authorization", "Bearer s3cret")
+}
+
+// withSession authorizes a request with a session cookie
+func withSession(cookie *http.Cookie) func(*http.Request) {
+	return func(r *http.Request) {
+		r.AddCookie(cookie)
+	}
+}
+
+func TestAlertsUnavailableWithoutAppDatabase(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	get(t, s, "/api/alerts", http.StatusServiceUnavailable, nil)
+	send(t, s, http.MethodPost, "/api/alerts", `{}`, http.StatusServiceUnavailable, nil)
+}
+
+func TestAlertLifecycle(t *testing.T) {
+	m := newTestStore()
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m, Alerts: m}), AdminToken: "s3cret"})
+
+	var created store.AlertRule
+	sendAs(t, s, withAdminToken, http.MethodPost, "/api/alerts",
+		`{"name":" Cheap linen ","name_pattern":"linen","max_unit_buyout":100,"webhook_url":"https://discord.com/api/webhooks/1/x"}`,
+		http.StatusCreated, &created)
+	if created.ID == 0 || created.Name != "Cheap linen" || !created.Enabled {
+		t.Errorf("created = %+v, want an enabled rule with a trimmed name", created)
+	}
+	if created.WebhookURL != "" {
+		t.Errorf("created webhook URL = %q, want it left out", created.WebhookURL)
+	}
+
+	var list struct {
+		Alerts []store.AlertRule `json:"alerts"`
+	}
+	sendAs(t, s, withAdminToken, http.MethodGet, "/api/alerts", "", http.StatusOK, &list)
+	if len(list.Alerts) != 1 || list.Alerts[0].ID != created.ID || list.Alerts[0].WebhookURL != "" {
+		t.Errorf("alerts = %+v, want the rule without its webhook URL", list.Alerts)
+	}
+
+	// A rule replaced without a webhook URL keeps the one it had
+	var updated store.AlertRule
+	sendAs(t, s, withAdminToken, http.MethodPut, "/api/alerts/1",
+		`{"item_entry":2589,"max_unit_buyout":95,"enabled":false}`,
+		http.StatusOK, &updated)
+	if updated.ItemEntry != 2589 || updated.MaxUnitBuyout != 95 || updated.Enabled {
+		t.Errorf("updated = %+v", updated)
+	}
+	if rule, err := m.AlertRule(context.Background(), 1); err != nil || rule.WebhookURL != "https://discord.com/api/webhooks/1/x" {
+		t.Errorf("stored rule = %+v, %v, want the webhook URL kept", rule, err)
+	}
+
+	var fetched store.AlertRule
+	sendAs(t, s, withAdminToken, http.MethodGet, "/api/alerts/1", "", http.StatusOK, &fetched)
+	if fetched.MaxUnitBuyout != 95 {
+		t.Errorf("fetched = %+v, want the update applied", fetched)
+	}
+
+	sendAs(t, s, withAdminToken, http.MethodDelete, "/api/alerts/1", "", http.StatusNoContent, nil)
+	sendAs(t, s, withAdminToken, http.MethodGet, "/api/alerts/1", "", http.StatusNotFound, nil)
+	sendAs(t, s, withAdminToken, http.MethodDelete, "/api/alerts/1", "", http.StatusNotFound, nil)
+	sendAs(t, s, withAdminToken, http.MethodPut, "/api/alerts/1",
+		`{"item_entry":2589,"max_unit_buyout":95,"webhook_url":"https://discord.com/api/webhooks/1/x"}`,
+		http.StatusNotFound, nil)
+	sendAs(t, s, withAdminToken, http.MethodGet, "/api/alerts/abc", "", http.StatusBadRequest, nil)
+}
+
+func TestAlertsRequireAuthorization(t *testing.T) {
+	m := newTestStore()
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m, Alerts: m}), AdminToken: "s3cret"})
+	sendAs(t, s, withAdminToken, http.MethodPost, "/api/alerts",
+		`{"item_entry":2589,"max_unit_buyout":100,"webhook_url":"https://discord.com/api/webhooks/1/x"}`,
+		http.StatusCreated, nil)
+
+	wrongToken := func(r *http.Request) { r.Header.Set("Authorization", "Bearer wrong") }
+	for _, auth := range []func(*http.Request){nil, wrongToken} {
+		sendAs(t, s, auth, http.MethodGet, "/api/alerts", "", http.StatusUnauthorized, nil)
+		sendAs(t, s, auth, http.MethodGet, "/api/alerts/1", "", http.StatusUnauthorized, nil)
+		sendAs(t, s, auth, http.MethodPost, "/api/alerts",
+			`{"item_entry":2589,"max_unit_buyout":100,"webhook_url":"https://discord.com/api/webhooks/2/y"}`,
+			http.StatusUnauthorized, nil)
+		sendAs(t, s, auth, http.MethodPut, "/api/alerts/1", `{"item_entry":2589,"max_unit_buyout":1}`, http.StatusUnauthorized, nil)
+		sendAs(t, s, auth, http.MethodDelete, "/api/alerts/1", "", http.StatusUnauthorized, nil)
+	}
+	if rules, _ := m.AlertRules(context.Background(), false); len(rules) != 1 || rules[0].MaxUnitBuyout != 100 {
+		t.Errorf("rules = %+v, want the one rule unchanged", rules)
+	}
+}
+
+func TestAlertsWithSession(t *testing.T) {
+	s := newAccountServer(t, nil)
+	player := withSession(login(t, s, "player", "playerpass", http.StatusOK))
+
+	body := `{"item_entry":2589,"max_unit_buyout":100,"webhook_url":"https://discord.com/api/webhooks/1/x"}`
+	var created store.AlertRule
+	sendAs(t, s, player, http.MethodPost, "/api/alerts", body, http.StatusCreated, &created)
+	if created.AccountID != 1 || created.WebhookURL == "" {
+		t.Errorf("created = %+v, want the player's rule with its webhook URL", created)
+	}
+	sendAs(t, s, player, http.MethodGet, "/api/alerts/1", "", http.StatusOK, nil)
+
+	// Other sites' pages cannot make changes with the player's cookie
+	crossOrigin := func(r *http.Request) {
+		player(r)
+		r.Header.Set("Origin", "https://elsewhere.example")
+	}
+	sendAs(t, s, crossOrigin, http.MethodPost, "/api/alerts", body, http.StatusForbidden, nil)
+	sendAs(t, s, crossOrigin, http.MethodDelete, "/api/alerts/1", "", http.StatusForbidden, nil)
+}
+
+func TestAlertsBelongToAccounts(t *testing.T) {
+	s := newAccountServer(t, nil)
+	player := withSession(login(t, s, "player", "playerpass", http.StatusOK))
+	visitor := withSession(login(t, s, "visitor", "visitorpass", http.StatusOK))
+	gm := withSession(login(t, s, "gm", "gmpass", http.StatusOK))
+
+	sendAs(t, s, player, http.MethodPost, "/api/alerts",
+		`{"item_entry":2589,"max_unit_buyout":100,"webhook_url":"https://discord.com/api/webhooks/1/x","account_id":4}`,
+		http.StatusCreated, nil)
+	sendAs(t, s, visitor, http.MethodPost, "/api/alerts",
+		`{"item_entry":2592,"max_unit_buyout":100,"webhook_url":"https://discord.com/api/webhooks/2/y"}`,
+		http.StatusCreated, nil)
+
+	var list struct {
+		Alerts []store.AlertRule `json:"alerts"`
+	}
+	for _, tt := range []struct {
+		name    string
+		auth    func(*http.Request)
+		want    []int
+		webhook bool
+	}{
+		{"player", player, []int{1}, true},
+		{"visitor", visitor, []int{2}, true},
+		{"game master", gm, []int{1, 2}, false},
+	} {
+		list.Alerts = nil
+		sendAs(t, s, tt.auth, http.MethodGet, "/api/alerts", "", http.StatusOK, &list)
+		var got []int
+		for _, rule := range list.Alerts {
+			got = append(got, rule.ID)
+			if (rule.WebhookURL != "") != tt.webhook {
+				t.Errorf("%s: rule %d webhook URL = %q", tt.name, rule.ID, rule.WebhookURL)
+			}
+		}
+		if !equalIDs(got, tt.want) {
+			t.Errorf("%s: alert IDs = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Another account's rule is not found, and is left alone
+	sendAs(t, s, visitor, http.MethodGet, "/api/alerts/1", "", http.StatusNotFound, nil)
+	sendAs(t, s, visitor, http.MethodPut, "/api/alerts/1", `{"item_entry":2589,"max_unit_buyout":1}`, http.StatusNotFound, nil)
+	sendAs(t, s, visitor, http.MethodDelete, "/api/alerts/1", "", http.StatusNotFound, nil)
+
+	// Game masters manage every rule without taking it over
+	var updated store.AlertRule
+	sendAs(t, s, gm, http.MethodPut, "/api/alerts/1", `{"item_entry":2589,"max_unit_buyout":50}`, http.StatusOK, &updated)
+	if updated.AccountID != 1 || updated.MaxUnitBuyout != 50 {
+		t.Errorf("updated = %+v, want the player's rule at 50", updated)
+	}
+	sendAs(t, s, gm, http.MethodDelete, "/api/alerts/2", "", http.StatusNoContent, nil)
+	sendAs(t, s, visitor, http.MethodGet, "/api/alerts", "", http.StatusOK, &list)
+	if len(list.Alerts) != 0 {
+		t.Errorf("visitor alerts = %+v, want none", list.Alerts)
+	}
+}
+
+func TestCreateAlertValidation(t *testing.T) {
+	m := newTestStore()
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m, Alerts: m}), WebhookHosts: []string{"discord.com"}, AdminToken: "s3cret"})
+
+	for _, body := range []string{
+		`not json`,
+		`{"max_unit_buyout":100,"webhook_url":"https://discord.com/x"}`,
+		`{"item_entry":2589,"webhook_url":"https://discord.com/x"}`,
+		`{"item_entry":2589,"max_unit_buyout":100,"min_quantity":-1,"webhook_url":"https://discord.com/x"}`,
+		`{"item_entry":2589,"max_unit_buyout":100,"webhook_url":"ftp://discord.com/x"}`,
+		`{"item_entry":2589,"max_unit_buyout":100,"webhook_url":"https://example.com/x"}`,
+	} {
+		sendAs(t, s, withAdminToken, http.MethodPost, "/api/alerts", body, http.StatusBadRequest, nil)
+	}
+
+	sendAs(t, s, withAdminToken, http.MethodPost, "/api/alerts",
+		`{"item_entry":2589,"max_unit_buyout":100,"webhook_url":"https://DISCORD.com/x"}`,
+		http.StatusCreated, nil)
+}
+
+func TestDefaultWebhookHosts(t *testing.T) {
+	m := newTestStore()
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m, Alerts: m}), AdminToken: "s3cret"})
+
+	for _, tt := range []struct {
+		url  string
+		want int
+	}{
+		{"https://discord.com/api/webhooks/1/x", http.StatusCreated},
+		{"https://discordapp.com/api/webhooks/1/x", http.StatusCreated},
+		{"https://hooks.slack.com/services/T/B/x", http.StatusCreated},
+		{"http://127.0.0.1:8080/internal", http.StatusBadRequest},
+		{"https://example.com/hook", http.StatusBadRequest},
+	} {
+		body := `{"item_entry":2589,"max_unit_buyout":100,"webhook_url":"` + tt.url + `"}`
+		sendAs(t, s, withAdminToken, http.MethodPost, "/api/alerts", body, tt.want, nil)
+	}
+}
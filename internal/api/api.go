@@ -0,0 +1,310 @@
+// Package api serves the web interface and JSON API on top of the store
+// repositories.
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/metrics"
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// Config holds the dependencies and settings of a Server
+type Config struct {
+	// Realms are served under /api/realms/{id}. The first is the default
+	// realm, which the API paths without a realm also serve.
+	Realms []Realm
+
+	// DBStats reports the connection pools of the game databases, by
+	// database name, for /metrics when set
+	DBStats func() map[string]sql.DBStats
+	// WebhookHosts lists the hosts alert webhooks may target; empty means
+	// Discord's and Slack's
+	WebhookHosts []string
+	// BaseURL is the public URL of the site, used for links in Discord
+	// messages
+	BaseURL string
+	// DiscordPublicKey is the hex-encoded key of the Discord application.
+	// The interactions endpoint is only served when it is set.
+	DiscordPublicKey string
+	// StreamInterval is how often /api/stream polls the auctions of a realm
+	// while clients are connected; zero means every 10 seconds
+	StreamInterval time.Duration
+	// DealPercent is the highest buyout, as a percentage of the market
+	// price, that /api/deals lists by default; zero means 80
+	DealPercent int
+
+	// Items is the item template cache shared by the realms, reported by
+	// /metrics and reloaded through the admin API when set
+	Items store.ItemCatalog
+	// Tooltips describes items for the tooltip endpoints, which are only
+	// served when it is set
+	Tooltips store.TooltipRepository
+	// Crafting reads the professions and recipes the crafting calculator
+	// prices, which is only served when it is set
+	Crafting store.CraftingRepository
+	// Yields reads the loot templates /api/yields values listings by, which
+	// is only served when it is set
+	Yields store.YieldRepository
+	// Sources reads where items come from for /api/items/{entry}/sources,
+	// which is only served when it is set
+	Sources store.SourceRepository
+	// AdminToken is the bearer token of the admin API, which is only served
+	// when it is set
+	AdminToken string
+	// AHBot is the configuration of mod_auctionhousebot, which the admin
+	// API and the /admin page edit when set
+	AHBot store.AHBotRepository
+	// Auctionator is the configuration of mod_auctionator, which the admin
+	// API and the /admin/auctionator page edit when set
+	Auctionator store.AuctionatorRepository
+	// Audit records the changes made through the admin API. Without it,
+	// changes are refused.
+	Audit store.AuditRepository
+
+	// Accounts are the game accounts players sign in with. Signing in is
+	// only offered when it is set.
+	Accounts store.AccountRepository
+	// SessionKey signs the session cookies and is required with Accounts
+	SessionKey []byte
+	// SessionTTL is how long a sign-in lasts; zero means 24 hours
+	SessionTTL time.Duration
+	// AdminGMLevel is the lowest gmlevel of the accounts that may use the
+	// admin features; zero means 2, game masters
+	AdminGMLevel int
+	// AdminRealm is the realm whose account_access levels, along with those
+	// granted on every realm, count toward AdminGMLevel; zero means the
+	// default realm
+	AdminRealm int
+}
+
+// Server routes requests to the handlers
+type Server struct {
+	realms       []*realm
+	dbStats      func() map[string]sql.DBStats
+	webhookHosts []string
+	baseURL      string
+	items        store.ItemCatalog
+	tooltips     store.TooltipRepository
+	crafting     store.CraftingRepository
+	yields       store.YieldRepository
+	yieldCache   yieldCache
+	sources      store.SourceRepository
+	adminToken   string
+	ahbot        store.AHBotRepository
+	auctionator  store.AuctionatorRepository
+	audit        store.AuditRepository
+	dealPercent  int
+	accounts     store.AccountRepository
+	sessionKey   []byte
+	sessionTTL   time.Duration
+	adminGMLevel int
+	adminRealm   int
+
+	mux *http.ServeMux
+}
+
+// New returns a Server for cfg. It fails if no realm is configured, realm
+// IDs repeat, the Discord public key is malformed or accounts lack a session
+// key.
+func New(cfg Config) (*Server, error) {
+	s := &Server{
+		dbStats:      cfg.DBStats,
+		webhookHosts: cfg.WebhookHosts,
+		baseURL:      cfg.BaseURL,
+		items:        cfg.Items,
+		tooltips:     cfg.Tooltips,
+		crafting:     cfg.Crafting,
+		yields:       cfg.Yields,
+		sources:      cfg.Sources,
+		adminToken:   cfg.AdminToken,
+		ahbot:        cfg.AHBot,
+		auctionator:  cfg.Auctionator,
+		audit:        cfg.Audit,
+		dealPercent:  cfg.DealPercent,
+		accounts:     cfg.Accounts,
+		sessionKey:   cfg.SessionKey,
+		sessionTTL:   cfg.SessionTTL,
+		adminGMLevel: cfg.AdminGMLevel,
+		adminRealm:   cfg.AdminRealm,
+		mux:          http.NewServeMux(),
+	}
+	if s.dealPercent <= 0 {
+		s.dealPercent = defaultDealPercent
+	}
+	if len(s.webhookHosts) == 0 {
+		s.webhookHosts = defaultWebhookHosts
+	}
+	if s.sessionTTL <= 0 {
+		s.sessionTTL = defaultSessionTTL
+	}
+	if s.adminGMLevel <= 0 {
+		s.adminGMLevel = defaultAdminGMLevel
+	}
+	if s.accounts != nil && len(s.sessionKey) == 0 {
+		return nil, errors.New("accounts configured without a session key")
+	}
+	if len(cfg.Realms) == 0 {
+		return nil, errors.New("no realms configured")
+	}
+	interval := cfg.StreamInterval
+	if interval <= 0 {
+		interval = defaultStreamInterval
+	}
+	seen := make(map[int]bool)
+	for _, r := range cfg.Realms {
+		if seen[r.ID] {
+			return nil, fmt.Errorf("realm %d configured twice", r.ID)
+		}
+		seen[r.ID] = true
+		rm := &realm{Realm: r}
+		rm.feed = auctionFeed{realm: r.Name, auctions: r.Auctions, interval: interval, now: time.Now}
+		s.realms = append(s.realms, rm)
+	}
+	if s.adminRealm == 0 {
+		s.adminRealm = s.realms[0].ID
+	}
+
+	s.mux.HandleFunc("GET /{$}", s.handleHome)
+	s.mux.HandleFunc("GET /items/{entry}", s.handleItemPage)
+	s.mux.HandleFunc("GET /metrics", s.handleMetrics)
+	s.mux.HandleFunc("GET /api/realms", s.handleGetRealms)
+	s.mux.HandleFunc("GET /api/realms/stats", s.handleGetRealmStats)
+
+	s.handleRealm("GET /api/auctions", s.handleGetAuctions)
+	s.handleRealm("GET /api/stats", s.handleGetStats)
+	s.handleRealm("GET /api/stream", s.handleStream)
+	s.handleRealm("GET /api/search", s.handleSearch)
+	s.handleRealm("GET /api/sellers", s.handleGetSellers)
+	s.handleRealm("GET /api/houses", s.handleGetHouses)
+	s.handleRealm("GET /api/deals", s.handleGetDeals)
+	s.handleRealm("GET /api/vendor-flips", s.handleGetVendorFlips)
+	s.handleRealm("GET /api/market-comparison", s.handleGetMarketComparison)
+	s.handleRealm("GET /api/items/{entry}", s.handleGetItem)
+	s.handleRealm("GET /api/items/{entry}/history", s.handleGetItemHistory)
+	if s.sources != nil {
+		s.handleRealm("GET /api/items/{entry}/sources", s.handleGetItemSources)
+	}
+	if s.tooltips != nil {
+		s.handleRealm("GET /api/items/{entry}/tooltip", s.handleGetItemTooltip)
+		s.handleRealm("GET /api/auctions/{id}/tooltip", s.handleGetAuctionTooltip)
+	}
+	if s.crafting != nil {
+		s.mux.HandleFunc("GET /api/crafting", s.handleGetProfessions)
+		s.handleRealm("GET /api/crafting/{skill}", s.handleGetCrafting)
+	}
+	if s.yields != nil {
+		s.handleRealm("GET /api/yields", s.handleGetYields)
+	}
+	s.handleRealm("GET /api/alerts", s.handleListAlerts)
+	s.handleRealm("POST /api/alerts", s.handleCreateAlert)
+	s.handleRealm("GET /api/alerts/{id}", s.handleGetAlert)
+	s.handleRealm("PUT /api/alerts/{id}", s.handleUpdateAlert)
+	s.handleRealm("DELETE /api/alerts/{id}", s.handleDeleteAlert)
+
+	// Discord slash commands, when configured
+	if cfg.DiscordPublicKey != "" {
+		handler, err := s.newDiscordHandler(cfg.DiscordPublicKey)
+		if err != nil {
+			return nil, err
+		}
+		s.mux.HandleFunc("POST /api/discord/interactions", handler)
+	}
+
+	// Sign-in with game accounts, when configured
+	if s.accounts != nil {
+		s.mux.HandleFunc("GET /login", s.handleLoginPage)
+		s.mux.HandleFunc("POST /api/login", s.handleLogin)
+		s.mux.HandleFunc("POST /api/logout", s.handleLogout)
+		s.mux.HandleFunc("GET /api/account", s.handleGetAccount)
+		s.handleRealm("GET /api/my-auctions", s.handleGetMyAuctions)
+	}
+
+	// Admin API, when configured. Game masters can use it when signing in
+	// is offered, with or without a token.
+	admin := s.adminToken != "" || s.accounts != nil
+	if admin && s.items != nil {
+		s.mux.HandleFunc("GET /api/admin/items", s.requireAdmin(s.handleGetItemCache))
+		s.mux.HandleFunc("POST /api/admin/items/refresh", s.requireAdmin(s.handleRefreshItemCache))
+	}
+	if admin && s.ahbot != nil {
+		s.mux.HandleFunc("GET /admin", s.handleAdminPage)
+		s.handleRealm("GET /api/admin/ahbot", s.requireAdminRealm(s.handleGetAHBot))
+		s.mux.HandleFunc("PUT /api/admin/ahbot/{house}", s.requireAdmin(s.handleUpdateAHBot))
+		s.mux.HandleFunc("GET /api/admin/ahbot/disabled-items", s.requireAdmin(s.handleGetDisabledItems))
+		s.mux.HandleFunc("POST /api/admin/ahbot/disabled-items", s.requireAdmin(s.handleDisableItem))
+		s.mux.HandleFunc("DELETE /api/admin/ahbot/disabled-items/{entry}", s.requireAdmin(s.handleEnableItem))
+	}
+	if admin && s.auctionator != nil {
+		s.mux.HandleFunc("GET /admin/auctionator", s.handleAuctionatorPage)
+		s.mux.HandleFunc("GET /api/admin/auctionator/classes", s.requireAdmin(s.handleGetAuctionatorClasses))
+		s.mux.HandleFunc("PUT /api/admin/auctionator/classes/{class}/{subclass}", s.requireAdmin(s.handleUpdateAuctionatorClass))
+		s.mux.HandleFunc("GET /api/admin/auctionator/disabled-items", s.requireAdmin(s.handleGetAuctionatorDisabledItems))
+		s.mux.HandleFunc("POST /api/admin/auctionator/disabled-items", s.requireAdmin(s.handleDisableAuctionatorItem))
+		s.mux.HandleFunc("DELETE /api/admin/auctionator/disabled-items/{entry}", s.requireAdmin(s.handleEnableAuctionatorItem))
+	}
+	if admin && s.audit != nil {
+		s.mux.HandleFunc("GET /api/admin/audit", s.requireAdmin(s.handleGetAudit))
+	}
+	return s, nil
+}
+
+// ServeHTTP counts and times every request by the ServeMux pattern that
+// handled it, which keeps label cardinality bounded regardless of the paths
+// requested. Every request is given an ID, see withRequestID.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	r = withRequestID(w, r)
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	if _, pattern := s.mux.Handler(r); pattern == "" {
+		s.mux.ServeHTTP(&unmatchedWriter{ResponseWriter: rec, r: r}, r)
+	} else {
+		s.mux.ServeHTTP(rec, r)
+	}
+
+	route := r.Pattern
+	if route == "" {
+		route = "unmatched"
+	}
+	metrics.HTTPRequests.Inc(route, r.Method, strconv.Itoa(rec.status))
+	metrics.HTTPDuration.Observe(time.Since(start).Seconds(), route)
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+// Flush lets streaming handlers flush through the recorder
+func (rec *statusRecorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// renderPage executes an HTML page template, logging failures. The response
+// has usually been partly written by then, so there is nothing to report.
+func renderPage(w http.ResponseWriter, r *http.Request, page *template.Template, data interface{}) {
+	if err := page.Execute(w, data); err != nil {
+		log.Printf("[%s] rendering %s page: %v", requestID(r), page.Name(), err)
+	}
+}
+
+// parseHouse reads the house query parameter, see wow.ParseHouse
+func parseHouse(r *http.Request) (int, error) {
+	return wow.ParseHouse(r.URL.Query().Get("house"))
+}
@@ -0,0 +1,504 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// now is the fixed clock of test stores
+var now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// newTestStore returns a store holding a handful of items and auctions across
+// the three houses
+func newTestStore() *store.Memory {
+	m := store.NewMemory()
+	m.Now = func() time.Time { return now }
+
+	m.AddItem(store.ItemTemplate{Entry: 2589, Name: "Linen Cloth", Quality: 1, ItemLevel: 5, Class: 7, Stackable: 20, SellPrice: 13})
+	m.AddItem(store.ItemTemplate{Entry: 2592, Name: "Wool Cloth", Quality: 1, ItemLevel: 15, Class: 7, Stackable: 20, SellPrice: 33})
+	m.AddItem(store.ItemTemplate{Entry: 19019, Name: "Thunderfury, Blessed Blade of the Windseeker", Quality: 5, ItemLevel: 80, RequiredLevel: 60, Class: 2})
+
+	hours := func(h int) int { return int(now.Add(time.Duration(h) * time.Hour).Unix()) }
+	m.AddAuction(store.AuctionItem{ID: 1, HouseID: wow.HouseAlliance, ItemOwner: 10, OwnerName: "Alice", ItemEntry: 2589, Count: 20, BuyoutPrice: 2000, StartBid: 1000, Time: hours(2)})
+	m.AddAuction(store.AuctionItem{ID: 2, HouseID: wow.HouseAlliance, ItemOwner: 10, OwnerName: "Alice", ItemEntry: 2589, Count: 10, BuyoutPrice: 1500, StartBid: 1000, Time: hours(24)})
+	m.AddAuction(store.AuctionItem{ID: 3, HouseID: wow.HouseHorde, ItemOwner: 11, OwnerName: "Bob", ItemEntry: 2592, Count: 5, BuyoutPrice: 0, StartBid: 500, LastBid: 600, Time: hours(1)})
+	m.AddAuction(store.AuctionItem{ID: 4, HouseID: wow.HouseNeutral, ItemOwner: 12, OwnerName: "Carol", ItemEntry: 19019, Count: 1, BuyoutPrice: 50000000, StartBid: 40000000, Time: hours(12)})
+	m.AddAuction(store.AuctionItem{ID: 5, HouseID: wow.HouseHorde, ItemOwner: 11, OwnerName: "Bob", ItemEntry: 2589, Count: 1, BuyoutPrice: 90, StartBid: 50, Time: hours(6)})
+	// Expired auctions are never listed
+	m.AddAuction(store.AuctionItem{ID: 6, HouseID: wow.HouseAlliance, ItemOwner: 12, OwnerName: "Carol", ItemEntry: 2592, Count: 1, BuyoutPrice: 10, Time: hours(-1)})
+
+	m.AddHouse(store.AuctionHouse{ID: wow.HouseHorde, Name: "Horde Auction House", FactionID: 6, DepositRate: 15, ConsignmentRate: 5})
+	m.AddHouse(store.AuctionHouse{ID: wow.HouseAlliance, Name: "Alliance Auction House", FactionID: 2, DepositRate: 15, ConsignmentRate: 5})
+	return m
+}
+
+// testRealms returns realm as the only realm, with ID 1
+func testRealms(realm Realm) []Realm {
+	realm.ID, realm.Name = 1, "Test Realm"
+	return []Realm{realm}
+}
+
+func newTestServer(t *testing.T, cfg Config) *Server {
+	t.Helper()
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return s
+}
+
+// newStoreServer returns a server of one realm reading the test store after
+// fill, unless nil, adds to it. configure, unless nil, sets the optional
+// repositories a test serves from the store, which implements them all.
+func newStoreServer(t *testing.T, fill func(m *store.Memory), configure func(cfg *Config, m *store.Memory)) *Server {
+	t.Helper()
+	m := newTestStore()
+	if fill != nil {
+		fill(m)
+	}
+	cfg := Config{Realms: testRealms(Realm{Auctions: m})}
+	if configure != nil {
+		configure(&cfg, m)
+	}
+	return newTestServer(t, cfg)
+}
+
+// get serves a GET request and decodes a JSON response into v, failing the
+// test unless the status is want
+func get(t *testing.T, h http.Handler, target string, want int, v interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != want {
+		t.Fatalf("GET %s status = %d, want %d; body: %s", target, rec.Code, want, rec.Body.String())
+	}
+	if v != nil {
+		if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+			t.Fatalf("GET %s decoding response: %v", target, err)
+		}
+	}
+	return rec
+}
+
+type auctionsResponse struct {
+	Auctions   []store.AuctionItem `json:"auctions"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	Total      int                 `json:"total"`
+	NextCursor string              `json:"next_cursor"`
+	Sort       string              `json:"sort"`
+	Order      string              `json:"order"`
+	House      int                 `json:"house"`
+	Search     string              `json:"search"`
+}
+
+// ids returns the IDs of a list, such as auctions or the listings that embed
+// them, as id reads them
+func ids[T any](list []T, id func(T) int) []int {
+	ids := make([]int, len(list))
+	for i, v := range list {
+		ids[i] = id(v)
+	}
+	return ids
+}
+
+func auctionIDs(auctions []store.AuctionItem) []int {
+	return ids(auctions, func(a store.AuctionItem) int { return a.ID })
+}
+
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGetAuctions(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	var resp auctionsResponse
+	get(t, s, "/api/auctions", http.StatusOK, &resp)
+
+	if got, want := auctionIDs(resp.Auctions), []int{3, 1, 5, 4, 2}; !equalIDs(got, want) {
+		t.Errorf("auction IDs = %v, want %v (ending soonest first)", got, want)
+	}
+	if resp.Total != 5 || resp.Page != 1 || resp.Limit != defaultPageSize || resp.Sort != "time" || resp.Order != "asc" {
+		t.Errorf("unexpected paging fields: %+v", resp)
+	}
+	if resp.NextCursor != "" {
+		t.Errorf("next_cursor = %q on the only page", resp.NextCursor)
+	}
+
+	first := resp.Auctions[1]
+	if first.ItemName != "Linen Cloth" || first.Quality != 1 || first.OwnerName != "Alice" {
+		t.Errorf("auction not joined with item and owner: %+v", first)
+	}
+	if first.UnitBuyout != 100 || first.TimeLeft != "2h 0m" {
+		t.Errorf("derived fields = %d, %q; want 100, \"2h 0m\"", first.UnitBuyout, first.TimeLeft)
+	}
+}
+
+func TestGetAuctionsFilters(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"house=alliance", []int{1, 2}},
+		{"house=6", []int{3, 5}},
+		{"entry=2589&sort=unit_buyout", []int{5, 1, 2}},
+		{"quality_min=2", []int{4}},
+		{"class=7&count_min=10", []int{1, 2}},
+		{"has_bid=true", []int{3}},
+		{"time_left=short", nil},
+		{"time_left=medium,very_long", []int{3, 4, 2}},
+		{"unit_buyout_max=100", []int{1, 5}},
+		{"seller=bob", []int{3, 5}},
+		{"sort=buyout", []int{5, 2, 1, 4, 3}},
+		{"sort=buyout&order=desc", []int{3, 4, 1, 2, 5}},
+		{"sort=name&order=asc", []int{1, 2, 5, 4, 3}},
+	}
+	for _, tt := range tests {
+		var resp auctionsResponse
+		get(t, s, "/api/auctions?"+tt.query, http.StatusOK, &resp)
+		if got := auctionIDs(resp.Auctions); !equalIDs(got, tt.want) {
+			t.Errorf("%s: auction IDs = %v, want %v", tt.query, got, tt.want)
+		}
+		if resp.Total != len(tt.want) {
+			t.Errorf("%s: total = %d, want %d", tt.query, resp.Total, len(tt.want))
+		}
+	}
+}
+
+func TestGetAuctionsCursorPagination(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	for _, order := range []string{"asc", "desc"} {
+		var all auctionsResponse
+		get(t, s, "/api/auctions?sort=unit_buyout&order="+order, http.StatusOK, &all)
+
+		var ids []int
+		cursor := ""
+		for pages := 0; ; pages++ {
+			if pages > 5 {
+				t.Fatalf("%s: cursor pagination did not terminate", order)
+			}
+			var resp auctionsResponse
+			target := "/api/auctions?sort=unit_buyout&limit=2&order=" + order + "&cursor=" + url.QueryEscape(cursor)
+			get(t, s, target, http.StatusOK, &resp)
+			if resp.Total != 5 {
+				t.Errorf("%s: total = %d on every page, want 5", order, resp.Total)
+			}
+			ids = append(ids, auctionIDs(resp.Auctions)...)
+			if resp.NextCursor == "" {
+				break
+			}
+			cursor = resp.NextCursor
+		}
+		if want := auctionIDs(all.Auctions); !equalIDs(ids, want) {
+			t.Errorf("%s: paged IDs = %v, want %v", order, ids, want)
+		}
+	}
+}
+
+func TestGetAuctionsPageNumbers(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	var resp auctionsResponse
+	get(t, s, "/api/auctions?limit=2&page=2", http.StatusOK, &resp)
+	if got, want := auctionIDs(resp.Auctions), []int{5, 4}; !equalIDs(got, want) {
+		t.Errorf("page 2 IDs = %v, want %v", got, want)
+	}
+	if resp.NextCursor == "" {
+		t.Error("next_cursor is empty before the last page")
+	}
+}
+
+func TestGetAuctionsRejectsInvalidParameters(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	var resp auctionsResponse
+	get(t, s, "/api/auctions?sort=buyout&limit=1", http.StatusOK, &resp)
+	cursor := url.QueryEscape(resp.NextCursor)
+
+	for _, query := range []string{
+		"sort=bogus",
+		"order=sideways",
+		"quality_min=4&quality_max=2",
+		"count_min=-1",
+		"has_bid=maybe",
+		"time_left=forever",
+		"house=gnome",
+		"cursor=not-a-cursor",
+		"cursor=" + cursor, // issued for sort=buyout
+	} {
+		get(t, s, "/api/auctions?"+query, http.StatusBadRequest, nil)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	get(t, s, "/api/search", http.StatusBadRequest, nil)
+	// A blank search would list every auction
+	get(t, s, "/api/search?q=%20", http.StatusBadRequest, nil)
+
+	var resp auctionsResponse
+	get(t, s, "/api/search?q=cloth&house=alliance", http.StatusOK, &resp)
+	if got, want := auctionIDs(resp.Auctions), []int{1, 2}; !equalIDs(got, want) {
+		t.Errorf("search by item IDs = %v, want %v", got, want)
+	}
+	if resp.Search != "cloth" || resp.House != wow.HouseAlliance {
+		t.Errorf("search = %q, house = %d", resp.Search, resp.House)
+	}
+
+	get(t, s, "/api/search?q=caro", http.StatusOK, &resp)
+	if got, want := auctionIDs(resp.Auctions), []int{4}; !equalIDs(got, want) {
+		t.Errorf("search by seller IDs = %v, want %v", got, want)
+	}
+}
+
+func TestGetStats(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	var stats store.AuctionHouseStats
+	get(t, s, "/api/stats", http.StatusOK, &stats)
+	want := store.AuctionHouseStats{TotalItems: 5, TotalValue: 50003590, ActiveBids: 1, UniqueOwners: 3, UniqueItems: 3}
+	if stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+
+	get(t, s, "/api/stats?house=horde", http.StatusOK, &stats)
+	want = store.AuctionHouseStats{TotalItems: 2, TotalValue: 90, ActiveBids: 1, UniqueOwners: 1, UniqueItems: 2}
+	if stats != want {
+		t.Errorf("horde stats = %+v, want %+v", stats, want)
+	}
+}
+
+func TestGetSellers(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	var resp struct {
+		Sellers []store.Seller `json:"sellers"`
+	}
+	get(t, s, "/api/sellers", http.StatusOK, &resp)
+	want := []store.Seller{
+		{Name: "Alice", TotalAuctions: 2, TotalValue: 3500, UniqueItems: 1},
+		{Name: "Bob", TotalAuctions: 2, TotalValue: 90, UniqueItems: 2},
+		{Name: "Carol", TotalAuctions: 1, TotalValue: 50000000, UniqueItems: 1},
+	}
+	if len(resp.Sellers) != len(want) {
+		t.Fatalf("sellers = %+v, want %+v", resp.Sellers, want)
+	}
+	for i := range want {
+		if resp.Sellers[i] != want[i] {
+			t.Errorf("seller %d = %+v, want %+v", i, resp.Sellers[i], want[i])
+		}
+	}
+}
+
+func TestGetHouses(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	var resp struct {
+		Houses []store.AuctionHouse `json:"houses"`
+	}
+	get(t, s, "/api/houses", http.StatusOK, &resp)
+	if len(resp.Houses) != 2 || resp.Houses[0].ID != wow.HouseAlliance || resp.Houses[1].ID != wow.HouseHorde {
+		t.Errorf("houses = %+v, want Alliance then Horde", resp.Houses)
+	}
+}
+
+func TestGetItem(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	var resp struct {
+		Item     store.ItemTemplate  `json:"item"`
+		Market   ItemMarket          `json:"market"`
+		Auctions []store.AuctionItem `json:"auctions"`
+	}
+	get(t, s, "/api/items/2589", http.StatusOK, &resp)
+	if resp.Item.Name != "Linen Cloth" {
+		t.Errorf("item = %+v", resp.Item)
+	}
+	if got, want := auctionIDs(resp.Auctions), []int{1, 5, 2}; !equalIDs(got, want) {
+		t.Errorf("auction IDs = %v, want %v", got, want)
+	}
+	// 31 units: 1 at 90, 20 at 100 and 10 at 150 per unit
+	want := ItemMarket{Listings: 3, Quantity: 31, BuyoutListings: 3, MinBuyout: 90, MedianBuyout: 100, MaxBuyout: 150, MeanBuyout: 115}
+	if !reflect.DeepEqual(resp.Market, want) {
+		t.Errorf("market = %+v, want %+v", resp.Market, want)
+	}
+
+	get(t, s, "/api/items/2589?house=horde", http.StatusOK, &resp)
+	if got, want := auctionIDs(resp.Auctions), []int{5}; !equalIDs(got, want) {
+		t.Errorf("horde auction IDs = %v, want %v", got, want)
+	}
+
+	get(t, s, "/api/items/999999", http.StatusNotFound, nil)
+	get(t, s, "/api/items/abc", http.StatusBadRequest, nil)
+	get(t, s, "/items/0", http.StatusBadRequest, nil)
+}
+
+func TestGetItemHistory(t *testing.T) {
+	m := newTestStore()
+	get(t, newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m})}), "/api/items/2589/history", http.StatusServiceUnavailable, nil)
+
+	m.SaveSnapshot(t.Context(), time.Now().Add(-48*time.Hour), 3, map[int]store.PricePoint{
+		2589: {Listings: 3, Quantity: 31, MinBuyout: 90, MedianBuyout: 100, MeanBuyout: 114},
+	})
+	m.SaveSnapshot(t.Context(), time.Now().Add(-30*24*time.Hour), 1, map[int]store.PricePoint{
+		2589: {Listings: 1, Quantity: 1, MinBuyout: 80, MedianBuyout: 80, MeanBuyout: 80},
+	})
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m, History: m})})
+
+	var resp struct {
+		Entry   int                `json:"entry"`
+		Days    int                `json:"days"`
+		History []store.PricePoint `json:"history"`
+	}
+	get(t, s, "/api/items/2589/history", http.StatusOK, &resp)
+	if resp.Entry != 2589 || resp.Days != 7 || len(resp.History) != 1 || resp.History[0].MedianBuyout != 100 {
+		t.Errorf("history = %+v, want the one snapshot in the last 7 days", resp)
+	}
+
+	get(t, s, "/api/items/2589/history?days=90", http.StatusOK, &resp)
+	if len(resp.History) != 2 || resp.History[0].MinBuyout != 80 {
+		t.Errorf("90 day history = %+v, want both snapshots oldest first", resp.History)
+	}
+}
+
+func TestQueryErrorsReturn500(t *testing.T) {
+	m := newTestStore()
+	m.Err = errors.New("connection refused")
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m})})
+
+	for _, target := range []string{"/api/auctions", "/api/search?q=x", "/api/stats", "/api/sellers", "/api/houses", "/api/items/2589"} {
+		get(t, s, target, http.StatusInternalServerError, nil)
+	}
+}
+
+func TestHome(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	rec := get(t, s, "/", http.StatusOK, nil)
+	if !strings.Contains(rec.Body.String(), "<html") {
+		t.Error("home page is not HTML")
+	}
+	rec = get(t, s, "/items/2589", http.StatusOK, nil)
+	if !strings.Contains(rec.Body.String(), "2589") {
+		t.Error("item page does not mention its entry")
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+	get(t, s, "/api/stats", http.StatusOK, nil)
+
+	rec := get(t, s, "/metrics", http.StatusOK, nil)
+	body := rec.Body.String()
+	for _, want := range []string{
+		`ah_http_requests_total{route="GET /api/stats",method="GET",status="200"}`,
+		`ah_http_request_duration_seconds_count{route="GET /api/stats"}`,
+		`ah_auction_listings{realm="1",house="2",quality="1"} 2`,
+		`ah_auction_buyout_value_copper{realm="1",house="7",quality="5"} 50000000`,
+		`ah_auction_active_bids{realm="1",house="6",quality="1"} 1`,
+		`ah_auction_unique_sellers{realm="1",house="6",quality="1"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %s", want)
+		}
+	}
+}
+
+// newSuffixStore returns a test store also listing Bandit Cinches of the
+// Monkey and of the Eagle, as auctions 7 and 8, and a plain one as auction 9
+func newSuffixStore() *store.Memory {
+	m := newTestStore()
+	m.AddItem(store.ItemTemplate{Entry: 9776, Name: "Bandit Cinch", Quality: 2, ItemLevel: 10, InventoryType: 6, Class: 4})
+	m.AddPropertyPoints(10, store.PropertyPoints{Good: [5]int{17, 13, 10, 7, 5}})
+	stat := func(stat int) []store.EnchantmentEffect {
+		return []store.EnchantmentEffect{{Type: 5, Arg: stat}}
+	}
+	m.AddRandomProperty(store.RandomProperty{ID: -5, Suffix: "of the Monkey", Enchantments: []store.RandomEnchantment{
+		{ID: 2802, Allocation: 5000, Effects: stat(3)}, {ID: 2803, Allocation: 5000, Effects: stat(7)},
+	}})
+	m.AddRandomProperty(store.RandomProperty{ID: -7, Suffix: "of the Eagle", Enchantments: []store.RandomEnchantment{
+		{ID: 2804, Allocation: 5000, Effects: stat(5)}, {ID: 2805, Allocation: 5000, Effects: stat(7)},
+	}})
+
+	expires := int(now.Add(time.Hour).Unix())
+	m.AddAuction(store.AuctionItem{ID: 7, HouseID: wow.HouseAlliance, ItemGUID: 700, ItemOwner: 10, OwnerName: "Alice", ItemEntry: 9776, Count: 1, BuyoutPrice: 900, RandomPropertyID: -5, Time: expires})
+	m.AddAuction(store.AuctionItem{ID: 8, HouseID: wow.HouseAlliance, ItemGUID: 800, ItemOwner: 10, OwnerName: "Alice", ItemEntry: 9776, Count: 1, BuyoutPrice: 700, RandomPropertyID: -7, Time: expires})
+	m.AddAuction(store.AuctionItem{ID: 9, HouseID: wow.HouseAlliance, ItemGUID: 900, ItemOwner: 10, OwnerName: "Alice", ItemEntry: 9776, Count: 1, BuyoutPrice: 500, Time: expires})
+	return m
+}
+
+func TestRandomSuffixes(t *testing.T) {
+	m := newSuffixStore()
+	m.AddItemTooltip(store.ItemTooltip{Entry: 9776, Name: "Bandit Cinch", Quality: 2, ItemLevel: 10, InventoryType: 6, Armor: 30})
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m}), Tooltips: m})
+
+	var resp auctionsResponse
+	get(t, s, "/api/auctions?entry=9776&sort=buyout", http.StatusOK, &resp)
+	if got, want := auctionIDs(resp.Auctions), []int{9, 8, 7}; !equalIDs(got, want) {
+		t.Fatalf("auction IDs = %v, want %v", got, want)
+	}
+	monkey := resp.Auctions[2]
+	if monkey.ItemName != "Bandit Cinch of the Monkey" || monkey.Suffix != "of the Monkey" {
+		t.Errorf("name = %q, suffix = %q", monkey.ItemName, monkey.Suffix)
+	}
+	// Each stat gets half of the 13 points of a waist slot
+	if want := []store.ItemStat{{Type: 3, Value: 6}, {Type: 7, Value: 6}}; !reflect.DeepEqual(monkey.RandomStats, want) {
+		t.Errorf("random stats = %+v, want %+v", monkey.RandomStats, want)
+	}
+	if plain := resp.Auctions[0]; plain.ItemName != "Bandit Cinch" || plain.Suffix != "" {
+		t.Errorf("plain name = %q, suffix = %q", plain.ItemName, plain.Suffix)
+	}
+
+	for query, want := range map[string][]int{
+		"/api/search?q=monkey":                               {7},
+		"/api/search?q=cinch+of+the":                         nil,
+		"/api/auctions?suffix=eagle":                         {8},
+		"/api/auctions?suffix=of+the&entry=9776&sort=buyout": {8, 7},
+		"/api/auctions?suffix=bear":                          nil,
+	} {
+		get(t, s, query, http.StatusOK, &resp)
+		if got := auctionIDs(resp.Auctions); !equalIDs(got, want) {
+			t.Errorf("%s: auction IDs = %v, want %v", query, got, want)
+		}
+	}
+
+	var item struct {
+		Market ItemMarket `json:"market"`
+	}
+	get(t, s, "/api/items/9776", http.StatusOK, &item)
+	if item.Market.Listings != 3 || len(item.Market.Suffixes) != 2 {
+		t.Fatalf("market = %+v", item.Market)
+	}
+	if eagle := item.Market.Suffixes[0]; eagle.Suffix != "of the Eagle" || eagle.RandomPropertyID != -7 || eagle.MinBuyout != 700 {
+		t.Errorf("first suffix = %+v, want of the Eagle at 700", eagle)
+	}
+
+	var tooltip store.ItemTooltip
+	get(t, s, "/api/auctions/7/tooltip", http.StatusOK, &tooltip)
+	if tooltip.Name != "Bandit Cinch of the Monkey" || len(tooltip.Stats) != 2 {
+		t.Errorf("tooltip = %+v", tooltip)
+	}
+}
@@ -0,0 +1,603 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// Audit log actions of the mod_auctionator endpoints
+const (
+	auditAuctionatorClass   = "auctionator.update_class"
+	auditAuctionatorDisable = "auctionator.disable_item"
+	auditAuctionatorEnable  = "auctionator.enable_item"
+)
+
+// handleGetMarketComparison compares the live listings of each item with its
+// mod_auctionator market price, cheapest first
+func (s *Server) handleGetMarketComparison(w http.ResponseWriter, r *http.Request, rm *realm) {
+	p := queryParser{values: r.URL.Query()}
+	q := store.CompareQuery{Quality: p.intRange("quality")}
+	switch r.URL.Query().Get("order") {
+	case "", "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		p.fail("order")
+	}
+	limit := defaultPageSize
+	if n := p.int("limit"); n > 0 {
+		limit = min(n, maxPageSize)
+	}
+	if p.err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, p.err.Error())
+		return
+	}
+	house, err := parseHouse(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid house")
+		return
+	}
+	q.House = house
+	locale, err := parseLocale(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid locale")
+		return
+	}
+
+	auctions, err := rm.Auctions.LiveAuctions(r.Context(), locale)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	items := store.CompareMarket(auctions, q)
+	total := len(items)
+	items = items[:min(limit, total)]
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"items":  items,
+		"total":  total,
+		"limit":  limit,
+		"order":  orderName(q.Desc),
+		"house":  house,
+		"locale": locale,
+	})
+}
+
+var auctionatorPage = template.Must(template.New("auctionator").Parse(auctionatorTemplate))
+
+func (s *Server) handleAuctionatorPage(w http.ResponseWriter, r *http.Request) {
+	renderPage(w, r, auctionatorPage, pageData(pageLocale(w, r), s.adminPageData(r)))
+}
+
+func (s *Server) handleGetAuctionatorClasses(w http.ResponseWriter, r *http.Request) {
+	classes, err := s.auctionator.AuctionatorClasses(r.Context())
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"classes": classes,
+	})
+}
+
+// handleUpdateAuctionatorClass changes how many items of one class and
+// subclass mod_auctionator lists, and in what stacks. Settings left out of
+// the body keep their values; the rest of the configuration is read-only.
+func (s *Server) handleUpdateAuctionatorClass(w http.ResponseWriter, r *http.Request) {
+	if !s.auditAvailable(w, r) {
+		return
+	}
+	class, err := strconv.Atoi(r.PathValue("class"))
+	if err != nil || class < 0 {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid item class")
+		return
+	}
+	subclass, err := strconv.Atoi(r.PathValue("subclass"))
+	if err != nil || subclass < 0 {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid item subclass")
+		return
+	}
+
+	before, err := s.auctionator.AuctionatorClass(r.Context(), class, subclass)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "Item class config not found")
+		return
+	}
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	var body struct {
+		MaxCount   *int `json:"max_count"`
+		StackCount *int `json:"stack_count"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "invalid JSON body")
+		return
+	}
+	config := before
+	if body.MaxCount != nil {
+		config.MaxCount = *body.MaxCount
+	}
+	if body.StackCount != nil {
+		config.StackCount = *body.StackCount
+	}
+	if err := config.Validate(); err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+
+	config, err = s.auctionator.UpdateAuctionatorClass(r.Context(), config)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "Item class config not found")
+		return
+	}
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	s.recordAudit(r, auditAuctionatorClass, fmt.Sprintf("class %d.%d", class, subclass), before, config)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(config)
+}
+
+func (s *Server) handleGetAuctionatorDisabledItems(w http.ResponseWriter, r *http.Request) {
+	items, err := s.auctionator.AuctionatorDisabledItems(r.Context())
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"items": items,
+	})
+}
+
+// handleDisableAuctionatorItem stops mod_auctionator listing the item whose
+// entry is in the body
+func (s *Server) handleDisableAuctionatorItem(w http.ResponseWriter, r *http.Request) {
+	if !s.auditAvailable(w, r) {
+		return
+	}
+	var body struct {
+		Entry int `json:"entry"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "invalid JSON body")
+		return
+	}
+	if body.Entry <= 0 {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid item entry")
+		return
+	}
+
+	if err := s.auctionator.DisableAuctionatorItem(r.Context(), body.Entry); err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	s.recordAudit(r, auditAuctionatorDisable, "item "+strconv.Itoa(body.Entry), nil, body)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleEnableAuctionatorItem lets mod_auctionator list an item again
+func (s *Server) handleEnableAuctionatorItem(w http.ResponseWriter, r *http.Request) {
+	if !s.auditAvailable(w, r) {
+		return
+	}
+	entry, ok := itemEntry(w, r)
+	if !ok {
+		return
+	}
+
+	err := s.auctionator.EnableAuctionatorItem(r.Context(), entry)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "Item is not disabled")
+		return
+	}
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	s.recordAudit(r, auditAuctionatorEnable, "item "+strconv.Itoa(entry), map[string]int{"entry": entry}, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+const auctionatorTemplate = `<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.T.auctionatorTitle}} - {{.T.title}}</title>
+    <style>
+        * {
+            margin: 0;
+            padding: 0;
+            box-sizing: border-box;
+        }
+
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            background: linear-gradient(135deg, #1e3c72 0%, #2a5298 100%);
+            color: #333;
+            min-height: 100vh;
+        }
+
+        .container {
+            max-width: 1400px;
+            margin: 0 auto;
+            padding: 20px;
+        }
+
+        .header {
+            text-align: center;
+            margin-bottom: 30px;
+            color: white;
+        }
+
+        .header h1 {
+            font-size: 2.5rem;
+            margin-bottom: 10px;
+            text-shadow: 2px 2px 4px rgba(0,0,0,0.5);
+        }
+
+        .header a {
+            color: white;
+            opacity: 0.9;
+        }
+
+        .panel {
+            background: rgba(255, 255, 255, 0.95);
+            border-radius: 10px;
+            overflow: hidden;
+            box-shadow: 0 4px 6px rgba(0,0,0,0.1);
+            margin-bottom: 20px;
+        }
+
+        .panel-header {
+            background: #2a5298;
+            color: white;
+            padding: 15px 20px;
+            font-weight: bold;
+        }
+
+        .panel-body {
+            padding: 15px 20px;
+        }
+
+        .toolbar {
+            display: flex;
+            flex-wrap: wrap;
+            gap: 10px;
+            align-items: center;
+            margin-bottom: 15px;
+        }
+
+        .table-container {
+            overflow-x: auto;
+        }
+
+        table {
+            width: 100%;
+            border-collapse: collapse;
+            margin-bottom: 15px;
+        }
+
+        th, td {
+            padding: 8px 12px;
+            text-align: left;
+            border-bottom: 1px solid #ddd;
+        }
+
+        th {
+            background: #f8f9fa;
+            font-weight: 600;
+        }
+
+        input {
+            padding: 6px 8px;
+            border: 1px solid #ccc;
+            border-radius: 4px;
+        }
+
+        input[type=number] {
+            width: 80px;
+        }
+
+        button {
+            padding: 6px 14px;
+            background: #2a5298;
+            color: white;
+            border: none;
+            border-radius: 4px;
+            cursor: pointer;
+        }
+
+        button.secondary {
+            background: #888;
+        }
+
+        .quality-0 { color: #9d9d9d; }
+        .quality-1 { color: #333; }
+        .quality-2 { color: #1eff00; text-shadow: 1px 1px 2px rgba(0,0,0,0.5); }
+        .quality-3 { color: #0070dd; }
+        .quality-4 { color: #a335ee; }
+        .quality-5 { color: #ff8000; }
+        .quality-6 { color: #e6cc80; }
+
+        .invalid {
+            color: #c62828;
+            font-weight: bold;
+        }
+
+        .note {
+            color: #666;
+            font-size: 0.9rem;
+        }
+
+        .loading {
+            text-align: center;
+            padding: 20px;
+            color: #666;
+        }
+
+        .error {
+            background: #ffebee;
+            color: #c62828;
+            padding: 10px 15px;
+            border-radius: 5px;
+            margin: 10px 0;
+        }
+
+        .success {
+            color: #2e7d32;
+        }
+
+        .hidden {
+            display: none;
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>{{.T.auctionatorTitle}}</h1>
+            <p><a href="/">&larr; {{.T.backToAuctions}}</a></p>
+        </div>
+
+        <div class="panel" id="signIn">
+            <div class="panel-body">
+                {{if .TokenSignIn}}
+                <form class="toolbar" onsubmit="signIn(event)">
+                    <label for="token">{{.T.adminToken}}</label>
+                    <input type="password" id="token" autocomplete="current-password">
+                    <button type="submit">{{.T.signIn}}</button>
+                </form>
+                {{end}}
+                {{if .Login}}
+                <p class="note"><a href="/login?next=/admin/auctionator">{{.T.signInWithAccount}}</a></p>
+                {{end}}
+                <div id="signInError"></div>
+            </div>
+        </div>
+
+        <div id="admin" class="hidden">
+            <div class="toolbar">
+                <button class="secondary" onclick="signOut()">{{.T.signOut}}</button>
+                <span class="note">{{.T.auctionatorNote}}</span>
+            </div>
+
+            <div class="panel">
+                <div class="panel-header">{{.T.itemClasses}}</div>
+                <div class="panel-body table-container">
+                    <div id="classesError"></div>
+                    <table>
+                        <thead>
+                            <tr>
+                                <th>{{.T.itemClass}}</th>
+                                <th>{{.T.className}}</th>
+                                <th>{{.T.lowestQuality}}</th>
+                                <th>{{.T.maxCount}}</th>
+                                <th>{{.T.stackCount}}</th>
+                                <th></th>
+                            </tr>
+                        </thead>
+                        <tbody id="classesBody">
+                            <tr><td colspan="6" class="loading">{{.T.loading}}</td></tr>
+                        </tbody>
+                    </table>
+                </div>
+            </div>
+
+            <div class="panel">
+                <div class="panel-header">{{.T.disabledItems}}</div>
+                <div class="panel-body">
+                    <form class="toolbar" onsubmit="disableItem(event)">
+                        <label for="disableEntry">{{.T.itemEntry}}</label>
+                        <input type="number" id="disableEntry" min="1" required>
+                        <button type="submit">{{.T.add}}</button>
+                    </form>
+                    <div id="disabledError"></div>
+                    <div class="table-container">
+                        <table>
+                            <thead>
+                                <tr><th>{{.T.itemEntry}}</th><th>{{.T.item}}</th><th></th></tr>
+                            </thead>
+                            <tbody id="disabledBody"></tbody>
+                        </table>
+                    </div>
+                </div>
+            </div>
+        </div>
+    </div>
+
+    <script>
+        const T = {{.T}};
+        const qualityNames = [T.qualityPoor, T.qualityCommon, T.qualityUncommon, T.qualityRare,
+            T.qualityEpic, T.qualityLegendary, T.qualityArtifact];
+
+        // Game masters signed in with their account need no token
+        let sessionAdmin = {{.SessionAdmin}};
+
+        document.addEventListener('DOMContentLoaded', function() {
+            if (sessionAdmin || sessionStorage.getItem('adminToken')) {
+                loadAll();
+            }
+        });
+
+        function signIn(event) {
+            event.preventDefault();
+            sessionStorage.setItem('adminToken', document.getElementById('token').value);
+            loadAll();
+        }
+
+        function signOut() {
+            sessionStorage.removeItem('adminToken');
+            if (sessionAdmin) {
+                sessionAdmin = false;
+                fetch('/api/logout', {method: 'POST'});
+            }
+            document.getElementById('admin').classList.add('hidden');
+            document.getElementById('signIn').classList.remove('hidden');
+        }
+
+        // api calls the admin API, throwing the error message of failed
+        // requests. A rejected token signs out.
+        async function api(method, path, body) {
+            const headers = {'Content-Type': 'application/json'};
+            if (sessionStorage.getItem('adminToken')) {
+                headers['Authorization'] = 'Bearer ' + sessionStorage.getItem('adminToken');
+            }
+            const response = await fetch(path, {
+                method: method,
+                headers: headers,
+                body: body === undefined ? undefined : JSON.stringify(body)
+            });
+            if (response.status === 401) {
+                signOut();
+                document.getElementById('signInError').innerHTML = '<div class="error">' + T.adminTokenRejected + '</div>';
+                throw new Error(T.adminTokenRejected);
+            }
+            if (!response.ok) {
+                throw new Error((await response.json()).error.message);
+            }
+            return response.status === 204 ? null : response.json();
+        }
+
+        async function loadAll() {
+            try {
+                await loadClasses();
+            } catch (error) {
+                console.error('Error loading mod_auctionator configuration:', error);
+                return;
+            }
+            document.getElementById('signInError').innerHTML = '';
+            document.getElementById('signIn').classList.add('hidden');
+            document.getElementById('admin').classList.remove('hidden');
+            loadDisabledItems();
+        }
+
+        async function loadClasses() {
+            const tbody = document.getElementById('classesBody');
+            let classes;
+            try {
+                classes = (await api('GET', '/api/admin/auctionator/classes')).classes;
+            } catch (error) {
+                tbody.innerHTML = '<tr><td colspan="6" class="error">' + T.errorAuctionator + ': ' + escapeHTML(error.message) + '</td></tr>';
+                throw error;
+            }
+            if (classes.length === 0) {
+                tbody.innerHTML = '<tr><td colspan="6" class="loading">' + T.auctionatorNotInstalled + '</td></tr>';
+                return;
+            }
+            tbody.innerHTML = classes.map(function(c) {
+                const id = c.class + '-' + c.subclass;
+                return '<tr>' +
+                    '<td>' + c.class + '.' + c.subclass + '</td>' +
+                    '<td>' + escapeHTML(c.name || '-') + '</td>' +
+                    '<td class="quality-' + c.bonding + '">' + (qualityNames[c.bonding] || c.bonding) + '</td>' +
+                    '<td><input type="number" min="0" id="max-' + id + '" value="' + c.max_count + '"></td>' +
+                    '<td><input type="number" min="1" id="stack-' + id + '" value="' + c.stack_count + '"></td>' +
+                    '<td><button onclick="saveClass(' + c.class + ', ' + c.subclass + ')">' + T.save + '</button> ' +
+                    '<span id="status-' + id + '"></span></td>' +
+                    '</tr>';
+            }).join('');
+        }
+
+        async function saveClass(itemClass, subclass) {
+            const id = itemClass + '-' + subclass;
+            const status = document.getElementById('status-' + id);
+            try {
+                await api('PUT', '/api/admin/auctionator/classes/' + itemClass + '/' + subclass, {
+                    max_count: parseInt(document.getElementById('max-' + id).value, 10) || 0,
+                    stack_count: parseInt(document.getElementById('stack-' + id).value, 10) || 0
+                });
+                status.innerHTML = '<span class="success">' + T.saved + '</span>';
+            } catch (error) {
+                status.innerHTML = '<span class="invalid">' + escapeHTML(error.message) + '</span>';
+            }
+        }
+
+        async function loadDisabledItems() {
+            const tbody = document.getElementById('disabledBody');
+            try {
+                const items = (await api('GET', '/api/admin/auctionator/disabled-items')).items;
+                if (items.length === 0) {
+                    tbody.innerHTML = '<tr><td colspan="3" class="loading">' + T.noDisabledItems + '</td></tr>';
+                    return;
+                }
+                tbody.innerHTML = items.map(item => '<tr>' +
+                    '<td>' + item.entry + '</td>' +
+                    '<td><a href="/items/' + item.entry + '">' + escapeHTML(item.name || '-') + '</a></td>' +
+                    '<td><button class="secondary" onclick="enableItem(' + item.entry + ')">' + T.remove + '</button></td>' +
+                    '</tr>').join('');
+            } catch (error) {
+                tbody.innerHTML = '<tr><td colspan="3" class="error">' + escapeHTML(error.message) + '</td></tr>';
+            }
+        }
+
+        async function disableItem(event) {
+            event.preventDefault();
+            const input = document.getElementById('disableEntry');
+            const errors = document.getElementById('disabledError');
+            try {
+                await api('POST', '/api/admin/auctionator/disabled-items', {entry: parseInt(input.value, 10)});
+                input.value = '';
+                errors.innerHTML = '';
+                loadDisabledItems();
+            } catch (error) {
+                errors.innerHTML = '<div class="error">' + escapeHTML(error.message) + '</div>';
+            }
+        }
+
+        async function enableItem(entry) {
+            const errors = document.getElementById('disabledError');
+            try {
+                await api('DELETE', '/api/admin/auctionator/disabled-items/' + entry);
+                errors.innerHTML = '';
+                loadDisabledItems();
+            } catch (error) {
+                errors.innerHTML = '<div class="error">' + escapeHTML(error.message) + '</div>';
+            }
+        }
+
+        function escapeHTML(text) {
+            const div = document.createElement('div');
+            div.textContent = text;
+            return div.innerHTML;
+        }
+    </script>
+</body>
+</html>`
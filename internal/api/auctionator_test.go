@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+func TestMarketComparison(t *testing.T) {
+	m := newTestStore()
+	scanned := time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC)
+	m.AddMarketPrice(store.MarketPrice{Entry: 2589, AveragePrice: 120, ScannedAt: scanned})
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m})})
+
+	// Listings carry the market price of their items
+	var page store.AuctionPage
+	get(t, s, "/api/auctions?sort=unit_buyout", http.StatusOK, &page)
+	for _, a := range page.Auctions {
+		want := 0
+		if a.ItemEntry == 2589 {
+			want = 120
+		}
+		if a.AuctionatorPrice != want {
+			t.Errorf("auction %d auctionator_price = %d, want %d", a.ID, a.AuctionatorPrice, want)
+		}
+	}
+
+	var resp struct {
+		Items []store.MarketComparison `json:"items"`
+		Total int                      `json:"total"`
+	}
+	get(t, s, "/api/market-comparison", http.StatusOK, &resp)
+	want := store.MarketComparison{
+		Entry: 2589, ItemName: "Linen Cloth", Quality: 1, Listings: 3, Units: 31, MinUnitBuyout: 90,
+		MedianUnitBuyout: 100, MarketPrice: 120, ScannedAt: scanned, PercentOfMarket: 75,
+	}
+	if resp.Total != 1 || len(resp.Items) != 1 || !resp.Items[0].ScannedAt.Equal(scanned) {
+		t.Fatalf("comparison = %+v, want Linen Cloth only", resp)
+	}
+	resp.Items[0].ScannedAt = scanned
+	if resp.Items[0] != want {
+		t.Errorf("comparison = %+v, want %+v", resp.Items[0], want)
+	}
+
+	get(t, s, "/api/market-comparison?house=alliance&order=desc", http.StatusOK, &resp)
+	if len(resp.Items) != 1 || resp.Items[0].MinUnitBuyout != 100 || resp.Items[0].PercentOfMarket != 83 {
+		t.Errorf("Alliance comparison = %+v", resp.Items)
+	}
+
+	for _, query := range []string{"order=sideways", "house=scourge", "quality_min=x"} {
+		get(t, s, "/api/market-comparison?"+query, http.StatusBadRequest, nil)
+	}
+}
+
+// newAuctionatorServer returns a server with mod_auctionator's configuration
+// of cloth, which its seller lists in stacks of 20
+func newAuctionatorServer(t *testing.T, audit store.AuditRepository) *Server {
+	m := newTestStore()
+	m.AddAuctionatorClass(store.AuctionatorClass{Class: 7, Subclass: 5, Name: "Cloth", Bonding: 1, MaxCount: 10, StackCount: 20})
+	return newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m}), AdminToken: "s3cret", Auctionator: m, Audit: audit})
+}
+
+func TestUpdateAuctionatorClass(t *testing.T) {
+	s := newAuctionatorServer(t, nil)
+	if rec := adminJSON(s, http.MethodPut, "/api/admin/auctionator/classes/7/5", `{"max_count": 5}`); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("without audit log: status = %d, want 503", rec.Code)
+	}
+
+	audit := store.NewMemory()
+	s = newAuctionatorServer(t, audit)
+	for target, want := range map[string]int{
+		"/api/admin/auctionator/classes/7/6": http.StatusNotFound,
+		"/api/admin/auctionator/classes/x/5": http.StatusBadRequest,
+		"/api/admin/auctionator/classes/7/5": http.StatusBadRequest,
+	} {
+		if rec := adminJSON(s, http.MethodPut, target, `{"stack_count": 0}`); rec.Code != want {
+			t.Errorf("PUT %s: status = %d, want %d", target, rec.Code, want)
+		}
+	}
+
+	rec := adminJSON(s, http.MethodPut, "/api/admin/auctionator/classes/7/5", `{"max_count": 5, "bonding": 3}`)
+	var config store.AuctionatorClass
+	if err := json.NewDecoder(rec.Body).Decode(&config); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("update status = %d, decoding: %v", rec.Code, err)
+	}
+	want := store.AuctionatorClass{Class: 7, Subclass: 5, Name: "Cloth", Bonding: 1, MaxCount: 5, StackCount: 20}
+	if config != want {
+		t.Errorf("updated config = %+v, want %+v", config, want)
+	}
+
+	rec = adminRequest(s, http.MethodGet, "/api/admin/auctionator/classes", "s3cret")
+	var resp struct {
+		Classes []store.AuctionatorClass `json:"classes"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, decoding: %v", rec.Code, err)
+	}
+	if len(resp.Classes) != 1 || resp.Classes[0] != want {
+		t.Errorf("classes = %+v, want the updated cloth", resp.Classes)
+	}
+
+	entries, _ := audit.AuditLog(t.Context(), 10)
+	if len(entries) != 1 || entries[0].Action != auditAuctionatorClass || entries[0].Target != "class 7.5" {
+		t.Errorf("audit log = %+v, want the update", entries)
+	}
+}
+
+func TestAuctionatorDisabledItems(t *testing.T) {
+	audit := store.NewMemory()
+	s := newAuctionatorServer(t, audit)
+
+	if rec := adminJSON(s, http.MethodPost, "/api/admin/auctionator/disabled-items", `{"entry": -1}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid entry: status = %d, want 400", rec.Code)
+	}
+	if rec := adminJSON(s, http.MethodPost, "/api/admin/auctionator/disabled-items", `{"entry": 2589}`); rec.Code != http.StatusNoContent {
+		t.Errorf("disabling: status = %d", rec.Code)
+	}
+	// The auction house bot keeps its own list
+	if rec := adminRequest(s, http.MethodGet, "/api/admin/ahbot/disabled-items", "s3cret"); rec.Code != http.StatusNotFound {
+		t.Errorf("bot disabled items without the bot: status = %d, want 404", rec.Code)
+	}
+
+	rec := adminRequest(s, http.MethodGet, "/api/admin/auctionator/disabled-items", "s3cret")
+	var resp struct {
+		Items []store.DisabledItem `json:"items"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, decoding: %v", rec.Code, err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0] != (store.DisabledItem{Entry: 2589, Name: "Linen Cloth"}) {
+		t.Errorf("disabled items = %+v, want Linen Cloth", resp.Items)
+	}
+
+	if rec := adminJSON(s, http.MethodDelete, "/api/admin/auctionator/disabled-items/2589", ""); rec.Code != http.StatusNoContent {
+		t.Errorf("enabling: status = %d", rec.Code)
+	}
+	if rec := adminJSON(s, http.MethodDelete, "/api/admin/auctionator/disabled-items/2589", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("enabling twice: status = %d, want 404", rec.Code)
+	}
+
+	entries, _ := audit.AuditLog(t.Context(), 10)
+	if len(entries) != 2 || entries[0].Action != auditAuctionatorEnable || entries[1].Action != auditAuctionatorDisable {
+		t.Errorf("audit log = %+v, want the disable and enable", entries)
+	}
+}
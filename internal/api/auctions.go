@@ -0,0 +1,152 @@
+package api
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+)
+
+var indexPage = template.Must(template.New("index").Parse(indexTemplate))
+
+// handleHome serves the auction browser, with the account signed in if
+// signing in is offered
+func (s *Server) handleHome(w http.ResponseWriter, r *http.Request) {
+	data := map[string]interface{}{
+		"Login":    s.accounts != nil,
+		"Tooltips": s.tooltips != nil,
+		"Crafting": s.crafting != nil,
+		"Yields":   s.yields != nil,
+	}
+	if sess, ok := s.session(r); ok {
+		data["Account"] = s.accountInfo(sess)
+	}
+	renderPage(w, r, indexPage, pageData(pageLocale(w, r), data))
+}
+
+func (s *Server) handleGetAuctions(w http.ResponseWriter, r *http.Request, rm *realm) {
+	q, err := parseAuctionQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+	q.Filter.House, err = parseHouse(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid house")
+		return
+	}
+	q.Locale, err = parseLocale(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid locale")
+		return
+	}
+
+	page, err := rm.Auctions.ListAuctions(r.Context(), q)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"auctions":    page.Auctions,
+		"page":        q.Page,
+		"limit":       q.Limit,
+		"total":       page.Total,
+		"next_cursor": page.NextCursor,
+		"sort":        q.Sort,
+		"order":       orderName(q.Desc),
+		"house":       q.Filter.House,
+		"locale":      q.Locale,
+	})
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request, rm *realm) {
+	q, err := parseAuctionQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+	if q.Filter.Search == "" {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Search term required")
+		return
+	}
+	q.Filter.House, err = parseHouse(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid house")
+		return
+	}
+	q.Locale, err = parseLocale(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid locale")
+		return
+	}
+
+	page, err := rm.Auctions.ListAuctions(r.Context(), q)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"auctions":    page.Auctions,
+		"search":      q.Filter.Search,
+		"page":        q.Page,
+		"limit":       q.Limit,
+		"total":       page.Total,
+		"next_cursor": page.NextCursor,
+		"sort":        q.Sort,
+		"order":       orderName(q.Desc),
+		"house":       q.Filter.House,
+		"locale":      q.Locale,
+	})
+}
+
+func (s *Server) handleGetStats(w http.ResponseWriter, r *http.Request, rm *realm) {
+	house, err := parseHouse(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid house")
+		return
+	}
+
+	stats, err := rm.Auctions.Stats(r.Context(), house)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+func (s *Server) handleGetSellers(w http.ResponseWriter, r *http.Request, rm *realm) {
+	house, err := parseHouse(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid house")
+		return
+	}
+
+	sellers, err := rm.Auctions.Sellers(r.Context(), house, "")
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sellers": sellers,
+	})
+}
+
+func (s *Server) handleGetHouses(w http.ResponseWriter, r *http.Request, rm *realm) {
+	houses, err := rm.Auctions.Houses(r.Context())
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"houses": houses,
+	})
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// handleGetProfessions lists the professions the crafting calculator can
+// price. Professions are shared by every realm.
+func (s *Server) handleGetProfessions(w http.ResponseWriter, r *http.Request) {
+	locale, err := parseLocale(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid locale")
+		return
+	}
+
+	professions, err := s.crafting.Professions(r.Context(), locale)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"professions": professions,
+		"locale":      locale,
+	})
+}
+
+// handleGetCrafting prices the recipes of a profession at the cheapest live
+// buyouts of their reagents and product, most profitable first
+func (s *Server) handleGetCrafting(w http.ResponseWriter, r *http.Request, rm *realm) {
+	skill, err := strconv.Atoi(r.PathValue("skill"))
+	if err != nil || skill <= 0 {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid skill")
+		return
+	}
+	house, err := parseHouse(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid house")
+		return
+	}
+	locale, err := parseLocale(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid locale")
+		return
+	}
+
+	recipes, err := s.crafting.Recipes(r.Context(), skill, locale)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "Profession not found")
+		return
+	}
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	auctions, err := rm.Auctions.LiveAuctions(r.Context(), locale)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	houses, err := rm.Auctions.Houses(r.Context())
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	crafts := store.PriceCrafts(recipes, auctions, houses, house)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"skill":  skill,
+		"crafts": crafts,
+		"total":  len(crafts),
+		"house":  house,
+		"locale": locale,
+	})
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// addCrafting adds First Aid, whose Linen Bandage takes one Linen Cloth, and
+// Fishing, which has no recipes
+func addCrafting(m *store.Memory) {
+	addProfessions(m)
+	m.AddRecipe(129, store.Recipe{
+		SpellID: 3275, Name: "Linen Bandage", MinSkill: 1, TrivialLow: 30, TrivialHigh: 45,
+		Product: store.CraftItem{Entry: 1251}, MinCount: 1, MaxCount: 1,
+		Reagents: []store.CraftItem{{Entry: 2589, Count: 1}},
+	})
+	m.AddRecipe(129, store.Recipe{
+		SpellID: 3276, Name: "Heavy Linen Bandage", MinSkill: 40, TrivialLow: 50, TrivialHigh: 75,
+		Product: store.CraftItem{Entry: 2581}, MinCount: 1, MaxCount: 1,
+		Reagents: []store.CraftItem{{Entry: 2589, Count: 2}},
+	})
+}
+
+// addProfessions is addCrafting without the recipes, as a world database
+// without skilllineability_dbc or spell_dbc reads
+func addProfessions(m *store.Memory) {
+	m.AddItem(store.ItemTemplate{Entry: 1251, Name: "Linen Bandage", Quality: 1, ItemLevel: 3, Class: 0, Stackable: 20})
+	m.AddItem(store.ItemTemplate{Entry: 2581, Name: "Heavy Linen Bandage", Quality: 1, ItemLevel: 7, Class: 0, Stackable: 20})
+	m.AddItemName(1251, "deDE", "Leinenverband")
+	m.AddProfession(store.Profession{ID: 129, Name: "First Aid"})
+	m.AddProfession(store.Profession{ID: 356, Name: "Fishing"})
+	// Bandages sell for 2 silver each in the Alliance house
+	m.AddAuction(store.AuctionItem{ID: 7, HouseID: 2, ItemOwner: 10, OwnerName: "Alice", ItemEntry: 1251, Count: 5, BuyoutPrice: 1000, Time: int(now.Unix()) + 3600})
+}
+
+func withCrafting(cfg *Config, m *store.Memory) {
+	cfg.Crafting = m
+}
+
+type craftingResponse struct {
+	Crafts []store.Craft `json:"crafts"`
+	Total  int           `json:"total"`
+}
+
+func TestGetProfessions(t *testing.T) {
+	// Only professions with recipes are listed
+	for _, tt := range []struct {
+		name string
+		fill func(m *store.Memory)
+		want []int
+	}{
+		{"recipes", addCrafting, []int{129}},
+		{"no recipe tables", addProfessions, nil},
+	} {
+		s := newStoreServer(t, tt.fill, withCrafting)
+
+		var resp struct {
+			Professions []store.Profession `json:"professions"`
+		}
+		get(t, s, "/api/crafting", http.StatusOK, &resp)
+		if got := ids(resp.Professions, func(p store.Profession) int { return p.ID }); !equalIDs(got, tt.want) {
+			t.Errorf("%s: professions = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	s := newStoreServer(t, addCrafting, withCrafting)
+	if rec := get(t, s, "/", http.StatusOK, nil); !strings.Contains(rec.Body.String(), `id="craftingTab"`) {
+		t.Error("home page has no crafting tab")
+	}
+}
+
+func TestGetCrafting(t *testing.T) {
+	s := newStoreServer(t, addCrafting, withCrafting)
+
+	var resp craftingResponse
+	get(t, s, "/api/crafting/129", http.StatusOK, &resp)
+	if resp.Total != 2 || len(resp.Crafts) != 2 {
+		t.Fatalf("crafts = %+v, want 2", resp.Crafts)
+	}
+	// The cheapest Linen Cloth is 90 copper; a bandage earns 200 less the
+	// Alliance house's 5% cut
+	bandage := resp.Crafts[0]
+	if bandage.SpellID != 3275 || !bandage.Priced || bandage.Cost != 90 || bandage.Revenue != 190 || bandage.Margin != 100 {
+		t.Errorf("first craft = %+v, want Linen Bandage with 100 margin", bandage)
+	}
+	if bandage.Product.Name != "Linen Bandage" || bandage.Reagents[0].Name != "Linen Cloth" || bandage.Reagents[0].UnitPrice != 90 {
+		t.Errorf("items = %+v, %+v", bandage.Product, bandage.Reagents)
+	}
+	if heavy := resp.Crafts[1]; heavy.Priced || heavy.Product.UnitPrice != 0 {
+		t.Errorf("second craft = %+v, want unpriced Heavy Linen Bandage", heavy)
+	}
+
+	// Within the Horde house no bandage is listed
+	get(t, s, "/api/crafting/129?house=horde&locale=deDE", http.StatusOK, &resp)
+	if resp.Crafts[0].Priced || resp.Crafts[0].Reagents[0].UnitPrice != 90 {
+		t.Errorf("horde crafts = %+v, want none priced", resp.Crafts)
+	}
+	for _, c := range resp.Crafts {
+		if c.SpellID == 3275 && c.Product.Name != "Leinenverband" {
+			t.Errorf("deDE product name = %q", c.Product.Name)
+		}
+	}
+}
+
+func TestCraftingRequests(t *testing.T) {
+	s := newStoreServer(t, addCrafting, withCrafting)
+
+	for _, tt := range []struct {
+		name   string
+		s      *Server
+		target string
+		want   int
+		spells []int
+	}{
+		{"first aid", s, "/api/crafting/129", http.StatusOK, []int{3275, 3276}},
+		// Unpriced crafts sort by name
+		{"horde", s, "/api/crafting/129?house=horde", http.StatusOK, []int{3276, 3275}},
+		{"fishing", s, "/api/crafting/356", http.StatusOK, nil},
+		// A profession whose recipes cannot be read has nothing to craft
+		{"no recipe tables", newStoreServer(t, addProfessions, withCrafting), "/api/crafting/129", http.StatusOK, nil},
+		{"unknown profession", s, "/api/crafting/999", http.StatusNotFound, nil},
+		{"bad profession", s, "/api/crafting/abc", http.StatusBadRequest, nil},
+		{"bad house", s, "/api/crafting/129?house=nowhere", http.StatusBadRequest, nil},
+		{"no crafting repository", newStoreServer(t, addCrafting, nil), "/api/crafting/129", http.StatusNotFound, nil},
+		{"no crafting repository for professions", newStoreServer(t, addCrafting, nil), "/api/crafting", http.StatusNotFound, nil},
+	} {
+		if tt.want != http.StatusOK {
+			get(t, tt.s, tt.target, tt.want, nil)
+			continue
+		}
+		var resp craftingResponse
+		get(t, tt.s, tt.target, tt.want, &resp)
+		if got := ids(resp.Crafts, func(c store.Craft) int { return c.SpellID }); !equalIDs(got, tt.spells) || resp.Total != len(tt.spells) {
+			t.Errorf("%s: spells = %v of %d, want %v", tt.name, got, resp.Total, tt.spells)
+		}
+	}
+}
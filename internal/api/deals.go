@@ -0,0 +1,166 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+const (
+	// defaultDealPercent is the highest buyout, as a percentage of the
+	// market price, that counts as a deal unless configured otherwise
+	defaultDealPercent = 80
+	// dealMedianWindow is how far back the rolling median price reaches
+	dealMedianWindow = 7 * 24 * time.Hour
+	// referenceCacheTTL limits how often reference prices are queried
+	referenceCacheTTL = 5 * time.Minute
+)
+
+// referenceCache holds the reference prices of one realm, which change
+// slowly and take a scan of the price history to compute
+type referenceCache struct {
+	sync.Mutex
+	at     time.Time
+	prices map[int]store.ReferencePrice
+}
+
+// get returns the cached reference prices, querying them again once they
+// are older than referenceCacheTTL. Without price history only
+// mod_auctionator's market prices are used.
+func (c *referenceCache) get(ctx context.Context, rm *realm) (map[int]store.ReferencePrice, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if time.Since(c.at) <= referenceCacheTTL {
+		return c.prices, nil
+	}
+	market, err := rm.Auctions.MarketPrices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var medians map[int]int
+	if rm.History != nil {
+		medians, err = rm.History.MedianPrices(ctx, time.Now().Add(-dealMedianWindow))
+		if err != nil {
+			return nil, err
+		}
+	}
+	c.prices = store.ReferencePrices(market, medians)
+	c.at = time.Now()
+	return c.prices, nil
+}
+
+// handleGetDeals lists buyouts below the market price of their items, most
+// profitable first
+func (s *Server) handleGetDeals(w http.ResponseWriter, r *http.Request, rm *realm) {
+	p := queryParser{values: r.URL.Query()}
+	q := store.DealQuery{
+		MaxPercent: s.dealPercent,
+		Quality:    p.intRange("quality"),
+		MinProfit:  p.int("min_profit"),
+	}
+	if percent := p.optionalInt("max_percent"); percent != nil {
+		if *percent < 1 || *percent > 100 {
+			p.fail("max_percent")
+		}
+		q.MaxPercent = *percent
+	}
+	limit := defaultPageSize
+	if n := p.int("limit"); n > 0 {
+		limit = min(n, maxPageSize)
+	}
+	if p.err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, p.err.Error())
+		return
+	}
+	house, err := parseHouse(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid house")
+		return
+	}
+	q.House = house
+	locale, err := parseLocale(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid locale")
+		return
+	}
+
+	refs, err := rm.references.get(r.Context(), rm)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	auctions, err := rm.Auctions.LiveAuctions(r.Context(), locale)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	houses, err := rm.Auctions.Houses(r.Context())
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	deals := store.FindDeals(auctions, refs, houses, q)
+	total := len(deals)
+	deals = deals[:min(limit, total)]
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"deals":       deals,
+		"total":       total,
+		"limit":       limit,
+		"max_percent": q.MaxPercent,
+		"house":       house,
+		"locale":      locale,
+	})
+}
+
+// handleGetVendorFlips lists auctions that cost less than a vendor pays for
+// their items, guaranteed profits first
+func (s *Server) handleGetVendorFlips(w http.ResponseWriter, r *http.Request, rm *realm) {
+	p := queryParser{values: r.URL.Query()}
+	q := store.FlipQuery{Quality: p.intRange("quality")}
+	limit := defaultPageSize
+	if n := p.int("limit"); n > 0 {
+		limit = min(n, maxPageSize)
+	}
+	if p.err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, p.err.Error())
+		return
+	}
+	house, err := parseHouse(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid house")
+		return
+	}
+	q.House = house
+	locale, err := parseLocale(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid locale")
+		return
+	}
+
+	auctions, err := rm.Auctions.LiveAuctions(r.Context(), locale)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	flips := store.FindVendorFlips(auctions, q)
+	total := len(flips)
+	flips = flips[:min(limit, total)]
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"flips":  flips,
+		"total":  total,
+		"limit":  limit,
+		"house":  house,
+		"locale": locale,
+	})
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+func TestGetDeals(t *testing.T) {
+	type dealsResponse struct {
+		Deals      []store.Deal `json:"deals"`
+		Total      int          `json:"total"`
+		MaxPercent int          `json:"max_percent"`
+	}
+
+	// Without mod_auctionator the median of the price history is the market
+	m := newTestStore()
+	m.SaveSnapshot(t.Context(), time.Now().Add(-time.Hour), 3, map[int]store.PricePoint{
+		2589: {Listings: 3, Quantity: 31, MinBuyout: 90, MedianBuyout: 100, MeanBuyout: 114},
+	})
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m, History: m})})
+
+	var resp dealsResponse
+	get(t, s, "/api/deals", http.StatusOK, &resp)
+	if resp.Total != 0 || resp.MaxPercent != defaultDealPercent {
+		t.Errorf("deals under %d%% = %+v, want none", defaultDealPercent, resp)
+	}
+	get(t, s, "/api/deals?max_percent=90", http.StatusOK, &resp)
+	if resp.Total != 1 || resp.Deals[0].ID != 5 || resp.Deals[0].PriceSource != store.PriceSourceMedian || resp.Deals[0].Profit != 5 {
+		t.Errorf("deals under 90%% = %+v, want auction 5 with 5c profit", resp.Deals)
+	}
+
+	// mod_auctionator's market price takes precedence
+	m.AddMarketPrice(store.MarketPrice{Entry: 2589, AveragePrice: 150})
+	s = newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m, History: m}), DealPercent: 70})
+	get(t, s, "/api/deals", http.StatusOK, &resp)
+	if got := ids(resp.Deals, func(d store.Deal) int { return d.ID }); !equalIDs(got, []int{1, 5}) || resp.Deals[0].Profit != 850 {
+		t.Errorf("deals = %+v, want auctions 1 and 5, most profitable first", resp.Deals)
+	}
+	get(t, s, "/api/deals?house=horde&limit=1", http.StatusOK, &resp)
+	if got := ids(resp.Deals, func(d store.Deal) int { return d.ID }); !equalIDs(got, []int{5}) {
+		t.Errorf("horde deals = %v, want [5]", got)
+	}
+
+	for _, query := range []string{"max_percent=0", "max_percent=101", "quality_min=3&quality_max=1", "house=scourge"} {
+		get(t, s, "/api/deals?"+query, http.StatusBadRequest, nil)
+	}
+}
+
+func TestGetVendorFlips(t *testing.T) {
+	m := newTestStore()
+	later := int(now.Add(time.Hour).Unix())
+	m.AddAuction(store.AuctionItem{ID: 7, HouseID: wow.HouseAlliance, ItemEntry: 2592, Count: 20, BuyoutPrice: 500, StartBid: 400, Time: later})
+	m.AddAuction(store.AuctionItem{ID: 8, HouseID: wow.HouseHorde, ItemEntry: 2589, Count: 20, StartBid: 100, Time: later})
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m})})
+
+	var resp struct {
+		Flips []store.VendorFlip `json:"flips"`
+		Total int                `json:"total"`
+	}
+	get(t, s, "/api/vendor-flips", http.StatusOK, &resp)
+	if resp.Total != 2 || resp.Flips[0].ID != 7 || resp.Flips[0].Buy != store.BuyBuyout || resp.Flips[0].VendorValue != 660 || resp.Flips[0].Profit != 160 {
+		t.Fatalf("flips = %+v, want the buyout of auction 7 first", resp.Flips)
+	}
+	if f := resp.Flips[1]; f.ID != 8 || f.Buy != store.BuyBid || f.Price != 100 || f.Profit != 160 {
+		t.Errorf("flip = %+v, want a bid on auction 8", f)
+	}
+
+	get(t, s, "/api/vendor-flips?house=horde", http.StatusOK, &resp)
+	if resp.Total != 1 || resp.Flips[0].ID != 8 {
+		t.Errorf("horde flips = %+v, want auction 8", resp.Flips)
+	}
+	get(t, s, "/api/vendor-flips?quality_min=2", http.StatusOK, &resp)
+	if resp.Total != 0 {
+		t.Errorf("uncommon flips = %+v, want none", resp.Flips)
+	}
+	get(t, s, "/api/vendor-flips?quality_min=x", http.StatusBadRequest, nil)
+}
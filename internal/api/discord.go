@@ -0,0 +1,334 @@
+package api
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/metrics"
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// Discord interaction and response types
+const (
+	interactionPing               = 1
+	interactionApplicationCommand = 2
+
+	responsePong           = 1
+	responseChannelMessage = 4
+
+	messageFlagEphemeral = 64
+)
+
+// maxDiscordListings caps the listings shown in one embed
+const maxDiscordListings = 10
+
+// discordInteraction is the subset of an incoming interaction this
+// application reads
+type discordInteraction struct {
+	Type int `json:"type"`
+	Data struct {
+		Name    string          `json:"name"`
+		Options []discordOption `json:"options"`
+	} `json:"data"`
+	// Locale is the Discord client language of the user, such as "de"
+	Locale string `json:"locale"`
+}
+
+type discordOption struct {
+	Name    string          `json:"name"`
+	Type    int             `json:"type"`
+	Value   json.RawMessage `json:"value"`
+	Options []discordOption `json:"options"`
+}
+
+// stringValue returns the option's value as text, whether Discord sent it as
+// a string or a number
+func (o discordOption) stringValue() string {
+	var s string
+	if err := json.Unmarshal(o.Value, &s); err == nil {
+		return s
+	}
+	return strings.Trim(string(o.Value), `"`)
+}
+
+type discordEmbed struct {
+	Title       string              `json:"title"`
+	URL         string              `json:"url,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Color       int                 `json:"color,omitempty"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+type discordMessage struct {
+	Content string         `json:"content,omitempty"`
+	Embeds  []discordEmbed `json:"embeds,omitempty"`
+	Flags   int            `json:"flags,omitempty"`
+}
+
+// newDiscordHandler returns the interactions endpoint for the application
+// with the given hex-encoded Ed25519 public key
+func (s *Server) newDiscordHandler(publicKeyHex string) (http.HandlerFunc, error) {
+	key, err := hex.DecodeString(publicKeyHex)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("DISCORD_PUBLIC_KEY must be a %d byte hex-encoded Ed25519 key", ed25519.PublicKeySize)
+	}
+	publicKey := ed25519.PublicKey(key)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, codeBadRequest, "Error reading body")
+			return
+		}
+		if !verifyDiscordSignature(publicKey, r.Header, body) {
+			writeError(w, r, http.StatusUnauthorized, codeUnauthorized, "Invalid request signature")
+			return
+		}
+
+		var interaction discordInteraction
+		if err := json.Unmarshal(body, &interaction); err != nil {
+			writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid interaction")
+			return
+		}
+
+		var response map[string]interface{}
+		switch interaction.Type {
+		case interactionPing:
+			response = map[string]interface{}{"type": responsePong}
+		case interactionApplicationCommand:
+			response = map[string]interface{}{
+				"type": responseChannelMessage,
+				"data": s.runDiscordCommand(r.Context(), interaction),
+			}
+		default:
+			writeError(w, r, http.StatusBadRequest, codeBadRequest, "Unsupported interaction type")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}, nil
+}
+
+// verifyDiscordSignature checks the Ed25519 signature Discord sends over the
+// timestamp followed by the raw request body
+func verifyDiscordSignature(publicKey ed25519.PublicKey, header http.Header, body []byte) bool {
+	signature, err := hex.DecodeString(header.Get("X-Signature-Ed25519"))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return false
+	}
+	timestamp := header.Get("X-Signature-Timestamp")
+	if timestamp == "" {
+		return false
+	}
+	message := append([]byte(timestamp), body...)
+	return ed25519.Verify(publicKey, message, signature)
+}
+
+// runDiscordCommand answers an /ah subcommand
+func (s *Server) runDiscordCommand(ctx context.Context, interaction discordInteraction) discordMessage {
+	if interaction.Data.Name != "ah" || len(interaction.Data.Options) == 0 {
+		return discordError("Unknown command")
+	}
+
+	sub := interaction.Data.Options[0]
+	args := make(map[string]string)
+	for _, option := range sub.Options {
+		args[option.Name] = option.stringValue()
+	}
+
+	house := 0
+	if value := args["house"]; value != "" {
+		var err error
+		house, err = wow.ParseHouse(value)
+		if err != nil {
+			return discordError("Unknown auction house " + value)
+		}
+	}
+
+	// Item names follow the user's Discord language
+	locale, ok := wow.ParseLocale(interaction.Locale)
+	if !ok {
+		locale = wow.DefaultLocale
+	}
+
+	rm := s.defaultRealm()
+	if value := args["realm"]; value != "" {
+		if rm = s.findRealm(value); rm == nil {
+			return discordError("Unknown realm " + value)
+		}
+	}
+
+	var (
+		message discordMessage
+		err     error
+	)
+	switch sub.Name {
+	case "price":
+		message, err = s.discordPrice(ctx, rm, strings.TrimSpace(args["item"]), house, locale)
+	case "seller":
+		message, err = s.discordSeller(ctx, rm, strings.TrimSpace(args["name"]), house, locale)
+	case "stats":
+		message, err = s.discordStats(ctx, rm, house)
+	default:
+		return discordError("Unknown subcommand " + sub.Name)
+	}
+	if err != nil {
+		metrics.QueryErrors.Inc("discord_" + sub.Name)
+		log.Printf("Error running Discord command %s: %v", sub.Name, err)
+		return discordError("Something went wrong looking that up, please try again later")
+	}
+	return message
+}
+
+func discordError(text string) discordMessage {
+	return discordMessage{Content: text, Flags: messageFlagEphemeral}
+}
+
+// discordPrice lists the cheapest listings whose item name contains item
+func (s *Server) discordPrice(ctx context.Context, rm *realm, item string, house int, locale string) (discordMessage, error) {
+	if item == "" {
+		return discordError("Tell me which item to look up"), nil
+	}
+
+	page, err := rm.Auctions.ListAuctions(ctx, store.AuctionQuery{
+		Filter: store.AuctionFilter{ItemNameLike: "%" + store.EscapeLike(item) + "%", House: house},
+		Sort:   "unit_buyout",
+		Page:   1,
+		Limit:  maxDiscordListings,
+		Locale: locale,
+	})
+	if err != nil {
+		return discordMessage{}, err
+	}
+	if len(page.Auctions) == 0 {
+		return discordMessage{Content: fmt.Sprintf("No auctions found for %q", item)}, nil
+	}
+
+	// Listings without a buyout sort last, so the cheapest per unit is the
+	// first with one, if any is on the page
+	cheapest := page.Auctions[0]
+	unitPrice := "no buyout"
+	for _, a := range page.Auctions {
+		if a.BuyoutPrice > 0 {
+			unitPrice = wow.FormatGold(a.UnitBuyout)
+			break
+		}
+	}
+	embed := discordEmbed{
+		Title:       fmt.Sprintf("Price check: %s", item),
+		Description: discordListingLines(page.Auctions, false),
+		Color:       wow.QualityColors[cheapest.Quality],
+		Fields: []discordEmbedField{
+			{Name: "Listings", Value: fmt.Sprintf("%d", page.Total), Inline: true},
+			{Name: "Cheapest per unit", Value: unitPrice, Inline: true},
+		},
+	}
+	if s.baseURL != "" {
+		embed.URL = fmt.Sprintf("%s/items/%d", strings.TrimRight(s.baseURL, "/"), cheapest.ItemEntry)
+		if rm != s.defaultRealm() {
+			embed.URL += fmt.Sprintf("?realm=%d", rm.ID)
+		}
+	}
+	return discordMessage{Embeds: []discordEmbed{embed}}, nil
+}
+
+// discordSeller summarizes one character's auctions and lists the ones
+// ending soonest
+func (s *Server) discordSeller(ctx context.Context, rm *realm, name string, house int, locale string) (discordMessage, error) {
+	if name == "" {
+		return discordError("Tell me which seller to look up"), nil
+	}
+
+	sellers, err := rm.Auctions.Sellers(ctx, house, name)
+	if err != nil {
+		return discordMessage{}, err
+	}
+	if len(sellers) == 0 {
+		return discordMessage{Content: fmt.Sprintf("%s has no active auctions", name)}, nil
+	}
+	seller := sellers[0]
+
+	page, err := rm.Auctions.ListAuctions(ctx, store.AuctionQuery{
+		Filter: store.AuctionFilter{Seller: seller.Name, House: house},
+		Sort:   "time",
+		Page:   1,
+		Limit:  maxDiscordListings,
+		Locale: locale,
+	})
+	if err != nil {
+		return discordMessage{}, err
+	}
+
+	embed := discordEmbed{
+		Title:       fmt.Sprintf("Auctions by %s", seller.Name),
+		Description: discordListingLines(page.Auctions, true),
+		Fields: []discordEmbedField{
+			{Name: "Auctions", Value: fmt.Sprintf("%d", seller.TotalAuctions), Inline: true},
+			{Name: "Total Value", Value: wow.FormatGold(seller.TotalValue), Inline: true},
+			{Name: "Unique Items", Value: fmt.Sprintf("%d", seller.UniqueItems), Inline: true},
+		},
+	}
+	return discordMessage{Embeds: []discordEmbed{embed}}, nil
+}
+
+func (s *Server) discordStats(ctx context.Context, rm *realm, house int) (discordMessage, error) {
+	stats, err := rm.Auctions.Stats(ctx, house)
+	if err != nil {
+		return discordMessage{}, err
+	}
+
+	title := "Auction House Statistics"
+	if house != 0 {
+		title = fmt.Sprintf("Auction House %d Statistics", house)
+	}
+	if len(s.realms) > 1 {
+		title = rm.Name + " " + title
+	}
+	embed := discordEmbed{
+		Title: title,
+		Fields: []discordEmbedField{
+			{Name: "Total Items", Value: fmt.Sprintf("%d", stats.TotalItems), Inline: true},
+			{Name: "Total Value", Value: wow.FormatGold(stats.TotalValue), Inline: true},
+			{Name: "Active Bids", Value: fmt.Sprintf("%d", stats.ActiveBids), Inline: true},
+			{Name: "Unique Sellers", Value: fmt.Sprintf("%d", stats.UniqueOwners), Inline: true},
+			{Name: "Unique Items", Value: fmt.Sprintf("%d", stats.UniqueItems), Inline: true},
+		},
+	}
+	return discordMessage{Embeds: []discordEmbed{embed}}, nil
+}
+
+// discordListingLines renders auctions one per line, including the seller
+// unless every line is by the same one
+func discordListingLines(auctions []store.AuctionItem, sameSeller bool) string {
+	lines := make([]string, 0, len(auctions))
+	for _, auction := range auctions {
+		price := "no buyout"
+		if auction.BuyoutPrice > 0 {
+			price = wow.FormatGold(auction.BuyoutPrice)
+			if auction.Count > 1 {
+				price += fmt.Sprintf(" (%s each)", wow.FormatGold(auction.UnitBuyout))
+			}
+		}
+		line := fmt.Sprintf("**%s** x%d [%s] - %s", auction.ItemName, auction.Count, wow.QualityName(auction.Quality), price)
+		if !sameSeller {
+			line += " by " + auction.OwnerName
+		}
+		lines = append(lines, line+", "+auction.TimeLeft)
+	}
+	return strings.Join(lines, "\n")
+}
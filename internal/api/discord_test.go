@@ -0,0 +1,151 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newDiscordTestServer returns a server accepting interactions signed with
+// the returned private key
+func newDiscordTestServer(t *testing.T) (*Server, ed25519.PrivateKey) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestServer(t, Config{
+		Realms:           testRealms(Realm{Auctions: newTestStore()}),
+		BaseURL:          "https://ah.example.com/",
+		DiscordPublicKey: hex.EncodeToString(public),
+	})
+	return s, private
+}
+
+func postInteraction(t *testing.T, s *Server, key ed25519.PrivateKey, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	timestamp := "1717243200"
+	req := httptest.NewRequest(http.MethodPost, "/api/discord/interactions", strings.NewReader(body))
+	req.Header.Set("X-Signature-Timestamp", timestamp)
+	req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(key, []byte(timestamp+body))))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+type interactionResponse struct {
+	Type int            `json:"type"`
+	Data discordMessage `json:"data"`
+}
+
+func runCommand(t *testing.T, s *Server, key ed25519.PrivateKey, body string) discordMessage {
+	t.Helper()
+	rec := postInteraction(t, s, key, body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var resp interactionResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Type != responseChannelMessage {
+		t.Fatalf("response type = %d, want %d", resp.Type, responseChannelMessage)
+	}
+	return resp.Data
+}
+
+func TestNewRejectsInvalidDiscordKey(t *testing.T) {
+	if _, err := New(Config{Realms: testRealms(Realm{Auctions: newTestStore()}), DiscordPublicKey: "abcd"}); err == nil {
+		t.Error("New() accepted a short Discord public key")
+	}
+}
+
+func TestDiscordInteractionsNotServedWithoutKey(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/discord/interactions", strings.NewReader(`{"type":1}`)))
+	if rec.Code != http.StatusMethodNotAllowed && rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want the endpoint to be unregistered", rec.Code)
+	}
+}
+
+func TestDiscordSignature(t *testing.T) {
+	s, key := newDiscordTestServer(t)
+
+	rec := postInteraction(t, s, key, `{"type":1}`)
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"type":1}` {
+		t.Errorf("ping: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	_, otherKey, _ := ed25519.GenerateKey(nil)
+	if rec := postInteraction(t, s, otherKey, `{"type":1}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong key: status = %d, want 401", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/discord/interactions", strings.NewReader(`{"type":1}`))
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned: status = %d, want 401", rec.Code)
+	}
+}
+
+func TestDiscordPrice(t *testing.T) {
+	s, key := newDiscordTestServer(t)
+
+	msg := runCommand(t, s, key, `{"type":2,"data":{"name":"ah","options":[{"name":"price","type":1,"options":[{"name":"item","type":3,"value":"linen"}]}]}}`)
+	if len(msg.Embeds) != 1 {
+		t.Fatalf("message = %+v, want one embed", msg)
+	}
+	embed := msg.Embeds[0]
+	if embed.URL != "https://ah.example.com/items/2589" {
+		t.Errorf("embed URL = %q", embed.URL)
+	}
+	lines := strings.Split(embed.Description, "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "**Linen Cloth** x1 [Common] - 90c by Bob") {
+		t.Errorf("description = %q, want the cheapest per unit first", embed.Description)
+	}
+	if f := embed.Fields[1]; f.Name != "Cheapest per unit" || f.Value != "90c" {
+		t.Errorf("cheapest field = %+v, want 90c", f)
+	}
+
+	// Wool Cloth is only up for bids
+	msg = runCommand(t, s, key, `{"type":2,"data":{"name":"ah","options":[{"name":"price","type":1,"options":[{"name":"item","type":3,"value":"wool"}]}]}}`)
+	if f := msg.Embeds[0].Fields[1]; f.Value != "no buyout" {
+		t.Errorf("cheapest field = %+v, want no buyout", f)
+	}
+
+	msg = runCommand(t, s, key, `{"type":2,"data":{"name":"ah","options":[{"name":"price","type":1,"options":[{"name":"item","type":3,"value":"linen"},{"name":"house","type":3,"value":"alliance"}]}]}}`)
+	if got := strings.Count(msg.Embeds[0].Description, "\n") + 1; got != 2 {
+		t.Errorf("alliance listings = %d, want 2", got)
+	}
+
+	msg = runCommand(t, s, key, `{"type":2,"data":{"name":"ah","options":[{"name":"price","type":1,"options":[{"name":"item","type":3,"value":"mageweave"}]}]}}`)
+	if !strings.Contains(msg.Content, "No auctions found") {
+		t.Errorf("content = %q", msg.Content)
+	}
+}
+
+func TestDiscordSellerAndStats(t *testing.T) {
+	s, key := newDiscordTestServer(t)
+
+	msg := runCommand(t, s, key, `{"type":2,"data":{"name":"ah","options":[{"name":"seller","type":1,"options":[{"name":"name","type":3,"value":"Alice"}]}]}}`)
+	if len(msg.Embeds) != 1 || msg.Embeds[0].Title != "Auctions by Alice" || msg.Embeds[0].Fields[0].Value != "2" {
+		t.Errorf("seller message = %+v", msg)
+	}
+
+	msg = runCommand(t, s, key, `{"type":2,"data":{"name":"ah","options":[{"name":"stats","type":1,"options":[{"name":"house","type":3,"value":"horde"}]}]}}`)
+	if len(msg.Embeds) != 1 || msg.Embeds[0].Title != "Auction House 6 Statistics" || msg.Embeds[0].Fields[0].Value != "2" {
+		t.Errorf("stats message = %+v", msg)
+	}
+
+	msg = runCommand(t, s, key, `{"type":2,"data":{"name":"ah","options":[{"name":"stats","type":1,"options":[{"name":"house","type":3,"value":"gnome"}]}]}}`)
+	if msg.Flags != messageFlagEphemeral {
+		t.Errorf("unknown house reply = %+v, want an ephemeral error", msg)
+	}
+}
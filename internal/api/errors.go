@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/metrics"
+)
+
+// Error codes identify the kind of failure in an error response, so clients
+// can branch on them rather than on the message
+const (
+	codeBadRequest       = "bad_request"
+	codeUnauthorized     = "unauthorized"
+	codeForbidden        = "forbidden"
+	codeNotFound         = "not_found"
+	codeMethodNotAllowed = "method_not_allowed"
+	codeUnavailable      = "unavailable"
+	codeInternal         = "internal_error"
+)
+
+// requestIDHeader carries the request ID to and from clients
+const requestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// apiError is the body of every error response:
+//
+//	{"error": {"code": "not_found", "message": "Item not found", "request_id": "..."}}
+type apiError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// withRequestID attaches a request ID to r and echoes it in the response. A
+// well-formed ID supplied by the client or a proxy is kept, so logs can be
+// correlated across services.
+func withRequestID(w http.ResponseWriter, r *http.Request) *http.Request {
+	id := r.Header.Get(requestIDHeader)
+	if !validRequestID(id) {
+		var b [8]byte
+		rand.Read(b[:])
+		id = hex.EncodeToString(b[:])
+	}
+	w.Header().Set(requestIDHeader, id)
+	return r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id))
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// requestID returns the ID attached by withRequestID
+func requestID(r *http.Request) string {
+	id, _ := r.Context().Value(requestIDKey{}).(string)
+	return id
+}
+
+// writeError writes an error response. The message is shown to clients, so it
+// must never include internal details such as SQL errors.
+func writeError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]apiError{
+		"error": {Code: code, Message: message, RequestID: requestID(r)},
+	})
+}
+
+// writeQueryError logs a failed database query under the request ID, counts
+// it against the route and reports a generic error to the client
+func writeQueryError(w http.ResponseWriter, r *http.Request, err error) {
+	metrics.QueryErrors.Inc(r.Pattern)
+	log.Printf("[%s] %s: %v", requestID(r), r.Pattern, err)
+	writeError(w, r, http.StatusInternalServerError, codeInternal, "Internal server error")
+}
+
+// unmatchedWriter replaces the plain text 404 and 405 responses the ServeMux
+// writes for requests no route handles with error envelopes
+type unmatchedWriter struct {
+	http.ResponseWriter
+	r     *http.Request
+	wrote bool
+}
+
+func (u *unmatchedWriter) WriteHeader(status int) {
+	if u.wrote {
+		return
+	}
+	u.wrote = true
+	code, message := codeNotFound, "Not found"
+	if status == http.StatusMethodNotAllowed {
+		code, message = codeMethodNotAllowed, "Method not allowed"
+	}
+	writeError(u.ResponseWriter, u.r, status, code, message)
+}
+
+// Write discards the ServeMux's own message
+func (u *unmatchedWriter) Write(b []byte) (int, error) {
+	if !u.wrote {
+		u.WriteHeader(http.StatusNotFound)
+	}
+	return len(b), nil
+}
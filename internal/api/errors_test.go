@@ -0,0 +1,78 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type errorResponse struct {
+	Error apiError `json:"error"`
+}
+
+func TestQueryErrorsAreNotLeaked(t *testing.T) {
+	m := newTestStore()
+	m.Err = errors.New("Error 1054 (42S22): Unknown column 'ah.secret' in 'field list'")
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m})})
+
+	var resp errorResponse
+	rec := get(t, s, "/api/auctions", http.StatusInternalServerError, &resp)
+	if resp.Error.Code != codeInternal || strings.Contains(resp.Error.Message, "1054") {
+		t.Errorf("error = %+v, want a generic internal error", resp.Error)
+	}
+	if id := rec.Header().Get(requestIDHeader); id == "" || resp.Error.RequestID != id {
+		t.Errorf("request_id = %q, header = %q, want them to match", resp.Error.RequestID, id)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestErrorEnvelope(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	tests := []struct {
+		method, target string
+		status         int
+		code           string
+	}{
+		{http.MethodGet, "/api/search", http.StatusBadRequest, codeBadRequest},
+		{http.MethodGet, "/api/auctions?house=gnome", http.StatusBadRequest, codeBadRequest},
+		{http.MethodGet, "/api/items/999", http.StatusNotFound, codeNotFound},
+		{http.MethodGet, "/api/items/abc", http.StatusBadRequest, codeBadRequest},
+		{http.MethodGet, "/api/items/2589/history", http.StatusServiceUnavailable, codeUnavailable},
+		{http.MethodGet, "/api/nope", http.StatusNotFound, codeNotFound},
+		{http.MethodGet, "/favicon.ico", http.StatusNotFound, codeNotFound},
+		{http.MethodPost, "/api/auctions", http.StatusMethodNotAllowed, codeMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		var resp errorResponse
+		send(t, s, tt.method, tt.target, "", tt.status, &resp)
+		if resp.Error.Code != tt.code || resp.Error.Message == "" || resp.Error.RequestID == "" {
+			t.Errorf("%s %s error = %+v, want code %q", tt.method, tt.target, resp.Error, tt.code)
+		}
+	}
+}
+
+func TestRequestIDFromClient(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	for id, keep := range map[string]bool{
+		"edge-7f3a.1":           true,
+		"bad id":                false,
+		strings.Repeat("a", 65): false,
+		"":                      false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+		req.Header.Set(requestIDHeader, id)
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+
+		got := rec.Header().Get(requestIDHeader)
+		if got == "" || (got == id) != keep {
+			t.Errorf("X-Request-ID %q answered with %q, want kept = %v", id, got, keep)
+		}
+	}
+}
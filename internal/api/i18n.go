@@ -0,0 +1,1379 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// localeCookie remembers the language picked in the web interface
+const localeCookie = "locale"
+
+var errInvalidLocale = errors.New("invalid locale")
+
+// parseLocale picks the locale of item names from the locale parameter, the
+// locale cookie, then the Accept-Language header, defaulting to enUS. Only
+// an invalid locale parameter is an error. The response is marked as
+// varying with the headers consulted.
+func parseLocale(w http.ResponseWriter, r *http.Request) (string, error) {
+	w.Header().Add("Vary", "Accept-Language, Cookie")
+
+	if value := r.URL.Query().Get("locale"); value != "" {
+		locale, ok := wow.ParseLocale(value)
+		if !ok {
+			return "", errInvalidLocale
+		}
+		return locale, nil
+	}
+	if cookie, err := r.Cookie(localeCookie); err == nil {
+		if locale, ok := wow.ParseLocale(cookie.Value); ok {
+			return locale, nil
+		}
+	}
+	if locale, ok := acceptLanguage(r.Header.Get("Accept-Language")); ok {
+		return locale, nil
+	}
+	return wow.DefaultLocale, nil
+}
+
+// pageLocale is parseLocale for HTML pages, which ignore an invalid locale
+// parameter
+func pageLocale(w http.ResponseWriter, r *http.Request) string {
+	locale, err := parseLocale(w, r)
+	if err != nil {
+		return wow.DefaultLocale
+	}
+	return locale
+}
+
+// acceptLanguage returns the game locale an Accept-Language header prefers
+func acceptLanguage(header string) (string, bool) {
+	type choice struct {
+		tag     string
+		quality float64
+	}
+	var choices []choice
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if quality, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if tag != "" && tag != "*" && quality > 0 {
+			choices = append(choices, choice{tag, quality})
+		}
+	}
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].quality > choices[j].quality })
+
+	for _, c := range choices {
+		if locale, ok := wow.ParseLocale(c.tag); ok {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// language is an entry of the language switcher
+type language struct {
+	Locale string
+	Name   string
+}
+
+// languages lists every game locale by its own name. Interface strings fall
+// back to English where there is no translation, item names to enUS.
+var languages = []language{
+	{"enUS", "English"},
+	{"deDE", "Deutsch"},
+	{"frFR", "Français"},
+	{"esES", "Español (España)"},
+	{"esMX", "Español (México)"},
+	{"ruRU", "Русский"},
+	{"koKR", "한국어"},
+	{"zhCN", "简体中文"},
+	{"zhTW", "繁體中文"},
+}
+
+// htmlLang returns the lang attribute of pages in locale, such as "de-DE"
+func htmlLang(locale string) string {
+	return locale[:2] + "-" + locale[2:]
+}
+
+// uiStrings are the translated interface strings of the web pages, by locale
+// and key. English is complete; other locales may leave keys out.
+var uiStrings = map[string]map[string]string{
+	"enUS": {
+		"title":                   "WoW Auction House Viewer",
+		"subtitle":                "Real-time auction house data from your AzerothCore server",
+		"totalItems":              "Total Items",
+		"totalValue":              "Total Value (Gold)",
+		"activeBids":              "Active Bids",
+		"uniqueSellers":           "Unique Sellers",
+		"realm":                   "Realm",
+		"auctionHouse":            "Auction house",
+		"allHouses":               "All Auction Houses",
+		"language":                "Language",
+		"searchHint":              "Search by item name, suffix or seller...",
+		"search":                  "Search",
+		"refresh":                 "Refresh",
+		"showSellers":             "Show Sellers",
+		"hideSellers":             "Hide Sellers",
+		"activeSellers":           "Active Sellers",
+		"sellerName":              "Seller Name",
+		"totalAuctions":           "Total Auctions",
+		"uniqueItems":             "Unique Items",
+		"activeAuctions":          "Active Auctions",
+		"item":                    "Item",
+		"quality":                 "Quality",
+		"level":                   "Level",
+		"count":                   "Count",
+		"seller":                  "Seller",
+		"currentBid":              "Current Bid",
+		"buyout":                  "Buyout",
+		"perUnit":                 "Per Unit",
+		"timeLeft":                "Time Left",
+		"loading":                 "Loading...",
+		"loadingSellers":          "Loading sellers...",
+		"loadingAuctions":         "Loading auctions...",
+		"errorSellers":            "Error loading sellers",
+		"errorAuctions":           "Error loading auctions",
+		"noSellers":               "No sellers found",
+		"noAuctions":              "No auctions found",
+		"noBuyout":                "No Buyout",
+		"previous":                "Previous",
+		"next":                    "Next",
+		"qualityPoor":             "Poor",
+		"qualityCommon":           "Common",
+		"qualityUncommon":         "Uncommon",
+		"qualityRare":             "Rare",
+		"qualityEpic":             "Epic",
+		"qualityLegendary":        "Legendary",
+		"qualityUnknown":          "Unknown",
+		"backToAuctions":          "Back to auctions",
+		"viewOnWowhead":           "View on Wowhead",
+		"lowestPerUnit":           "Lowest (per unit)",
+		"medianPerUnit":           "Median (per unit)",
+		"highestPerUnit":          "Highest (per unit)",
+		"quantityListed":          "Quantity Listed",
+		"vendorSellsFor":          "Vendor Sells For",
+		"vendorBuyPrice":          "Vendor Buy Price",
+		"priceHistory":            "Price History (7 days, median per unit)",
+		"loadingHistory":          "Loading history...",
+		"currentListings":         "Current Listings",
+		"loadingListings":         "Loading listings...",
+		"itemNotFound":            "Item not found",
+		"errorItem":               "Error loading item",
+		"noListings":              "No current listings",
+		"historyDisabled":         "Price history is not available",
+		"errorHistory":            "Error loading price history",
+		"historyTooShort":         "Not enough price history yet",
+		"low":                     "Low",
+		"high":                    "High",
+		"newAuctions":             "{n} new auctions",
+		"showNew":                 "Show",
+		"sold":                    "Sold",
+		"expired":                 "Expired",
+		"cancelled":               "Cancelled",
+		"removed":                 "Ended",
+		"tabAuctions":             "Auctions",
+		"tabDeals":                "Deals",
+		"maxPercent":              "Up to",
+		"defaultOption":           "Default",
+		"marketPrice":             "Market Price",
+		"percentOfMarket":         "% of Market",
+		"profit":                  "Profit",
+		"noDeals":                 "No deals found",
+		"errorDeals":              "Error loading deals",
+		"sourceAuctionator":       "mod_auctionator market price",
+		"sourceMedian":            "7-day median price",
+		"tabFlips":                "Vendor Flips",
+		"allQualities":            "All",
+		"price":                   "Price",
+		"bid":                     "bid",
+		"vendorValue":             "Vendor Value",
+		"noFlips":                 "Nothing sells to a vendor for more right now",
+		"errorFlips":              "Error loading vendor flips",
+		"ahbotTitle":              "Auction House Bot",
+		"adminToken":              "Admin token",
+		"signIn":                  "Sign in",
+		"signOut":                 "Sign out",
+		"adminTokenRejected":      "The admin token was not accepted",
+		"ahbotRestartNote":        "The worldserver reads these settings when it starts, so changes apply after a restart. Live counts are of every auction, not only the bot's.",
+		"ahbotNotInstalled":       "mod_auctionhousebot is not installed",
+		"errorAHBot":              "Error loading the auction house bot",
+		"minItems":                "Min items",
+		"maxItems":                "Max items",
+		"biddingInterval":         "Bidding interval (minutes)",
+		"bidsPerInterval":         "Bids per interval",
+		"liveListings":            "Live listings",
+		"tradeGoods":              "Trade goods",
+		"otherItems":              "Other items",
+		"live":                    "Live",
+		"minPrice":                "Min price %",
+		"maxPrice":                "Max price %",
+		"minBidPrice":             "Min bid %",
+		"maxBidPrice":             "Max bid %",
+		"maxStack":                "Max stack",
+		"buyerPrice":              "Buyer price ×",
+		"qualityArtifact":         "Artifact",
+		"percentTotal":            "Total: {n}%",
+		"save":                    "Save",
+		"saved":                   "Saved",
+		"disabledItems":           "Disabled items",
+		"itemEntry":               "Item entry",
+		"add":                     "Add",
+		"remove":                  "Remove",
+		"noDisabledItems":         "No items are disabled",
+		"auditLog":                "Audit log",
+		"time":                    "Time",
+		"actor":                   "By",
+		"action":                  "Action",
+		"target":                  "Target",
+		"noAuditEntries":          "No changes recorded yet",
+		"auditUnavailable":        "The audit log is not available",
+		"tabMarket":               "Market Comparison",
+		"auctionatorPrice":        "Auctionator Price",
+		"scanned":                 "Scanned",
+		"listings":                "Listings",
+		"units":                   "Units",
+		"lowest":                  "Lowest",
+		"median":                  "Median",
+		"order":                   "Order",
+		"cheapestFirst":           "Cheapest first",
+		"priciestFirst":           "Priciest first",
+		"noComparisons":           "No listed item has a mod_auctionator market price",
+		"errorMarket":             "Error loading the market comparison",
+		"auctionatorTitle":        "Auctionator Settings",
+		"auctionatorNote":         "These settings decide how many items of each class mod_auctionator's seller lists, and in what stacks.",
+		"itemClasses":             "Item classes",
+		"itemClass":               "Class",
+		"className":               "Name",
+		"lowestQuality":           "Lowest quality",
+		"maxCount":                "Max count",
+		"stackCount":              "Stack size",
+		"errorAuctionator":        "Error loading the mod_auctionator settings",
+		"auctionatorNotInstalled": "mod_auctionator is not installed",
+		"signInWithAccount":       "Sign in with your game account",
+		"signedInAs":              "Signed in as",
+		"username":                "Account name",
+		"password":                "Password",
+		"loginNote":               "Use the account name and password you log in to the game with.",
+		"errorSignIn":             "Could not sign in",
+		"tabMyAuctions":           "My auctions",
+		"noMyAuctions":            "None of your characters has an auction",
+		"errorMyAuctions":         "Error loading your auctions",
+		"itemLevelN":              "Item Level {n}",
+		"bindPickup":              "Binds when picked up",
+		"bindEquip":               "Binds when equipped",
+		"bindUse":                 "Binds when used",
+		"questItem":               "Quest Item",
+		"unique":                  "Unique",
+		"bagSlots":                "{n} Slot Bag",
+		"armorValue":              "{n} Armor",
+		"blockValue":              "{n} Block",
+		"damageRange":             "{min} - {max} Damage",
+		"damageRangeSchool":       "+{min} - {max} {school} Damage",
+		"speed":                   "Speed {n}",
+		"dps":                     "({n} damage per second)",
+		"resistance":              "+{n} {school} Resistance",
+		"schoolHoly":              "Holy",
+		"schoolFire":              "Fire",
+		"schoolNature":            "Nature",
+		"schoolFrost":             "Frost",
+		"schoolShadow":            "Shadow",
+		"schoolArcane":            "Arcane",
+		"statMana":                "+{n} Mana",
+		"statHealth":              "+{n} Health",
+		"statAgility":             "+{n} Agility",
+		"statStrength":            "+{n} Strength",
+		"statIntellect":           "+{n} Intellect",
+		"statSpirit":              "+{n} Spirit",
+		"statStamina":             "+{n} Stamina",
+		"statDefense":             "Increases defense rating by {n}.",
+		"statDodge":               "Increases your dodge rating by {n}.",
+		"statParry":               "Increases your parry rating by {n}.",
+		"statBlock":               "Increases your shield block rating by {n}.",
+		"statHit":                 "Improves hit rating by {n}.",
+		"statCrit":                "Improves critical strike rating by {n}.",
+		"statResilience":          "Improves your resilience rating by {n}.",
+		"statHaste":               "Improves haste rating by {n}.",
+		"statExpertise":           "Increases your expertise rating by {n}.",
+		"statAttackPower":         "Increases attack power by {n}.",
+		"statRangedAttackPower":   "Increases ranged attack power by {n}.",
+		"statFeralAttackPower":    "Increases attack power by {n} in Cat, Bear, Dire Bear, and Moonkin forms only.",
+		"statHealing":             "Increases healing done by spells and effects by up to {n}.",
+		"statSpellDamage":         "Increases damage done by magical spells and effects by up to {n}.",
+		"statManaRegen":           "Restores {n} mana per 5 sec.",
+		"statArmorPen":            "Increases your armor penetration rating by {n}.",
+		"statSpellPower":          "Increases spell power by {n}.",
+		"statHealthRegen":         "Restores {n} health per 5 sec.",
+		"statSpellPen":            "Increases your spell penetration by {n}.",
+		"statBlockValue":          "Increases the block value of your shield by {n}.",
+		"statOther":               "Increases stat {type} by {n}.",
+		"socketMeta":              "Meta Socket",
+		"socketRed":               "Red Socket",
+		"socketYellow":            "Yellow Socket",
+		"socketBlue":              "Blue Socket",
+		"socketBonus":             "Socket Bonus: {name}",
+		"durability":              "Durability {n} / {n}",
+		"requiresLevel":           "Requires Level {n}",
+		"triggerUse":              "Use:",
+		"triggerEquip":            "Equip:",
+		"triggerChance":           "Chance on hit:",
+		"setPieces":               "Set pieces",
+		"sellPrice":               "Sell Price:",
+		"slotHead":                "Head",
+		"slotNeck":                "Neck",
+		"slotShoulder":            "Shoulder",
+		"slotShirt":               "Shirt",
+		"slotChest":               "Chest",
+		"slotWaist":               "Waist",
+		"slotLegs":                "Legs",
+		"slotFeet":                "Feet",
+		"slotWrist":               "Wrist",
+		"slotHands":               "Hands",
+		"slotFinger":              "Finger",
+		"slotTrinket":             "Trinket",
+		"slotOneHand":             "One-Hand",
+		"slotOffHand":             "Off Hand",
+		"slotRanged":              "Ranged",
+		"slotBack":                "Back",
+		"slotTwoHand":             "Two-Hand",
+		"slotTabard":              "Tabard",
+		"slotMainHand":            "Main Hand",
+		"slotHeldOffHand":         "Held In Off-hand",
+		"slotProjectile":          "Projectile",
+		"slotThrown":              "Thrown",
+		"slotRelic":               "Relic",
+		"weaponAxe":               "Axe",
+		"weaponBow":               "Bow",
+		"weaponGun":               "Gun",
+		"weaponMace":              "Mace",
+		"weaponPolearm":           "Polearm",
+		"weaponSword":             "Sword",
+		"weaponStaff":             "Staff",
+		"weaponFist":              "Fist Weapon",
+		"weaponMisc":              "Miscellaneous",
+		"weaponDagger":            "Dagger",
+		"weaponCrossbow":          "Crossbow",
+		"weaponWand":              "Wand",
+		"weaponFishingPole":       "Fishing Pole",
+		"armorCloth":              "Cloth",
+		"armorLeather":            "Leather",
+		"armorMail":               "Mail",
+		"armorPlate":              "Plate",
+		"armorShield":             "Shield",
+		"armorLibram":             "Libram",
+		"armorIdol":               "Idol",
+		"armorTotem":              "Totem",
+		"armorSigil":              "Sigil",
+		"pricesBySuffix":          "Prices by Suffix",
+		"suffix":                  "Suffix",
+		"tabCrafting":             "Crafting",
+		"profession":              "Profession",
+		"skill":                   "Skill",
+		"reagents":                "Reagents",
+		"reagentCost":             "Reagent Cost",
+		"afterCut":                "After AH Cut",
+		"margin":                  "Margin",
+		"notListed":               "not listed",
+		"noProfessions":           "No recipes found in the world database",
+		"noRecipes":               "This profession has no recipes that create items",
+		"errorCrafting":           "Error loading crafting prices",
+		"tabYields":               "Disenchant & Mill",
+		"method":                  "Method",
+		"allMethods":              "All methods",
+		"methodDisenchant":        "Disenchant",
+		"methodMilling":           "Milling",
+		"methodProspecting":       "Prospecting",
+		"belowYieldOnly":          "Below yield only",
+		"expectedYield":           "Expected Yield",
+		"yieldValue":              "Yield Value",
+		"noYields":                "No listing costs less than what it breaks down into right now",
+		"errorYields":             "Error loading yields",
+		"itemSources":             "Sources",
+		"droppedBy":               "Dropped by",
+		"soldBy":                  "Sold by",
+		"questRewards":            "Quest rewards",
+		"kindCreature":            "Creature",
+		"kindGameObject":          "Object",
+		"dropChance":              "Drop chance",
+		"averageCount":            "Average count",
+		"stock":                   "Stock",
+		"unlimited":               "Unlimited",
+		"honorPoints":             "Honor",
+		"arenaPoints":             "Arena points",
+		"quest":                   "Quest",
+		"rewardChoice":            "choice",
+		"noSources":               "No drops, vendors or quest rewards are known for this item",
+		"errorSources":            "Error loading item sources",
+		"overpricedNote":          "{n} listings cost {percent}% or more of the vendor price",
+		"overpriced":              "Above vendor price",
+	},
+	"deDE": {
+		"title":                   "WoW-Auktionshaus",
+		"subtitle":                "Aktuelle Auktionshausdaten deines AzerothCore-Servers",
+		"totalItems":              "Gegenstände",
+		"totalValue":              "Gesamtwert (Gold)",
+		"activeBids":              "Aktive Gebote",
+		"uniqueSellers":           "Verkäufer",
+		"realm":                   "Realm",
+		"auctionHouse":            "Auktionshaus",
+		"allHouses":               "Alle Auktionshäuser",
+		"language":                "Sprache",
+		"searchHint":              "Nach Gegenstand, Suffix oder Verkäufer suchen...",
+		"search":                  "Suchen",
+		"refresh":                 "Aktualisieren",
+		"showSellers":             "Verkäufer anzeigen",
+		"hideSellers":             "Verkäufer ausblenden",
+		"activeSellers":           "Aktive Verkäufer",
+		"sellerName":              "Verkäufer",
+		"totalAuctions":           "Auktionen",
+		"uniqueItems":             "Verschiedene Gegenstände",
+		"activeAuctions":          "Aktive Auktionen",
+		"item":                    "Gegenstand",
+		"quality":                 "Qualität",
+		"level":                   "Stufe",
+		"count":                   "Anzahl",
+		"seller":                  "Verkäufer",
+		"currentBid":              "Aktuelles Gebot",
+		"buyout":                  "Sofortkauf",
+		"perUnit":                 "Pro Stück",
+		"timeLeft":                "Restzeit",
+		"loading":                 "Wird geladen...",
+		"loadingSellers":          "Verkäufer werden geladen...",
+		"loadingAuctions":         "Auktionen werden geladen...",
+		"errorSellers":            "Fehler beim Laden der Verkäufer",
+		"errorAuctions":           "Fehler beim Laden der Auktionen",
+		"noSellers":               "Keine Verkäufer gefunden",
+		"noAuctions":              "Keine Auktionen gefunden",
+		"noBuyout":                "Kein Sofortkauf",
+		"previous":                "Zurück",
+		"next":                    "Weiter",
+		"qualityPoor":             "Schlecht",
+		"qualityCommon":           "Verbreitet",
+		"qualityUncommon":         "Selten",
+		"qualityRare":             "Rar",
+		"qualityEpic":             "Episch",
+		"qualityLegendary":        "Legendär",
+		"qualityUnknown":          "Unbekannt",
+		"backToAuctions":          "Zurück zu den Auktionen",
+		"viewOnWowhead":           "Auf Wowhead ansehen",
+		"lowestPerUnit":           "Niedrigster (pro Stück)",
+		"medianPerUnit":           "Median (pro Stück)",
+		"highestPerUnit":          "Höchster (pro Stück)",
+		"quantityListed":          "Angebotene Menge",
+		"vendorSellsFor":          "Händler zahlt",
+		"vendorBuyPrice":          "Händlerpreis",
+		"priceHistory":            "Preisverlauf (7 Tage, Median pro Stück)",
+		"loadingHistory":          "Verlauf wird geladen...",
+		"currentListings":         "Aktuelle Angebote",
+		"loadingListings":         "Angebote werden geladen...",
+		"itemNotFound":            "Gegenstand nicht gefunden",
+		"errorItem":               "Fehler beim Laden des Gegenstands",
+		"noListings":              "Keine aktuellen Angebote",
+		"historyDisabled":         "Preisverlauf ist nicht verfügbar",
+		"errorHistory":            "Fehler beim Laden des Preisverlaufs",
+		"historyTooShort":         "Noch nicht genug Preisverlauf",
+		"low":                     "Tief",
+		"high":                    "Hoch",
+		"newAuctions":             "{n} neue Auktionen",
+		"showNew":                 "Anzeigen",
+		"sold":                    "Verkauft",
+		"expired":                 "Abgelaufen",
+		"cancelled":               "Abgebrochen",
+		"removed":                 "Beendet",
+		"tabAuctions":             "Auktionen",
+		"tabDeals":                "Schnäppchen",
+		"maxPercent":              "Bis zu",
+		"defaultOption":           "Standard",
+		"marketPrice":             "Marktpreis",
+		"percentOfMarket":         "% vom Markt",
+		"profit":                  "Gewinn",
+		"noDeals":                 "Keine Schnäppchen gefunden",
+		"errorDeals":              "Fehler beim Laden der Schnäppchen",
+		"sourceAuctionator":       "Marktpreis von mod_auctionator",
+		"sourceMedian":            "Median der letzten 7 Tage",
+		"tabFlips":                "Händler-Flips",
+		"allQualities":            "Alle",
+		"price":                   "Preis",
+		"bid":                     "Gebot",
+		"vendorValue":             "Händlerwert",
+		"noFlips":                 "Derzeit zahlt kein Händler mehr",
+		"errorFlips":              "Fehler beim Laden der Händler-Flips",
+		"ahbotTitle":              "Auktionshaus-Bot",
+		"adminToken":              "Admin-Token",
+		"signIn":                  "Anmelden",
+		"signOut":                 "Abmelden",
+		"adminTokenRejected":      "Das Admin-Token wurde nicht akzeptiert",
+		"ahbotRestartNote":        "Der Worldserver liest diese Einstellungen beim Start, Änderungen gelten also nach einem Neustart. Die Live-Zahlen umfassen alle Auktionen, nicht nur die des Bots.",
+		"ahbotNotInstalled":       "mod_auctionhousebot ist nicht installiert",
+		"errorAHBot":              "Fehler beim Laden des Auktionshaus-Bots",
+		"minItems":                "Min. Gegenstände",
+		"maxItems":                "Max. Gegenstände",
+		"biddingInterval":         "Gebotsintervall (Minuten)",
+		"bidsPerInterval":         "Gebote pro Intervall",
+		"liveListings":            "Aktuelle Auktionen",
+		"tradeGoods":              "Handwerkswaren",
+		"otherItems":              "Andere Gegenstände",
+		"live":                    "Aktuell",
+		"minPrice":                "Min. Preis %",
+		"maxPrice":                "Max. Preis %",
+		"minBidPrice":             "Min. Gebot %",
+		"maxBidPrice":             "Max. Gebot %",
+		"maxStack":                "Max. Stapel",
+		"buyerPrice":              "Kaufpreis ×",
+		"qualityArtifact":         "Artefakt",
+		"percentTotal":            "Summe: {n}%",
+		"save":                    "Speichern",
+		"saved":                   "Gespeichert",
+		"disabledItems":           "Gesperrte Gegenstände",
+		"itemEntry":               "Gegenstands-ID",
+		"add":                     "Hinzufügen",
+		"remove":                  "Entfernen",
+		"noDisabledItems":         "Keine Gegenstände gesperrt",
+		"auditLog":                "Änderungsprotokoll",
+		"time":                    "Zeit",
+		"actor":                   "Von",
+		"action":                  "Aktion",
+		"target":                  "Ziel",
+		"noAuditEntries":          "Noch keine Änderungen protokolliert",
+		"auditUnavailable":        "Das Änderungsprotokoll ist nicht verfügbar",
+		"tabMarket":               "Marktvergleich",
+		"auctionatorPrice":        "Auctionator-Preis",
+		"scanned":                 "Erfasst",
+		"listings":                "Angebote",
+		"units":                   "Stück",
+		"lowest":                  "Niedrigster",
+		"median":                  "Median",
+		"order":                   "Reihenfolge",
+		"cheapestFirst":           "Günstigste zuerst",
+		"priciestFirst":           "Teuerste zuerst",
+		"noComparisons":           "Kein angebotener Gegenstand hat einen Marktpreis von mod_auctionator",
+		"errorMarket":             "Fehler beim Laden des Marktvergleichs",
+		"auctionatorTitle":        "Auctionator-Einstellungen",
+		"auctionatorNote":         "Diese Einstellungen legen fest, wie viele Gegenstände jeder Klasse der Verkäufer von mod_auctionator anbietet und in welchen Stapeln.",
+		"itemClasses":             "Gegenstandsklassen",
+		"itemClass":               "Klasse",
+		"className":               "Name",
+		"lowestQuality":           "Mindestqualität",
+		"maxCount":                "Höchstanzahl",
+		"stackCount":              "Stapelgröße",
+		"errorAuctionator":        "Fehler beim Laden der mod_auctionator-Einstellungen",
+		"auctionatorNotInstalled": "mod_auctionator ist nicht installiert",
+		"signInWithAccount":       "Mit dem Spielkonto anmelden",
+		"signedInAs":              "Angemeldet als",
+		"username":                "Kontoname",
+		"password":                "Passwort",
+		"loginNote":               "Verwende den Kontonamen und das Passwort, mit denen du dich im Spiel anmeldest.",
+		"errorSignIn":             "Anmeldung fehlgeschlagen",
+		"tabMyAuctions":           "Meine Auktionen",
+		"noMyAuctions":            "Keiner deiner Charaktere hat eine Auktion",
+		"errorMyAuctions":         "Fehler beim Laden deiner Auktionen",
+		"itemLevelN":              "Gegenstandsstufe {n}",
+		"bindPickup":              "Wird beim Aufheben gebunden",
+		"bindEquip":               "Wird beim Anlegen gebunden",
+		"bindUse":                 "Wird bei Benutzung gebunden",
+		"questItem":               "Questgegenstand",
+		"unique":                  "Einzigartig",
+		"bagSlots":                "Tasche mit {n} Plätzen",
+		"armorValue":              "{n} Rüstung",
+		"blockValue":              "{n} Blocken",
+		"damageRange":             "{min} - {max} Schaden",
+		"damageRangeSchool":       "+{min} - {max} {school}schaden",
+		"speed":                   "Tempo {n}",
+		"dps":                     "({n} Schaden pro Sekunde)",
+		"resistance":              "+{n} {school}widerstand",
+		"schoolHoly":              "Heilig",
+		"schoolFire":              "Feuer",
+		"schoolNature":            "Natur",
+		"schoolFrost":             "Frost",
+		"schoolShadow":            "Schatten",
+		"schoolArcane":            "Arkan",
+		"statMana":                "+{n} Mana",
+		"statHealth":              "+{n} Gesundheit",
+		"statAgility":             "+{n} Beweglichkeit",
+		"statStrength":            "+{n} Stärke",
+		"statIntellect":           "+{n} Intelligenz",
+		"statSpirit":              "+{n} Willenskraft",
+		"statStamina":             "+{n} Ausdauer",
+		"statDefense":             "Erhöht die Verteidigungswertung um {n}.",
+		"statDodge":               "Erhöht Eure Ausweichwertung um {n}.",
+		"statParry":               "Erhöht Eure Parierwertung um {n}.",
+		"statBlock":               "Erhöht Eure Blockwertung um {n}.",
+		"statHit":                 "Erhöht Eure Trefferwertung um {n}.",
+		"statCrit":                "Erhöht Eure kritische Trefferwertung um {n}.",
+		"statResilience":          "Erhöht Eure Abhärtungswertung um {n}.",
+		"statHaste":               "Erhöht Eure Tempowertung um {n}.",
+		"statExpertise":           "Erhöht Eure Waffenkundewertung um {n}.",
+		"statAttackPower":         "Erhöht die Angriffskraft um {n}.",
+		"statRangedAttackPower":   "Erhöht die Distanzangriffskraft um {n}.",
+		"statFeralAttackPower":    "Erhöht die Angriffskraft in Katzen-, Bären-, Terrorbären- und Mondkingestalt um {n}.",
+		"statHealing":             "Erhöht die durch Zauber und Effekte verursachte Heilung um bis zu {n}.",
+		"statSpellDamage":         "Erhöht den durch magische Zauber und Effekte zugefügten Schaden um bis zu {n}.",
+		"statManaRegen":           "Stellt alle 5 Sek. {n} Mana wieder her.",
+		"statArmorPen":            "Erhöht Eure Rüstungsdurchschlagwertung um {n}.",
+		"statSpellPower":          "Erhöht die Zaubermacht um {n}.",
+		"statHealthRegen":         "Stellt alle 5 Sek. {n} Gesundheit wieder her.",
+		"statSpellPen":            "Erhöht Eure Zauberdurchschlagskraft um {n}.",
+		"statBlockValue":          "Erhöht den Blockwert Eures Schildes um {n}.",
+		"statOther":               "Erhöht Wert {type} um {n}.",
+		"socketMeta":              "Metasockel",
+		"socketRed":               "Roter Sockel",
+		"socketYellow":            "Gelber Sockel",
+		"socketBlue":              "Blauer Sockel",
+		"socketBonus":             "Sockelbonus: {name}",
+		"durability":              "Haltbarkeit {n} / {n}",
+		"requiresLevel":           "Benötigt Stufe {n}",
+		"triggerUse":              "Benutzen:",
+		"triggerEquip":            "Anlegen:",
+		"triggerChance":           "Trefferchance:",
+		"setPieces":               "Setteile",
+		"sellPrice":               "Verkaufspreis:",
+		"slotHead":                "Kopf",
+		"slotNeck":                "Hals",
+		"slotShoulder":            "Schulter",
+		"slotShirt":               "Hemd",
+		"slotChest":               "Brust",
+		"slotWaist":               "Taille",
+		"slotLegs":                "Beine",
+		"slotFeet":                "Füße",
+		"slotWrist":               "Handgelenke",
+		"slotHands":               "Hände",
+		"slotFinger":              "Finger",
+		"slotTrinket":             "Schmuck",
+		"slotOneHand":             "Einhändig",
+		"slotOffHand":             "Schildhand",
+		"slotRanged":              "Distanz",
+		"slotBack":                "Rücken",
+		"slotTwoHand":             "Zweihändig",
+		"slotTabard":              "Wappenrock",
+		"slotMainHand":            "Waffenhand",
+		"slotHeldOffHand":         "In Schildhand geführt",
+		"slotProjectile":          "Projektil",
+		"slotThrown":              "Wurfwaffe",
+		"slotRelic":               "Relikt",
+		"weaponAxe":               "Axt",
+		"weaponBow":               "Bogen",
+		"weaponGun":               "Schusswaffe",
+		"weaponMace":              "Streitkolben",
+		"weaponPolearm":           "Stangenwaffe",
+		"weaponSword":             "Schwert",
+		"weaponStaff":             "Stab",
+		"weaponFist":              "Faustwaffe",
+		"weaponMisc":              "Verschiedenes",
+		"weaponDagger":            "Dolch",
+		"weaponCrossbow":          "Armbrust",
+		"weaponWand":              "Zauberstab",
+		"weaponFishingPole":       "Angelrute",
+		"armorCloth":              "Stoff",
+		"armorLeather":            "Leder",
+		"armorMail":               "Schwere Rüstung",
+		"armorPlate":              "Platte",
+		"armorShield":             "Schild",
+		"armorLibram":             "Buchband",
+		"armorIdol":               "Götze",
+		"armorTotem":              "Totem",
+		"armorSigil":              "Siegel",
+		"pricesBySuffix":          "Preise nach Suffix",
+		"suffix":                  "Suffix",
+		"tabCrafting":             "Handwerk",
+		"profession":              "Beruf",
+		"skill":                   "Fertigkeit",
+		"reagents":                "Reagenzien",
+		"reagentCost":             "Reagenzienkosten",
+		"afterCut":                "Nach AH-Gebühr",
+		"margin":                  "Marge",
+		"notListed":               "nicht angeboten",
+		"noProfessions":           "Keine Rezepte in der Weltdatenbank gefunden",
+		"noRecipes":               "Dieser Beruf hat keine Rezepte, die Gegenstände herstellen",
+		"errorCrafting":           "Fehler beim Laden der Handwerkspreise",
+		"tabYields":               "Entzaubern & Mahlen",
+		"method":                  "Methode",
+		"allMethods":              "Alle Methoden",
+		"methodDisenchant":        "Entzaubern",
+		"methodMilling":           "Mahlen",
+		"methodProspecting":       "Sondieren",
+		"belowYieldOnly":          "Nur unter Ertrag",
+		"expectedYield":           "Erwarteter Ertrag",
+		"yieldValue":              "Ertragswert",
+		"noYields":                "Derzeit kostet kein Angebot weniger als sein Ertrag",
+		"errorYields":             "Fehler beim Laden der Erträge",
+		"itemSources":             "Herkunft",
+		"droppedBy":               "Beute von",
+		"soldBy":                  "Verkauft von",
+		"questRewards":            "Questbelohnungen",
+		"kindCreature":            "Kreatur",
+		"kindGameObject":          "Objekt",
+		"dropChance":              "Dropchance",
+		"averageCount":            "Durchschnittliche Anzahl",
+		"stock":                   "Vorrat",
+		"unlimited":               "Unbegrenzt",
+		"honorPoints":             "Ehre",
+		"arenaPoints":             "Arenapunkte",
+		"quest":                   "Quest",
+		"rewardChoice":            "Auswahl",
+		"noSources":               "Für diesen Gegenstand sind keine Beute, Händler oder Questbelohnungen bekannt",
+		"errorSources":            "Fehler beim Laden der Herkunft",
+		"overpricedNote":          "{n} Angebote kosten {percent}% oder mehr des Händlerpreises",
+		"overpriced":              "Über dem Händlerpreis",
+	},
+	"frFR": {
+		"title":                   "Hôtel des ventes WoW",
+		"subtitle":                "Les enchères de votre serveur AzerothCore en temps réel",
+		"totalItems":              "Objets",
+		"totalValue":              "Valeur totale (or)",
+		"activeBids":              "Enchères en cours",
+		"uniqueSellers":           "Vendeurs",
+		"realm":                   "Royaume",
+		"auctionHouse":            "Hôtel des ventes",
+		"allHouses":               "Tous les hôtels des ventes",
+		"language":                "Langue",
+		"searchHint":              "Rechercher un objet, un suffixe ou un vendeur...",
+		"search":                  "Rechercher",
+		"refresh":                 "Actualiser",
+		"showSellers":             "Afficher les vendeurs",
+		"hideSellers":             "Masquer les vendeurs",
+		"activeSellers":           "Vendeurs actifs",
+		"sellerName":              "Vendeur",
+		"totalAuctions":           "Enchères",
+		"uniqueItems":             "Objets différents",
+		"activeAuctions":          "Enchères en cours",
+		"item":                    "Objet",
+		"quality":                 "Qualité",
+		"level":                   "Niveau",
+		"count":                   "Quantité",
+		"seller":                  "Vendeur",
+		"currentBid":              "Enchère actuelle",
+		"buyout":                  "Achat immédiat",
+		"perUnit":                 "À l'unité",
+		"timeLeft":                "Temps restant",
+		"loading":                 "Chargement...",
+		"loadingSellers":          "Chargement des vendeurs...",
+		"loadingAuctions":         "Chargement des enchères...",
+		"errorSellers":            "Erreur lors du chargement des vendeurs",
+		"errorAuctions":           "Erreur lors du chargement des enchères",
+		"noSellers":               "Aucun vendeur trouvé",
+		"noAuctions":              "Aucune enchère trouvée",
+		"noBuyout":                "Pas d'achat immédiat",
+		"previous":                "Précédent",
+		"next":                    "Suivant",
+		"qualityPoor":             "Médiocre",
+		"qualityCommon":           "Classique",
+		"qualityUncommon":         "Inhabituel",
+		"qualityRare":             "Rare",
+		"qualityEpic":             "Épique",
+		"qualityLegendary":        "Légendaire",
+		"qualityUnknown":          "Inconnu",
+		"backToAuctions":          "Retour aux enchères",
+		"viewOnWowhead":           "Voir sur Wowhead",
+		"lowestPerUnit":           "Plus bas (à l'unité)",
+		"medianPerUnit":           "Médiane (à l'unité)",
+		"highestPerUnit":          "Plus haut (à l'unité)",
+		"quantityListed":          "Quantité en vente",
+		"vendorSellsFor":          "Prix de revente",
+		"vendorBuyPrice":          "Prix chez le marchand",
+		"priceHistory":            "Historique des prix (7 jours, médiane à l'unité)",
+		"loadingHistory":          "Chargement de l'historique...",
+		"currentListings":         "Offres actuelles",
+		"loadingListings":         "Chargement des offres...",
+		"itemNotFound":            "Objet introuvable",
+		"errorItem":               "Erreur lors du chargement de l'objet",
+		"noListings":              "Aucune offre actuelle",
+		"historyDisabled":         "L'historique des prix n'est pas disponible",
+		"errorHistory":            "Erreur lors du chargement de l'historique",
+		"historyTooShort":         "Pas encore assez d'historique",
+		"low":                     "Bas",
+		"high":                    "Haut",
+		"newAuctions":             "{n} nouvelles enchères",
+		"showNew":                 "Afficher",
+		"sold":                    "Vendue",
+		"expired":                 "Expirée",
+		"cancelled":               "Annulée",
+		"removed":                 "Terminée",
+		"tabAuctions":             "Enchères",
+		"tabDeals":                "Bonnes affaires",
+		"maxPercent":              "Jusqu'à",
+		"defaultOption":           "Par défaut",
+		"marketPrice":             "Prix du marché",
+		"percentOfMarket":         "% du marché",
+		"profit":                  "Bénéfice",
+		"noDeals":                 "Aucune bonne affaire trouvée",
+		"errorDeals":              "Erreur lors du chargement des bonnes affaires",
+		"sourceAuctionator":       "Prix du marché de mod_auctionator",
+		"sourceMedian":            "Prix médian sur 7 jours",
+		"tabFlips":                "Revente au marchand",
+		"allQualities":            "Toutes",
+		"price":                   "Prix",
+		"bid":                     "enchère",
+		"vendorValue":             "Valeur marchand",
+		"noFlips":                 "Aucun marchand ne paie plus pour le moment",
+		"errorFlips":              "Erreur lors du chargement des reventes au marchand",
+		"ahbotTitle":              "Bot de l'hôtel des ventes",
+		"adminToken":              "Jeton d'administration",
+		"signIn":                  "Se connecter",
+		"signOut":                 "Se déconnecter",
+		"adminTokenRejected":      "Le jeton d'administration a été refusé",
+		"ahbotRestartNote":        "Le worldserver lit ces réglages au démarrage : les modifications s'appliquent après un redémarrage. Les chiffres en direct comptent toutes les enchères, pas seulement celles du bot.",
+		"ahbotNotInstalled":       "mod_auctionhousebot n'est pas installé",
+		"errorAHBot":              "Erreur lors du chargement du bot de l'hôtel des ventes",
+		"minItems":                "Objets min.",
+		"maxItems":                "Objets max.",
+		"biddingInterval":         "Intervalle d'enchères (minutes)",
+		"bidsPerInterval":         "Enchères par intervalle",
+		"liveListings":            "Enchères en cours",
+		"tradeGoods":              "Artisanat",
+		"otherItems":              "Autres objets",
+		"live":                    "En direct",
+		"minPrice":                "Prix min. %",
+		"maxPrice":                "Prix max. %",
+		"minBidPrice":             "Enchère min. %",
+		"maxBidPrice":             "Enchère max. %",
+		"maxStack":                "Pile max.",
+		"buyerPrice":              "Prix d'achat ×",
+		"qualityArtifact":         "Artefact",
+		"percentTotal":            "Total : {n} %",
+		"save":                    "Enregistrer",
+		"saved":                   "Enregistré",
+		"disabledItems":           "Objets désactivés",
+		"itemEntry":               "ID de l'objet",
+		"add":                     "Ajouter",
+		"remove":                  "Retirer",
+		"noDisabledItems":         "Aucun objet désactivé",
+		"auditLog":                "Journal des modifications",
+		"time":                    "Date",
+		"actor":                   "Par",
+		"action":                  "Action",
+		"target":                  "Cible",
+		"noAuditEntries":          "Aucune modification enregistrée",
+		"auditUnavailable":        "Le journal des modifications n'est pas disponible",
+		"tabMarket":               "Comparaison au marché",
+		"auctionatorPrice":        "Prix Auctionator",
+		"scanned":                 "Relevé",
+		"listings":                "Annonces",
+		"units":                   "Unités",
+		"lowest":                  "Le plus bas",
+		"median":                  "Médian",
+		"order":                   "Ordre",
+		"cheapestFirst":           "Moins chers d'abord",
+		"priciestFirst":           "Plus chers d'abord",
+		"noComparisons":           "Aucun objet en vente n'a de prix de marché mod_auctionator",
+		"errorMarket":             "Erreur lors du chargement de la comparaison au marché",
+		"auctionatorTitle":        "Paramètres d'Auctionator",
+		"auctionatorNote":         "Ces paramètres fixent combien d'objets de chaque classe le vendeur de mod_auctionator met en vente, et par piles de combien.",
+		"itemClasses":             "Classes d'objets",
+		"itemClass":               "Classe",
+		"className":               "Nom",
+		"lowestQuality":           "Qualité minimale",
+		"maxCount":                "Nombre max.",
+		"stackCount":              "Taille des piles",
+		"errorAuctionator":        "Erreur lors du chargement des paramètres de mod_auctionator",
+		"auctionatorNotInstalled": "mod_auctionator n'est pas installé",
+		"signInWithAccount":       "Se connecter avec son compte de jeu",
+		"signedInAs":              "Connecté en tant que",
+		"username":                "Nom de compte",
+		"password":                "Mot de passe",
+		"loginNote":               "Utilisez le nom de compte et le mot de passe avec lesquels vous vous connectez au jeu.",
+		"errorSignIn":             "Connexion impossible",
+		"tabMyAuctions":           "Mes enchères",
+		"noMyAuctions":            "Aucun de vos personnages n'a d'enchère",
+		"errorMyAuctions":         "Erreur lors du chargement de vos enchères",
+		"itemLevelN":              "Niveau d'objet {n}",
+		"bindPickup":              "Lié quand ramassé",
+		"bindEquip":               "Lié quand équipé",
+		"bindUse":                 "Lié quand utilisé",
+		"questItem":               "Objet de quête",
+		"unique":                  "Unique",
+		"bagSlots":                "Sac de {n} emplacements",
+		"armorValue":              "Armure : {n}",
+		"blockValue":              "Bloquer : {n}",
+		"damageRange":             "Dégâts : {min} - {max}",
+		"damageRangeSchool":       "+{min} - {max} points de dégâts ({school})",
+		"speed":                   "Vitesse {n}",
+		"dps":                     "({n} dégâts par seconde)",
+		"resistance":              "+{n} résistance ({school})",
+		"schoolHoly":              "Sacré",
+		"schoolFire":              "Feu",
+		"schoolNature":            "Nature",
+		"schoolFrost":             "Givre",
+		"schoolShadow":            "Ombre",
+		"schoolArcane":            "Arcanes",
+		"statMana":                "+{n} Mana",
+		"statHealth":              "+{n} Points de vie",
+		"statAgility":             "+{n} Agilité",
+		"statStrength":            "+{n} Force",
+		"statIntellect":           "+{n} Intelligence",
+		"statSpirit":              "+{n} Esprit",
+		"statStamina":             "+{n} Endurance",
+		"statDefense":             "Augmente le score de défense de {n}.",
+		"statDodge":               "Augmente votre score d'esquive de {n}.",
+		"statParry":               "Augmente votre score de parade de {n}.",
+		"statBlock":               "Augmente votre score de blocage de {n}.",
+		"statHit":                 "Augmente de {n} le score de toucher.",
+		"statCrit":                "Augmente de {n} le score de coup critique.",
+		"statResilience":          "Augmente de {n} le score de résilience.",
+		"statHaste":               "Augmente de {n} le score de hâte.",
+		"statExpertise":           "Augmente votre score d'expertise de {n}.",
+		"statAttackPower":         "Augmente la puissance d'attaque de {n}.",
+		"statRangedAttackPower":   "Augmente la puissance d'attaque à distance de {n}.",
+		"statFeralAttackPower":    "Augmente la puissance d'attaque de {n} sous les formes de félin, d'ours, d'ours redoutable et de sélénien uniquement.",
+		"statHealing":             "Augmente les soins prodigués par les sorts et effets de {n} au maximum.",
+		"statSpellDamage":         "Augmente les dégâts infligés par les sorts et effets magiques de {n} au maximum.",
+		"statManaRegen":           "Rend {n} points de mana toutes les 5 s.",
+		"statArmorPen":            "Augmente votre score de pénétration d'armure de {n}.",
+		"statSpellPower":          "Augmente la puissance des sorts de {n}.",
+		"statHealthRegen":         "Rend {n} points de vie toutes les 5 s.",
+		"statSpellPen":            "Augmente la pénétration des sorts de {n}.",
+		"statBlockValue":          "Augmente la valeur de blocage de votre bouclier de {n}.",
+		"statOther":               "Augmente la caractéristique {type} de {n}.",
+		"socketMeta":              "Méta-châsse",
+		"socketRed":               "Châsse rouge",
+		"socketYellow":            "Châsse jaune",
+		"socketBlue":              "Châsse bleue",
+		"socketBonus":             "Bonus de châsse : {name}",
+		"durability":              "Durabilité {n} / {n}",
+		"requiresLevel":           "Niveau {n} requis",
+		"triggerUse":              "Utiliser :",
+		"triggerEquip":            "Équipé :",
+		"triggerChance":           "Chances quand vous touchez :",
+		"setPieces":               "Pièces de l'ensemble",
+		"sellPrice":               "Prix de vente :",
+		"slotHead":                "Tête",
+		"slotNeck":                "Cou",
+		"slotShoulder":            "Épaule",
+		"slotShirt":               "Chemise",
+		"slotChest":               "Torse",
+		"slotWaist":               "Taille",
+		"slotLegs":                "Jambes",
+		"slotFeet":                "Pieds",
+		"slotWrist":               "Poignets",
+		"slotHands":               "Mains",
+		"slotFinger":              "Doigt",
+		"slotTrinket":             "Bijou",
+		"slotOneHand":             "À une main",
+		"slotOffHand":             "Main gauche",
+		"slotRanged":              "À distance",
+		"slotBack":                "Dos",
+		"slotTwoHand":             "Deux mains",
+		"slotTabard":              "Tabard",
+		"slotMainHand":            "Main droite",
+		"slotHeldOffHand":         "Tenu(e) en main gauche",
+		"slotProjectile":          "Projectile",
+		"slotThrown":              "Armes de jet",
+		"slotRelic":               "Relique",
+		"weaponAxe":               "Hache",
+		"weaponBow":               "Arc",
+		"weaponGun":               "Arme à feu",
+		"weaponMace":              "Masse",
+		"weaponPolearm":           "Arme d'hast",
+		"weaponSword":             "Épée",
+		"weaponStaff":             "Bâton",
+		"weaponFist":              "Arme de pugilat",
+		"weaponMisc":              "Divers",
+		"weaponDagger":            "Dague",
+		"weaponCrossbow":          "Arbalète",
+		"weaponWand":              "Baguette",
+		"weaponFishingPole":       "Canne à pêche",
+		"armorCloth":              "Tissu",
+		"armorLeather":            "Cuir",
+		"armorMail":               "Mailles",
+		"armorPlate":              "Plaques",
+		"armorShield":             "Bouclier",
+		"armorLibram":             "Libram",
+		"armorIdol":               "Idole",
+		"armorTotem":              "Totem",
+		"armorSigil":              "Cachet",
+		"pricesBySuffix":          "Prix par suffixe",
+		"suffix":                  "Suffixe",
+		"tabCrafting":             "Artisanat",
+		"profession":              "Métier",
+		"skill":                   "Compétence",
+		"reagents":                "Composants",
+		"reagentCost":             "Coût des composants",
+		"afterCut":                "Après commission",
+		"margin":                  "Marge",
+		"notListed":               "pas en vente",
+		"noProfessions":           "Aucune recette trouvée dans la base de données du monde",
+		"noRecipes":               "Ce métier n'a aucune recette qui crée des objets",
+		"errorCrafting":           "Erreur lors du chargement des prix d'artisanat",
+		"tabYields":               "Désenchanter & Broyer",
+		"method":                  "Méthode",
+		"allMethods":              "Toutes les méthodes",
+		"methodDisenchant":        "Désenchantement",
+		"methodMilling":           "Broyage",
+		"methodProspecting":       "Prospection",
+		"belowYieldOnly":          "Sous le rendement uniquement",
+		"expectedYield":           "Rendement attendu",
+		"yieldValue":              "Valeur du rendement",
+		"noYields":                "Aucune annonce ne coûte moins que son rendement pour le moment",
+		"errorYields":             "Erreur lors du chargement des rendements",
+		"itemSources":             "Provenance",
+		"droppedBy":               "Butin de",
+		"soldBy":                  "Vendu par",
+		"questRewards":            "Récompenses de quête",
+		"kindCreature":            "Créature",
+		"kindGameObject":          "Objet",
+		"dropChance":              "Chance de butin",
+		"averageCount":            "Quantité moyenne",
+		"stock":                   "Stock",
+		"unlimited":               "Illimité",
+		"honorPoints":             "Honneur",
+		"arenaPoints":             "Points d'arène",
+		"quest":                   "Quête",
+		"rewardChoice":            "au choix",
+		"noSources":               "Aucun butin, marchand ou récompense de quête n'est connu pour cet objet",
+		"errorSources":            "Erreur lors du chargement de la provenance",
+		"overpricedNote":          "{n} annonces coûtent {percent}% ou plus du prix du marchand",
+		"overpriced":              "Au-dessus du prix du marchand",
+	},
+	"esES": {
+		"title":                   "Casa de subastas de WoW",
+		"subtitle":                "Datos de la casa de subastas de tu servidor AzerothCore en tiempo real",
+		"totalItems":              "Objetos",
+		"totalValue":              "Valor total (oro)",
+		"activeBids":              "Pujas activas",
+		"uniqueSellers":           "Vendedores",
+		"realm":                   "Reino",
+		"auctionHouse":            "Casa de subastas",
+		"allHouses":               "Todas las casas de subastas",
+		"language":                "Idioma",
+		"searchHint":              "Buscar por objeto, sufijo o vendedor...",
+		"search":                  "Buscar",
+		"refresh":                 "Actualizar",
+		"showSellers":             "Mostrar vendedores",
+		"hideSellers":             "Ocultar vendedores",
+		"activeSellers":           "Vendedores activos",
+		"sellerName":              "Vendedor",
+		"totalAuctions":           "Subastas",
+		"uniqueItems":             "Objetos distintos",
+		"activeAuctions":          "Subastas activas",
+		"item":                    "Objeto",
+		"quality":                 "Calidad",
+		"level":                   "Nivel",
+		"count":                   "Cantidad",
+		"seller":                  "Vendedor",
+		"currentBid":              "Puja actual",
+		"buyout":                  "Compra inmediata",
+		"perUnit":                 "Por unidad",
+		"timeLeft":                "Tiempo restante",
+		"loading":                 "Cargando...",
+		"loadingSellers":          "Cargando vendedores...",
+		"loadingAuctions":         "Cargando subastas...",
+		"errorSellers":            "Error al cargar los vendedores",
+		"errorAuctions":           "Error al cargar las subastas",
+		"noSellers":               "No se encontraron vendedores",
+		"noAuctions":              "No se encontraron subastas",
+		"noBuyout":                "Sin compra inmediata",
+		"previous":                "Anterior",
+		"next":                    "Siguiente",
+		"qualityPoor":             "Pobre",
+		"qualityCommon":           "Común",
+		"qualityUncommon":         "Poco común",
+		"qualityRare":             "Raro",
+		"qualityEpic":             "Épico",
+		"qualityLegendary":        "Legendario",
+		"qualityUnknown":          "Desconocido",
+		"backToAuctions":          "Volver a las subastas",
+		"viewOnWowhead":           "Ver en Wowhead",
+		"lowestPerUnit":           "Mínimo (por unidad)",
+		"medianPerUnit":           "Mediana (por unidad)",
+		"highestPerUnit":          "Máximo (por unidad)",
+		"quantityListed":          "Cantidad en venta",
+		"vendorSellsFor":          "Precio de venta al vendedor",
+		"vendorBuyPrice":          "Precio del vendedor",
+		"priceHistory":            "Historial de precios (7 días, mediana por unidad)",
+		"loadingHistory":          "Cargando historial...",
+		"currentListings":         "Ofertas actuales",
+		"loadingListings":         "Cargando ofertas...",
+		"itemNotFound":            "Objeto no encontrado",
+		"errorItem":               "Error al cargar el objeto",
+		"noListings":              "No hay ofertas actuales",
+		"historyDisabled":         "El historial de precios no está disponible",
+		"errorHistory":            "Error al cargar el historial de precios",
+		"historyTooShort":         "Aún no hay suficiente historial",
+		"low":                     "Mín.",
+		"high":                    "Máx.",
+		"newAuctions":             "{n} subastas nuevas",
+		"showNew":                 "Mostrar",
+		"sold":                    "Vendida",
+		"expired":                 "Caducada",
+		"cancelled":               "Cancelada",
+		"removed":                 "Finalizada",
+		"tabAuctions":             "Subastas",
+		"tabDeals":                "Gangas",
+		"maxPercent":              "Hasta",
+		"defaultOption":           "Predeterminado",
+		"marketPrice":             "Precio de mercado",
+		"percentOfMarket":         "% del mercado",
+		"profit":                  "Beneficio",
+		"noDeals":                 "No se encontraron gangas",
+		"errorDeals":              "Error al cargar las gangas",
+		"sourceAuctionator":       "Precio de mercado de mod_auctionator",
+		"sourceMedian":            "Precio mediano de 7 días",
+		"tabFlips":                "Reventa al vendedor",
+		"allQualities":            "Todas",
+		"price":                   "Precio",
+		"bid":                     "puja",
+		"vendorValue":             "Valor de vendedor",
+		"noFlips":                 "Ahora mismo ningún vendedor paga más",
+		"errorFlips":              "Error al cargar las reventas al vendedor",
+		"ahbotTitle":              "Bot de la casa de subastas",
+		"adminToken":              "Token de administración",
+		"signIn":                  "Iniciar sesión",
+		"signOut":                 "Cerrar sesión",
+		"adminTokenRejected":      "El token de administración no fue aceptado",
+		"ahbotRestartNote":        "El worldserver lee estos ajustes al arrancar, así que los cambios se aplican tras reiniciarlo. Los recuentos en vivo incluyen todas las subastas, no solo las del bot.",
+		"ahbotNotInstalled":       "mod_auctionhousebot no está instalado",
+		"errorAHBot":              "Error al cargar el bot de la casa de subastas",
+		"minItems":                "Objetos mín.",
+		"maxItems":                "Objetos máx.",
+		"biddingInterval":         "Intervalo de pujas (minutos)",
+		"bidsPerInterval":         "Pujas por intervalo",
+		"liveListings":            "Subastas activas",
+		"tradeGoods":              "Objetos comerciables",
+		"otherItems":              "Otros objetos",
+		"live":                    "En vivo",
+		"minPrice":                "Precio mín. %",
+		"maxPrice":                "Precio máx. %",
+		"minBidPrice":             "Puja mín. %",
+		"maxBidPrice":             "Puja máx. %",
+		"maxStack":                "Pila máx.",
+		"buyerPrice":              "Precio de compra ×",
+		"qualityArtifact":         "Artefacto",
+		"percentTotal":            "Total: {n}%",
+		"save":                    "Guardar",
+		"saved":                   "Guardado",
+		"disabledItems":           "Objetos desactivados",
+		"itemEntry":               "ID del objeto",
+		"add":                     "Añadir",
+		"remove":                  "Quitar",
+		"noDisabledItems":         "No hay objetos desactivados",
+		"auditLog":                "Registro de cambios",
+		"time":                    "Fecha",
+		"actor":                   "Por",
+		"action":                  "Acción",
+		"target":                  "Objetivo",
+		"noAuditEntries":          "Aún no hay cambios registrados",
+		"auditUnavailable":        "El registro de cambios no está disponible",
+		"tabMarket":               "Comparación de mercado",
+		"auctionatorPrice":        "Precio de Auctionator",
+		"scanned":                 "Escaneado",
+		"listings":                "Anuncios",
+		"units":                   "Unidades",
+		"lowest":                  "Más bajo",
+		"median":                  "Mediana",
+		"order":                   "Orden",
+		"cheapestFirst":           "Más baratos primero",
+		"priciestFirst":           "Más caros primero",
+		"noComparisons":           "Ningún objeto en venta tiene precio de mercado de mod_auctionator",
+		"errorMarket":             "Error al cargar la comparación de mercado",
+		"auctionatorTitle":        "Ajustes de Auctionator",
+		"auctionatorNote":         "Estos ajustes deciden cuántos objetos de cada clase pone a la venta el vendedor de mod_auctionator, y en qué montones.",
+		"itemClasses":             "Clases de objetos",
+		"itemClass":               "Clase",
+		"className":               "Nombre",
+		"lowestQuality":           "Calidad mínima",
+		"maxCount":                "Cantidad máx.",
+		"stackCount":              "Tamaño del montón",
+		"errorAuctionator":        "Error al cargar los ajustes de mod_auctionator",
+		"auctionatorNotInstalled": "mod_auctionator no está instalado",
+		"signInWithAccount":       "Iniciar sesión con tu cuenta de juego",
+		"signedInAs":              "Sesión iniciada como",
+		"username":                "Nombre de cuenta",
+		"password":                "Contraseña",
+		"loginNote":               "Usa el nombre de cuenta y la contraseña con los que entras al juego.",
+		"errorSignIn":             "No se pudo iniciar sesión",
+		"tabMyAuctions":           "Mis subastas",
+		"noMyAuctions":            "Ninguno de tus personajes tiene subastas",
+		"errorMyAuctions":         "Error al cargar tus subastas",
+		"itemLevelN":              "Nivel de objeto {n}",
+		"bindPickup":              "Se liga al recogerlo",
+		"bindEquip":               "Se liga al equiparlo",
+		"bindUse":                 "Se liga al usarlo",
+		"questItem":               "Objeto de misión",
+		"unique":                  "Único",
+		"bagSlots":                "Bolsa de {n} casillas",
+		"armorValue":              "{n} armadura",
+		"blockValue":              "{n} bloqueo",
+		"damageRange":             "{min} - {max} daño",
+		"damageRangeSchool":       "+{min} - {max} daño de {school}",
+		"speed":                   "Velocidad {n}",
+		"dps":                     "({n} daño por segundo)",
+		"resistance":              "+{n} resistencia a {school}",
+		"schoolHoly":              "Sagrado",
+		"schoolFire":              "Fuego",
+		"schoolNature":            "Naturaleza",
+		"schoolFrost":             "Escarcha",
+		"schoolShadow":            "Sombras",
+		"schoolArcane":            "Arcano",
+		"statMana":                "+{n} maná",
+		"statHealth":              "+{n} salud",
+		"statAgility":             "+{n} agilidad",
+		"statStrength":            "+{n} fuerza",
+		"statIntellect":           "+{n} intelecto",
+		"statSpirit":              "+{n} espíritu",
+		"statStamina":             "+{n} aguante",
+		"statDefense":             "Aumenta el índice de defensa {n} p.",
+		"statDodge":               "Aumenta tu índice de esquivar {n} p.",
+		"statParry":               "Aumenta tu índice de parada {n} p.",
+		"statBlock":               "Aumenta tu índice de bloqueo {n} p.",
+		"statHit":                 "Mejora el índice de golpe {n} p.",
+		"statCrit":                "Mejora el índice de golpe crítico {n} p.",
+		"statResilience":          "Mejora tu índice de temple {n} p.",
+		"statHaste":               "Mejora el índice de celeridad {n} p.",
+		"statExpertise":           "Aumenta tu índice de pericia {n} p.",
+		"statAttackPower":         "Aumenta el poder de ataque {n} p.",
+		"statRangedAttackPower":   "Aumenta el poder de ataque a distancia {n} p.",
+		"statFeralAttackPower":    "Aumenta el poder de ataque {n} p. solo en las formas de felino, oso, oso temible y lechúcico lunar.",
+		"statHealing":             "Aumenta la sanación realizada por hechizos y efectos hasta {n} p.",
+		"statSpellDamage":         "Aumenta el daño infligido por hechizos y efectos mágicos hasta {n} p.",
+		"statManaRegen":           "Restaura {n} p. de maná cada 5 s.",
+		"statArmorPen":            "Aumenta tu índice de penetración de armadura {n} p.",
+		"statSpellPower":          "Aumenta el poder con hechizos {n} p.",
+		"statHealthRegen":         "Restaura {n} p. de salud cada 5 s.",
+		"statSpellPen":            "Aumenta tu penetración de hechizos {n} p.",
+		"statBlockValue":          "Aumenta el valor de bloqueo de tu escudo {n} p.",
+		"statOther":               "Aumenta la estadística {type} {n} p.",
+		"socketMeta":              "Ranura meta",
+		"socketRed":               "Ranura roja",
+		"socketYellow":            "Ranura amarilla",
+		"socketBlue":              "Ranura azul",
+		"socketBonus":             "Bonus ranura: {name}",
+		"durability":              "Durabilidad {n} / {n}",
+		"requiresLevel":           "Necesitas ser de nivel {n}",
+		"triggerUse":              "Uso:",
+		"triggerEquip":            "Equipar:",
+		"triggerChance":           "Probabilidad al acertar:",
+		"setPieces":               "Piezas del conjunto",
+		"sellPrice":               "Precio de venta:",
+		"slotHead":                "Cabeza",
+		"slotNeck":                "Cuello",
+		"slotShoulder":            "Hombro",
+		"slotShirt":               "Camisa",
+		"slotChest":               "Pecho",
+		"slotWaist":               "Cintura",
+		"slotLegs":                "Piernas",
+		"slotFeet":                "Pies",
+		"slotWrist":               "Muñeca",
+		"slotHands":               "Manos",
+		"slotFinger":              "Dedo",
+		"slotTrinket":             "Abalorio",
+		"slotOneHand":             "Una mano",
+		"slotOffHand":             "Mano izquierda",
+		"slotRanged":              "A distancia",
+		"slotBack":                "Espalda",
+		"slotTwoHand":             "Dos manos",
+		"slotTabard":              "Tabardo",
+		"slotMainHand":            "Mano derecha",
+		"slotHeldOffHand":         "Sostener con la mano izquierda",
+		"slotProjectile":          "Proyectil",
+		"slotThrown":              "Arrojadiza",
+		"slotRelic":               "Reliquia",
+		"weaponAxe":               "Hacha",
+		"weaponBow":               "Arco",
+		"weaponGun":               "Arma de fuego",
+		"weaponMace":              "Maza",
+		"weaponPolearm":           "Arma de asta",
+		"weaponSword":             "Espada",
+		"weaponStaff":             "Bastón",
+		"weaponFist":              "Arma de puño",
+		"weaponMisc":              "Miscelánea",
+		"weaponDagger":            "Daga",
+		"weaponCrossbow":          "Ballesta",
+		"weaponWand":              "Varita",
+		"weaponFishingPole":       "Caña de pescar",
+		"armorCloth":              "Tela",
+		"armorLeather":            "Cuero",
+		"armorMail":               "Malla",
+		"armorPlate":              "Placas",
+		"armorShield":             "Escudo",
+		"armorLibram":             "Tratado",
+		"armorIdol":               "Ídolo",
+		"armorTotem":              "Tótem",
+		"armorSigil":              "Sigilo",
+		"pricesBySuffix":          "Precios por sufijo",
+		"suffix":                  "Sufijo",
+		"tabCrafting":             "Artesanía",
+		"profession":              "Profesión",
+		"skill":                   "Habilidad",
+		"reagents":                "Componentes",
+		"reagentCost":             "Coste de componentes",
+		"afterCut":                "Tras la comisión",
+		"margin":                  "Margen",
+		"notListed":               "no en venta",
+		"noProfessions":           "No se encontraron recetas en la base de datos del mundo",
+		"noRecipes":               "Esta profesión no tiene recetas que creen objetos",
+		"errorCrafting":           "Error al cargar los precios de artesanía",
+		"tabYields":               "Desencantar y moler",
+		"method":                  "Método",
+		"allMethods":              "Todos los métodos",
+		"methodDisenchant":        "Desencantar",
+		"methodMilling":           "Moler",
+		"methodProspecting":       "Prospección",
+		"belowYieldOnly":          "Solo bajo rendimiento",
+		"expectedYield":           "Rendimiento esperado",
+		"yieldValue":              "Valor del rendimiento",
+		"noYields":                "Ahora mismo ningún anuncio cuesta menos que su rendimiento",
+		"errorYields":             "Error al cargar los rendimientos",
+		"itemSources":             "Procedencia",
+		"droppedBy":               "Botín de",
+		"soldBy":                  "Vendido por",
+		"questRewards":            "Recompensas de misión",
+		"kindCreature":            "Criatura",
+		"kindGameObject":          "Objeto",
+		"dropChance":              "Probabilidad de botín",
+		"averageCount":            "Cantidad media",
+		"stock":                   "Existencias",
+		"unlimited":               "Ilimitado",
+		"honorPoints":             "Honor",
+		"arenaPoints":             "Puntos de arena",
+		"quest":                   "Misión",
+		"rewardChoice":            "a elegir",
+		"noSources":               "No se conoce botín, vendedor ni recompensa de misión para este objeto",
+		"errorSources":            "Error al cargar la procedencia",
+		"overpricedNote":          "{n} anuncios cuestan el {percent}% o más del precio del vendedor",
+		"overpriced":              "Por encima del precio del vendedor",
+	},
+}
+
+// translations returns the interface strings of locale, falling back to
+// the first translated locale of the same language in languages, then
+// English
+func translations(locale string) map[string]string {
+	own, ok := uiStrings[locale]
+	for _, l := range languages {
+		if ok {
+			break
+		}
+		if l.Locale[:2] == locale[:2] {
+			own, ok = uiStrings[l.Locale]
+		}
+	}
+
+	t := make(map[string]string, len(uiStrings[wow.DefaultLocale]))
+	for key, value := range uiStrings[wow.DefaultLocale] {
+		t[key] = value
+	}
+	for key, value := range own {
+		t[key] = value
+	}
+	return t
+}
+
+// pageData returns the template data shared by the HTML pages
+func pageData(locale string, data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["Locale"] = locale
+	data["Lang"] = htmlLang(locale)
+	data["T"] = translations(locale)
+	data["Languages"] = languages
+	return data
+}
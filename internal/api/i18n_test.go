@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+func TestAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+		ok     bool
+	}{
+		{"de-DE,de;q=0.9,en;q=0.8", "deDE", true},
+		{"pt-BR, fr;q=0.5", "frFR", true},
+		{"en;q=0.2, es-MX;q=0.7", "esMX", true},
+		{"*", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := acceptLanguage(tt.header)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("acceptLanguage(%q) = %q, %v; want %q, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// localizedRequest serves a GET request with the given header and decodes
+// the JSON response into v
+func localizedRequest(t *testing.T, s *Server, target, header, value string, v interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set(header, value)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s status = %d; body: %s", target, rec.Code, rec.Body.String())
+	}
+	if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+		t.Fatalf("GET %s decoding response: %v", target, err)
+	}
+}
+
+func TestLocalizedItemNames(t *testing.T) {
+	m := newTestStore()
+	m.AddItemName(2589, "deDE", "Leinenstoff")
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m})})
+
+	var resp auctionsResponse
+	get(t, s, "/api/auctions?locale=deDE&entry=2589", http.StatusOK, &resp)
+	if resp.Total != 3 || resp.Auctions[0].ItemName != "Leinenstoff" {
+		t.Errorf("locale=deDE auctions = %+v", resp.Auctions)
+	}
+
+	// Search matches the translated names, untranslated items keep enUS
+	localizedRequest(t, s, "/api/search?q=stoff", "Accept-Language", "de-DE,de;q=0.9", &resp)
+	if resp.Total != 3 {
+		t.Errorf("search for stoff in deDE: total = %d, want 3", resp.Total)
+	}
+	localizedRequest(t, s, "/api/search?q=wool", "Cookie", "locale=deDE", &resp)
+	if resp.Total != 1 || resp.Auctions[0].ItemName != "Wool Cloth" {
+		t.Errorf("untranslated item in deDE = %+v", resp.Auctions)
+	}
+
+	var item struct {
+		Item     store.ItemTemplate  `json:"item"`
+		Auctions []store.AuctionItem `json:"auctions"`
+	}
+	localizedRequest(t, s, "/api/items/2589", "Accept-Language", "de", &item)
+	if item.Item.Name != "Leinenstoff" || item.Auctions[0].ItemName != "Leinenstoff" {
+		t.Errorf("item in deDE = %+v", item)
+	}
+
+	get(t, s, "/api/auctions?locale=klingon", http.StatusBadRequest, nil)
+}
+
+func TestPagesTranslated(t *testing.T) {
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: newTestStore()})})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "fr-CA,fr;q=0.9")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	body := rec.Body.String()
+	for _, want := range []string{`lang="fr-FR"`, "Hôtel des ventes WoW", `<option value="frFR" selected>`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("French home page missing %s", want)
+		}
+	}
+
+	// Untranslated strings fall back to English
+	rec = get(t, s, "/items/2589?locale=ruRU", http.StatusOK, nil)
+	if !strings.Contains(rec.Body.String(), "View on Wowhead") {
+		t.Error("Russian item page does not fall back to English")
+	}
+	// Mexican Spanish falls back to Spain's
+	if got, want := translations("esMX")["overpriced"], uiStrings["esES"]["overpriced"]; got != want {
+		t.Errorf("esMX overpriced = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,1639 @@
+package api
+
+// HTML template with embedded CSS and JavaScript
+const indexTemplate = `<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.T.title}}</title>
+    <style>
+        * {
+            margin: 0;
+            padding: 0;
+            box-sizing: border-box;
+        }
+
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            background: linear-gradient(135deg, #1e3c72 0%, #2a5298 100%);
+            color: #333;
+            min-height: 100vh;
+        }
+
+        .container {
+            max-width: 1400px;
+            margin: 0 auto;
+            padding: 20px;
+        }
+
+        .header {
+            text-align: center;
+            margin-bottom: 30px;
+            color: white;
+        }
+
+        .header h1 {
+            font-size: 2.5rem;
+            margin-bottom: 10px;
+            text-shadow: 2px 2px 4px rgba(0,0,0,0.5);
+        }
+
+        .header p {
+            font-size: 1.1rem;
+            opacity: 0.9;
+        }
+
+        .header .account {
+            margin-top: 10px;
+            font-size: 0.95rem;
+        }
+
+        .header .account a {
+            color: white;
+        }
+
+        .stats-grid {
+            display: grid;
+            grid-template-columns: repeat(auto-fit, minmax(200px, 1fr));
+            gap: 20px;
+            margin-bottom: 30px;
+        }
+
+        .stat-card {
+            background: rgba(255, 255, 255, 0.95);
+            padding: 20px;
+            border-radius: 10px;
+            text-align: center;
+            box-shadow: 0 4px 6px rgba(0,0,0,0.1);
+            transition: transform 0.2s;
+        }
+
+        .stat-card:hover {
+            transform: translateY(-2px);
+        }
+
+        .stat-number {
+            font-size: 2rem;
+            font-weight: bold;
+            color: #2a5298;
+            margin-bottom: 5px;
+        }
+
+        .stat-label {
+            color: #666;
+            font-size: 0.9rem;
+        }
+
+        .search-section {
+            background: rgba(255, 255, 255, 0.95);
+            padding: 20px;
+            border-radius: 10px;
+            margin-bottom: 20px;
+            box-shadow: 0 4px 6px rgba(0,0,0,0.1);
+        }
+
+        .sellers-section {
+            margin-bottom: 20px;
+        }
+
+        .tabs {
+            display: flex;
+            gap: 10px;
+            margin-bottom: 20px;
+        }
+
+        .tab-button {
+            padding: 10px 20px;
+            background: rgba(255, 255, 255, 0.8);
+            color: #2a5298;
+            border: none;
+            border-radius: 5px;
+            cursor: pointer;
+            font-size: 1rem;
+            font-weight: bold;
+        }
+
+        .tab-button.active {
+            background: #2a5298;
+            color: white;
+        }
+
+        .search-form {
+            display: flex;
+            gap: 10px;
+            align-items: center;
+        }
+
+        .search-input {
+            flex: 1;
+            padding: 12px;
+            border: 2px solid #ddd;
+            border-radius: 5px;
+            font-size: 1rem;
+        }
+
+        .house-select {
+            flex: 0 0 220px;
+            background: white;
+        }
+
+        .search-input:focus {
+            outline: none;
+            border-color: #2a5298;
+        }
+
+        .btn {
+            padding: 12px 24px;
+            background: #2a5298;
+            color: white;
+            border: none;
+            border-radius: 5px;
+            cursor: pointer;
+            font-size: 1rem;
+            transition: background 0.2s;
+        }
+
+        .btn:hover {
+            background: #1e3c72;
+        }
+
+        .auctions-table {
+            background: rgba(255, 255, 255, 0.95);
+            border-radius: 10px;
+            overflow: hidden;
+            box-shadow: 0 4px 6px rgba(0,0,0,0.1);
+        }
+
+        .table-header {
+            background: #2a5298;
+            color: white;
+            padding: 15px 20px;
+            font-weight: bold;
+        }
+
+        .table-container {
+            overflow-x: auto;
+        }
+
+        table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+
+        th, td {
+            padding: 12px 15px;
+            text-align: left;
+            border-bottom: 1px solid #ddd;
+        }
+
+        th {
+            background: #f8f9fa;
+            font-weight: 600;
+            color: #333;
+            cursor: pointer;
+            user-select: none;
+            position: relative;
+        }
+
+        th:hover {
+            background: #e9ecef;
+        }
+
+        th.sortable::after {
+            content: '↕';
+            position: absolute;
+            right: 8px;
+            color: #999;
+        }
+
+        th.sort-asc::after {
+            content: '↑';
+            color: #2a5298;
+        }
+
+        th.sort-desc::after {
+            content: '↓';
+            color: #2a5298;
+        }
+
+        tr:hover {
+            background: #f5f5f5;
+        }
+
+        .quality-0 { 
+            color: #9d9d9d; 
+            text-shadow: 1px 1px 2px rgba(0,0,0,0.3);
+            font-weight: 500;
+        }
+        .quality-1 { color: #ffffff; }
+        .quality-2 { color: #1eff00; }
+        .quality-3 { color: #0070dd; }
+        .quality-4 { color: #a335ee; }
+        .quality-5 { color: #ff8000; }
+
+        .item-link {
+            text-decoration: none;
+            color: inherit;
+        }
+
+        .item-link:hover {
+            text-decoration: underline;
+        }
+
+        .tooltip {
+            position: fixed;
+            z-index: 1000;
+            max-width: 320px;
+            padding: 8px 10px;
+            background: rgba(8, 12, 32, 0.95);
+            border: 1px solid #8a8a9a;
+            border-radius: 4px;
+            color: #ffffff;
+            font-size: 0.85rem;
+            line-height: 1.35;
+            pointer-events: none;
+            box-shadow: 0 4px 8px rgba(0,0,0,0.4);
+        }
+
+        .tooltip .tooltip-name {
+            font-size: 1rem;
+            font-weight: bold;
+        }
+
+        .tooltip .tooltip-row {
+            display: flex;
+            justify-content: space-between;
+            gap: 20px;
+        }
+
+        .tooltip .tooltip-gold { color: #ffd100; }
+        .tooltip .tooltip-green { color: #1eff00; }
+        .tooltip .tooltip-gray { color: #9d9d9d; }
+
+        .price {
+            font-weight: bold;
+            color: #2a5298;
+        }
+
+        .time-left {
+            font-size: 0.9rem;
+            color: #666;
+        }
+
+        /* Controls on the right of a table header */
+        .table-tools {
+            float: right;
+            font-weight: normal;
+        }
+
+        .table-tools button, .table-tools select {
+            margin-left: 8px;
+            padding: 2px 10px;
+            border: none;
+            border-radius: 3px;
+            cursor: pointer;
+        }
+
+        .profit {
+            font-weight: bold;
+            color: #2e7d32;
+        }
+
+        .loss {
+            font-weight: bold;
+            color: #c62828;
+        }
+
+        .reagents {
+            font-size: 0.9rem;
+        }
+
+        /* Live changes from the auction stream */
+        .row-bid {
+            animation: flash-bid 3s;
+        }
+
+        @keyframes flash-bid {
+            from { background: #fff3b0; }
+        }
+
+        .row-ended {
+            opacity: 0.5;
+            text-decoration: line-through;
+        }
+
+        .row-ended .time-left {
+            text-decoration: none;
+            font-weight: bold;
+        }
+
+        .loading {
+            text-align: center;
+            padding: 40px;
+            color: #666;
+        }
+
+        .error {
+            background: #ffebee;
+            color: #c62828;
+            padding: 15px;
+            border-radius: 5px;
+            margin: 10px 0;
+        }
+
+        .pagination {
+            display: flex;
+            justify-content: center;
+            gap: 10px;
+            margin-top: 20px;
+        }
+
+        .pagination button {
+            padding: 8px 16px;
+            border: 1px solid #ddd;
+            background: white;
+            cursor: pointer;
+            border-radius: 3px;
+        }
+
+        .pagination button:hover {
+            background: #f5f5f5;
+        }
+
+        .pagination button.active {
+            background: #2a5298;
+            color: white;
+            border-color: #2a5298;
+        }
+
+        @media (max-width: 768px) {
+            .container {
+                padding: 10px;
+            }
+            
+            .header h1 {
+                font-size: 2rem;
+            }
+            
+            .search-form {
+                flex-direction: column;
+            }
+            
+            .stats-grid {
+                grid-template-columns: repeat(2, 1fr);
+            }
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>⚔️ {{.T.title}}</h1>
+            <p>{{.T.subtitle}}</p>
+            {{if .Login}}
+            <p class="account">
+                {{if .Account}}
+                {{.T.signedInAs}} <strong>{{.Account.Username}}</strong> &middot; <a href="#" onclick="signOut(event)">{{.T.signOut}}</a>
+                {{else}}
+                <a href="/login">{{.T.signInWithAccount}}</a>
+                {{end}}
+            </p>
+            {{end}}
+        </div>
+
+        <div class="stats-grid" id="statsGrid">
+            <div class="stat-card">
+                <div class="stat-number" id="totalItems">-</div>
+                <div class="stat-label">{{.T.totalItems}}</div>
+            </div>
+            <div class="stat-card">
+                <div class="stat-number" id="totalValue">-</div>
+                <div class="stat-label">{{.T.totalValue}}</div>
+            </div>
+            <div class="stat-card">
+                <div class="stat-number" id="activeBids">-</div>
+                <div class="stat-label">{{.T.activeBids}}</div>
+            </div>
+            <div class="stat-card">
+                <div class="stat-number" id="uniqueOwners">-</div>
+                <div class="stat-label">{{.T.uniqueSellers}}</div>
+            </div>
+        </div>
+
+        <div class="search-section">
+            <form class="search-form" id="searchForm">
+                <select class="search-input house-select" id="realmSelect" title="{{.T.realm}}" style="display: none;"></select>
+                <select class="search-input house-select" id="houseSelect" title="{{.T.auctionHouse}}">
+                    <option value="">{{.T.allHouses}}</option>
+                </select>
+                <input type="text" class="search-input" id="searchInput" placeholder="{{.T.searchHint}}">
+                <button type="submit" class="btn">{{.T.search}}</button>
+                <button type="button" class="btn" onclick="loadAuctions()">{{.T.refresh}}</button>
+                <button type="button" class="btn" onclick="toggleSellers()">{{.T.showSellers}}</button>
+                <select class="search-input house-select" id="localeSelect" title="{{.T.language}}">
+                    {{range .Languages}}<option value="{{.Locale}}"{{if eq .Locale $.Locale}} selected{{end}}>{{.Name}}</option>
+                    {{end}}
+                </select>
+            </form>
+        </div>
+
+        <div class="sellers-section" id="sellersSection" style="display: none;">
+            <div class="auctions-table">
+                <div class="table-header">
+                    <h2>{{.T.activeSellers}}</h2>
+                </div>
+                <div class="table-container">
+                    <table id="sellersTable">
+                        <thead>
+                            <tr>
+                                <th class="sortable" data-sort="name">{{.T.sellerName}}</th>
+                                <th class="sortable" data-sort="total_auctions">{{.T.totalAuctions}}</th>
+                                <th class="sortable" data-sort="total_value">{{.T.totalValue}}</th>
+                                <th class="sortable" data-sort="unique_items">{{.T.uniqueItems}}</th>
+                            </tr>
+                        </thead>
+                        <tbody id="sellersBody">
+                            <tr>
+                                <td colspan="4" class="loading">{{.T.loadingSellers}}</td>
+                            </tr>
+                        </tbody>
+                    </table>
+                </div>
+            </div>
+        </div>
+
+        <div class="tabs">
+            <button type="button" class="tab-button active" data-tab="auctions" onclick="showTab('auctions')">{{.T.tabAuctions}}</button>
+            <button type="button" class="tab-button" data-tab="deals" onclick="showTab('deals')">{{.T.tabDeals}}</button>
+            <button type="button" class="tab-button" data-tab="flips" onclick="showTab('flips')">{{.T.tabFlips}}</button>
+            <button type="button" class="tab-button" data-tab="market" onclick="showTab('market')">{{.T.tabMarket}}</button>
+            {{if .Crafting}}
+            <button type="button" class="tab-button" data-tab="crafting" onclick="showTab('crafting')">{{.T.tabCrafting}}</button>
+            {{end}}
+            {{if .Yields}}
+            <button type="button" class="tab-button" data-tab="yields" onclick="showTab('yields')">{{.T.tabYields}}</button>
+            {{end}}
+            {{if .Account}}
+            <button type="button" class="tab-button" data-tab="mine" onclick="showTab('mine')">{{.T.tabMyAuctions}}</button>
+            {{end}}
+        </div>
+
+        <div class="tab-panel" id="auctionsTab">
+            <div class="auctions-table">
+                <div class="table-header">
+                    <span class="table-tools" id="liveNotice" style="display: none;">
+                        <span id="liveCount"></span><button onclick="showNewAuctions()">{{.T.showNew}}</button>
+                    </span>
+                    <h2>{{.T.activeAuctions}}</h2>
+                </div>
+                <div class="table-container">
+                    <table id="auctionsTable">
+                        <thead>
+                            <tr>
+                                <th class="sortable" data-sort="name">{{.T.item}}</th>
+                                <th class="sortable" data-sort="quality">{{.T.quality}}</th>
+                                <th class="sortable" data-sort="item_level">{{.T.level}}</th>
+                                <th class="sortable" data-sort="count">{{.T.count}}</th>
+                                <th class="sortable" data-sort="seller">{{.T.seller}}</th>
+                                <th class="sortable" data-sort="bid">{{.T.currentBid}}</th>
+                                <th class="sortable" data-sort="buyout">{{.T.buyout}}</th>
+                                <th class="sortable" data-sort="unit_buyout">{{.T.perUnit}}</th>
+                                <th class="sortable sort-asc" data-sort="time">{{.T.timeLeft}}</th>
+                            </tr>
+                        </thead>
+                        <tbody id="auctionsBody">
+                            <tr>
+                                <td colspan="9" class="loading">{{.T.loadingAuctions}}</td>
+                            </tr>
+                        </tbody>
+                    </table>
+                </div>
+            </div>
+
+            <div class="pagination" id="pagination"></div>
+        </div>
+
+        <div class="tab-panel" id="dealsTab" style="display: none;">
+            <div class="auctions-table">
+                <div class="table-header">
+                    <span class="table-tools">
+                        <label for="dealPercent">{{.T.maxPercent}}</label>
+                        <select id="dealPercent" onchange="loadDeals()">
+                            <option value="">{{.T.defaultOption}}</option>
+                            <option value="50">50%</option>
+                            <option value="60">60%</option>
+                            <option value="70">70%</option>
+                            <option value="80">80%</option>
+                            <option value="90">90%</option>
+                        </select>
+                    </span>
+                    <h2>{{.T.tabDeals}}</h2>
+                </div>
+                <div class="table-container">
+                    <table>
+                        <thead>
+                            <tr>
+                                <th>{{.T.item}}</th>
+                                <th>{{.T.count}}</th>
+                                <th>{{.T.seller}}</th>
+                                <th>{{.T.buyout}}</th>
+                                <th>{{.T.perUnit}}</th>
+                                <th>{{.T.marketPrice}}</th>
+                                <th>{{.T.percentOfMarket}}</th>
+                                <th>{{.T.profit}}</th>
+                                <th>{{.T.timeLeft}}</th>
+                            </tr>
+                        </thead>
+                        <tbody id="dealsBody">
+                            <tr>
+                                <td colspan="9" class="loading">{{.T.loading}}</td>
+                            </tr>
+                        </tbody>
+                    </table>
+                </div>
+            </div>
+        </div>
+
+        <div class="tab-panel" id="flipsTab" style="display: none;">
+            <div class="auctions-table">
+                <div class="table-header">
+                    <span class="table-tools">
+                        <label for="flipQuality">{{.T.quality}}</label>
+                        <select id="flipQuality" onchange="loadFlips()">
+                            <option value="">{{.T.allQualities}}</option>
+                            <option value="0">{{.T.qualityPoor}}</option>
+                            <option value="1">{{.T.qualityCommon}}</option>
+                            <option value="2">{{.T.qualityUncommon}}</option>
+                            <option value="3">{{.T.qualityRare}}</option>
+                            <option value="4">{{.T.qualityEpic}}</option>
+                        </select>
+                    </span>
+                    <h2>{{.T.tabFlips}}</h2>
+                </div>
+                <div class="table-container">
+                    <table>
+                        <thead>
+                            <tr>
+                                <th>{{.T.item}}</th>
+                                <th>{{.T.count}}</th>
+                                <th>{{.T.seller}}</th>
+                                <th>{{.T.price}}</th>
+                                <th>{{.T.vendorValue}}</th>
+                                <th>{{.T.profit}}</th>
+                                <th>{{.T.timeLeft}}</th>
+                            </tr>
+                        </thead>
+                        <tbody id="flipsBody">
+                            <tr>
+                                <td colspan="7" class="loading">{{.T.loading}}</td>
+                            </tr>
+                        </tbody>
+                    </table>
+                </div>
+            </div>
+        </div>
+
+        <div class="tab-panel" id="marketTab" style="display: none;">
+            <div class="auctions-table">
+                <div class="table-header">
+                    <span class="table-tools">
+                        <label for="marketOrder">{{.T.order}}</label>
+                        <select id="marketOrder" onchange="loadMarket()">
+                            <option value="asc">{{.T.cheapestFirst}}</option>
+                            <option value="desc">{{.T.priciestFirst}}</option>
+                        </select>
+                    </span>
+                    <h2>{{.T.tabMarket}}</h2>
+                </div>
+                <div class="table-container">
+                    <table>
+                        <thead>
+                            <tr>
+                                <th>{{.T.item}}</th>
+                                <th>{{.T.listings}}</th>
+                                <th>{{.T.units}}</th>
+                                <th>{{.T.lowest}}</th>
+                                <th>{{.T.median}}</th>
+                                <th>{{.T.auctionatorPrice}}</th>
+                                <th>{{.T.percentOfMarket}}</th>
+                                <th>{{.T.scanned}}</th>
+                            </tr>
+                        </thead>
+                        <tbody id="marketBody">
+                            <tr>
+                                <td colspan="8" class="loading">{{.T.loading}}</td>
+                            </tr>
+                        </tbody>
+                    </table>
+                </div>
+            </div>
+        </div>
+
+        {{if .Crafting}}
+        <div class="tab-panel" id="craftingTab" style="display: none;">
+            <div class="auctions-table">
+                <div class="table-header">
+                    <span class="table-tools">
+                        <label for="craftingSkill">{{.T.profession}}</label>
+                        <select id="craftingSkill" onchange="loadCrafting()"></select>
+                    </span>
+                    <h2>{{.T.tabCrafting}}</h2>
+                </div>
+                <div class="table-container">
+                    <table>
+                        <thead>
+                            <tr>
+                                <th>{{.T.item}}</th>
+                                <th>{{.T.skill}}</th>
+                                <th>{{.T.reagents}}</th>
+                                <th>{{.T.reagentCost}}</th>
+                                <th>{{.T.perUnit}}</th>
+                                <th>{{.T.afterCut}}</th>
+                                <th>{{.T.margin}}</th>
+                            </tr>
+                        </thead>
+                        <tbody id="craftingBody">
+                            <tr>
+                                <td colspan="7" class="loading">{{.T.loading}}</td>
+                            </tr>
+                        </tbody>
+                    </table>
+                </div>
+            </div>
+        </div>
+        {{end}}
+
+        {{if .Yields}}
+        <div class="tab-panel" id="yieldsTab" style="display: none;">
+            <div class="auctions-table">
+                <div class="table-header">
+                    <span class="table-tools">
+                        <label for="yieldMethod">{{.T.method}}</label>
+                        <select id="yieldMethod" onchange="loadYields()">
+                            <option value="">{{.T.allMethods}}</option>
+                            <option value="disenchant">{{.T.methodDisenchant}}</option>
+                            <option value="milling">{{.T.methodMilling}}</option>
+                            <option value="prospecting">{{.T.methodProspecting}}</option>
+                        </select>
+                        <label><input type="checkbox" id="yieldBelow" checked onchange="loadYields()"> {{.T.belowYieldOnly}}</label>
+                    </span>
+                    <h2>{{.T.tabYields}}</h2>
+                </div>
+                <div class="table-container">
+                    <table>
+                        <thead>
+                            <tr>
+                                <th>{{.T.item}}</th>
+                                <th>{{.T.count}}</th>
+                                <th>{{.T.method}}</th>
+                                <th>{{.T.buyout}}</th>
+                                <th>{{.T.expectedYield}}</th>
+                                <th>{{.T.yieldValue}}</th>
+                                <th>{{.T.profit}}</th>
+                                <th>{{.T.timeLeft}}</th>
+                            </tr>
+                        </thead>
+                        <tbody id="yieldsBody">
+                            <tr>
+                                <td colspan="8" class="loading">{{.T.loading}}</td>
+                            </tr>
+                        </tbody>
+                    </table>
+                </div>
+            </div>
+        </div>
+        {{end}}
+
+        {{if .Account}}
+        <div class="tab-panel" id="mineTab" style="display: none;">
+            <div class="auctions-table">
+                <div class="table-header">
+                    <h2>{{.T.tabMyAuctions}}</h2>
+                </div>
+                <div class="table-container">
+                    <table>
+                        <thead>
+                            <tr>
+                                <th>{{.T.item}}</th>
+                                <th>{{.T.count}}</th>
+                                <th>{{.T.seller}}</th>
+                                <th>{{.T.currentBid}}</th>
+                                <th>{{.T.buyout}}</th>
+                                <th>{{.T.perUnit}}</th>
+                                <th>{{.T.timeLeft}}</th>
+                            </tr>
+                        </thead>
+                        <tbody id="mineBody">
+                            <tr>
+                                <td colspan="7" class="loading">{{.T.loading}}</td>
+                            </tr>
+                        </tbody>
+                    </table>
+                </div>
+            </div>
+        </div>
+        {{end}}
+    </div>
+
+    <div class="tooltip" id="tooltip" style="display: none;"></div>
+
+    <script>
+        const T = {{.T}};
+        let currentPage = 1;
+        let pageCursors = [''];
+        let currentSearch = '';
+        let currentSellers = [];
+        let sortColumn = 'time';
+        let sortDirection = 'asc';
+        let sellersSortColumn = '';
+        let sellersSortDirection = 'asc';
+        let currentHouse = localStorage.getItem('house') || '';
+        let currentRealm = new URLSearchParams(location.search).get('realm') || localStorage.getItem('realm') || '';
+        let stream = null;
+        let newAuctions = 0;
+        let statsTimer = null;
+        let currentTab = 'auctions';
+        const tooltipsEnabled = {{.Tooltips}};
+        const tooltipCache = {};
+        let tooltipTarget = null;
+
+        // Load initial data
+        document.addEventListener('DOMContentLoaded', function() {
+            loadHouses();
+            loadRealms().then(function() {
+                loadStats();
+                loadAuctions();
+                connectStream();
+            });
+        });
+
+        // Language selector handler. The cookie names items in API responses
+        // too, so the page is simply reloaded.
+        document.getElementById('localeSelect').addEventListener('change', function() {
+            document.cookie = 'locale=' + this.value + '; path=/; max-age=31536000; SameSite=Lax';
+            const params = new URLSearchParams(location.search);
+            if (params.has('locale')) {
+                params.delete('locale');
+                location.search = params.toString();
+            } else {
+                location.reload();
+            }
+        });
+
+        // Realm selector handler
+        document.getElementById('realmSelect').addEventListener('change', function() {
+            currentRealm = this.value;
+            localStorage.setItem('realm', currentRealm);
+            history.replaceState(null, '', '?realm=' + encodeURIComponent(currentRealm));
+            resetPaging();
+            loadStats();
+            loadAuctions();
+            connectStream();
+            reloadTab();
+            if (document.getElementById('sellersSection').style.display !== 'none') {
+                loadSellers();
+            }
+        });
+
+        // House selector handler
+        document.getElementById('houseSelect').addEventListener('change', function() {
+            currentHouse = this.value;
+            localStorage.setItem('house', currentHouse);
+            resetPaging();
+            loadStats();
+            loadAuctions();
+            connectStream();
+            reloadTab();
+            if (document.getElementById('sellersSection').style.display !== 'none') {
+                loadSellers();
+            }
+        });
+
+        // Search form handler
+        document.getElementById('searchForm').addEventListener('submit', function(e) {
+            e.preventDefault();
+            currentSearch = document.getElementById('searchInput').value.trim();
+            resetPaging();
+            loadAuctions();
+        });
+
+        // Add click handlers for sortable columns
+        document.addEventListener('DOMContentLoaded', function() {
+            // Auction table sorting
+            const auctionHeaders = document.querySelectorAll('#auctionsTable th.sortable');
+            auctionHeaders.forEach(header => {
+                header.addEventListener('click', function() {
+                    const column = this.getAttribute('data-sort');
+                    if (sortColumn === column) {
+                        sortDirection = sortDirection === 'asc' ? 'desc' : 'asc';
+                    } else {
+                        sortColumn = column;
+                        sortDirection = 'asc';
+                    }
+                    
+                    // Update sort indicators
+                    auctionHeaders.forEach(h => {
+                        h.classList.remove('sort-asc', 'sort-desc');
+                    });
+                    this.classList.add(sortDirection === 'asc' ? 'sort-asc' : 'sort-desc');
+                    
+                    // Sorting happens on the server across all pages
+                    resetPaging();
+                    loadAuctions();
+                });
+            });
+
+            // Sellers table sorting
+            const sellersHeaders = document.querySelectorAll('#sellersTable th.sortable');
+            sellersHeaders.forEach(header => {
+                header.addEventListener('click', function() {
+                    const column = this.getAttribute('data-sort');
+                    if (sellersSortColumn === column) {
+                        sellersSortDirection = sellersSortDirection === 'asc' ? 'desc' : 'asc';
+                    } else {
+                        sellersSortColumn = column;
+                        sellersSortDirection = 'asc';
+                    }
+                    
+                    // Update sort indicators
+                    sellersHeaders.forEach(h => {
+                        h.classList.remove('sort-asc', 'sort-desc');
+                    });
+                    this.classList.add(sellersSortDirection === 'asc' ? 'sort-asc' : 'sort-desc');
+                    
+                    // Sort and display sellers
+                    sortSellers();
+                });
+            });
+        });
+
+        // The realm picker is only shown when the server has several realms
+        async function loadRealms() {
+            try {
+                const response = await fetch('/api/realms');
+                const data = await response.json();
+                const select = document.getElementById('realmSelect');
+                data.realms.forEach(function(realm) {
+                    const option = document.createElement('option');
+                    option.value = realm.id;
+                    option.textContent = realm.name;
+                    select.appendChild(option);
+                    if (realm.default && !data.realms.some(r => String(r.id) === currentRealm)) {
+                        currentRealm = String(realm.id);
+                    }
+                });
+                select.value = currentRealm;
+                if (data.realms.length > 1) {
+                    select.style.display = '';
+                }
+            } catch (error) {
+                console.error('Error loading realms:', error);
+            }
+        }
+
+        // apiBase is the API prefix of the selected realm
+        function apiBase() {
+            return currentRealm ? '/api/realms/' + encodeURIComponent(currentRealm) : '/api';
+        }
+
+        function realmQuery() {
+            return currentRealm ? '?realm=' + encodeURIComponent(currentRealm) : '';
+        }
+
+        async function loadHouses() {
+            try {
+                const response = await fetch(apiBase() + '/houses');
+                const data = await response.json();
+                const select = document.getElementById('houseSelect');
+                data.houses.forEach(function(house) {
+                    const option = document.createElement('option');
+                    option.value = house.id;
+                    option.textContent = house.name + ' (deposit ' + house.deposit_rate + '%, cut ' + house.consignment_rate + '%)';
+                    select.appendChild(option);
+                });
+                select.value = currentHouse;
+            } catch (error) {
+                console.error('Error loading auction houses:', error);
+            }
+        }
+
+        function houseParam() {
+            return currentHouse ? '&house=' + encodeURIComponent(currentHouse) : '';
+        }
+
+        async function loadStats() {
+            try {
+                const response = await fetch(apiBase() + '/stats?' + houseParam());
+                const stats = await response.json();
+                
+                document.getElementById('totalItems').textContent = stats.total_items.toLocaleString();
+                document.getElementById('totalValue').textContent = formatGold(stats.total_value);
+                document.getElementById('activeBids').textContent = stats.active_bids.toLocaleString();
+                document.getElementById('uniqueOwners').textContent = stats.unique_owners.toLocaleString();
+            } catch (error) {
+                console.error('Error loading stats:', error);
+            }
+        }
+
+        async function loadAuctions() {
+            const base = currentSearch
+                ? apiBase() + '/search?q=' + encodeURIComponent(currentSearch) + '&'
+                : apiBase() + '/auctions?';
+            const cursor = pageCursors[currentPage - 1];
+            const url = base + 'sort=' + sortColumn + '&order=' + sortDirection +
+                (cursor ? '&cursor=' + encodeURIComponent(cursor) : '') + houseParam();
+
+            try {
+                const response = await fetch(url);
+                if (!response.ok) {
+                    throw new Error((await response.json()).error.message);
+                }
+                const data = await response.json();
+                newAuctions = 0;
+                updateLiveNotice();
+                displayAuctions(data.auctions);
+                updatePagination(data.limit, data.total, data.next_cursor);
+            } catch (error) {
+                console.error('Error loading auctions:', error);
+                document.getElementById('auctionsBody').innerHTML = 
+                    '<tr><td colspan="9" class="error">' + T.errorAuctions + '</td></tr>';
+            }
+        }
+
+        // connectStream follows the auction changes of the selected realm and
+        // house. Bids and ended auctions update the rows shown; new auctions
+        // are counted until the table is next loaded, as they may belong on
+        // another page.
+        function connectStream() {
+            if (stream) {
+                stream.close();
+            }
+            stream = new EventSource(apiBase() + '/stream?' + houseParam());
+
+            stream.addEventListener('listed', function() {
+                newAuctions++;
+                updateLiveNotice();
+                scheduleStats();
+            });
+            stream.addEventListener('bid', function(e) {
+                const auction = JSON.parse(e.data).auction;
+                const row = auctionRow(auction.id);
+                if (row) {
+                    row.querySelector('.bid').textContent = formatGold(auction.last_bid);
+                    row.classList.remove('row-bid');
+                    void row.offsetWidth; // restart the animation
+                    row.classList.add('row-bid');
+                }
+                scheduleStats();
+            });
+            ['sold', 'expired', 'cancelled', 'removed'].forEach(function(type) {
+                stream.addEventListener(type, function(e) {
+                    const row = auctionRow(JSON.parse(e.data).auction.id);
+                    if (row) {
+                        row.querySelector('.time-left').textContent = T[type];
+                        row.classList.add('row-ended');
+                    }
+                    scheduleStats();
+                });
+            });
+        }
+
+        function auctionRow(id) {
+            return document.querySelector('#auctionsBody tr[data-id="' + id + '"]');
+        }
+
+        function updateLiveNotice() {
+            document.getElementById('liveCount').textContent = T.newAuctions.replace('{n}', newAuctions);
+            document.getElementById('liveNotice').style.display = newAuctions > 0 ? '' : 'none';
+        }
+
+        function showNewAuctions() {
+            loadAuctions();
+        }
+
+        // scheduleStats reloads the statistics once a burst of events is over
+        function scheduleStats() {
+            clearTimeout(statsTimer);
+            statsTimer = setTimeout(loadStats, 1000);
+        }
+
+        // Tabs other than the auctions, which are always kept up to date, are
+        // loaded when shown
+        const tabLoaders = {
+            deals: loadDeals,
+            flips: loadFlips,
+            market: loadMarket,
+            crafting: loadCrafting,
+            yields: loadYields,
+            mine: loadMyAuctions,
+        };
+
+        function showTab(tab) {
+            currentTab = tab;
+            document.querySelectorAll('.tab-button').forEach(function(button) {
+                button.classList.toggle('active', button.dataset.tab === tab);
+            });
+            document.querySelectorAll('.tab-panel').forEach(function(panel) {
+                panel.style.display = panel.id === tab + 'Tab' ? '' : 'none';
+            });
+            reloadTab();
+        }
+
+        function reloadTab() {
+            if (tabLoaders[currentTab]) {
+                tabLoaders[currentTab]();
+            }
+        }
+
+        async function loadDeals() {
+            const percent = document.getElementById('dealPercent').value;
+            const url = apiBase() + '/deals?' + (percent ? 'max_percent=' + percent : '') + houseParam();
+            const tbody = document.getElementById('dealsBody');
+            try {
+                const response = await fetch(url);
+                if (!response.ok) {
+                    throw new Error((await response.json()).error.message);
+                }
+                const data = await response.json();
+                if (data.deals.length === 0) {
+                    tbody.innerHTML = '<tr><td colspan="9" class="loading">' + T.noDeals + '</td></tr>';
+                    return;
+                }
+                tbody.innerHTML = data.deals.map(function(deal) {
+                    const itemUrl = '/items/' + deal.item_entry + realmQuery();
+                    const source = deal.price_source === 'auctionator' ? T.sourceAuctionator : T.sourceMedian;
+                    return '<tr>' +
+                        '<td><a href="' + itemUrl + '" class="item-link"' + tooltipAttr('items/' + deal.item_entry) + '><span class="quality-' + deal.quality + '">' + deal.item_name + '</span></a></td>' +
+                        '<td>' + deal.count + '</td>' +
+                        '<td>' + deal.owner_name + '</td>' +
+                        '<td class="price">' + formatGold(deal.buyout_price) + '</td>' +
+                        '<td class="price">' + formatGold(deal.unit_buyout) + '</td>' +
+                        '<td class="price" title="' + source + '">' + formatGold(deal.market_price) + '</td>' +
+                        '<td>' + deal.percent_of_market + '%</td>' +
+                        '<td class="profit">' + formatGold(deal.profit) + '</td>' +
+                        '<td class="time-left">' + deal.time_left + '</td>' +
+                        '</tr>';
+                }).join('');
+            } catch (error) {
+                console.error('Error loading deals:', error);
+                tbody.innerHTML = '<tr><td colspan="9" class="error">' + T.errorDeals + '</td></tr>';
+            }
+        }
+
+        // Buyouts are guaranteed profits; bids only pay off if they win
+        async function loadFlips() {
+            const quality = document.getElementById('flipQuality').value;
+            const url = apiBase() + '/vendor-flips?' +
+                (quality ? 'quality_min=' + quality + '&quality_max=' + quality : '') + houseParam();
+            const tbody = document.getElementById('flipsBody');
+            try {
+                const response = await fetch(url);
+                if (!response.ok) {
+                    throw new Error((await response.json()).error.message);
+                }
+                const data = await response.json();
+                if (data.flips.length === 0) {
+                    tbody.innerHTML = '<tr><td colspan="7" class="loading">' + T.noFlips + '</td></tr>';
+                    return;
+                }
+                tbody.innerHTML = data.flips.map(function(flip) {
+                    const itemUrl = '/items/' + flip.item_entry + realmQuery();
+                    const price = formatGold(flip.price) + (flip.buy === 'bid' ? ' (' + T.bid + ')' : '');
+                    return '<tr>' +
+                        '<td><a href="' + itemUrl + '" class="item-link"' + tooltipAttr('items/' + flip.item_entry) + '><span class="quality-' + flip.quality + '">' + flip.item_name + '</span></a></td>' +
+                        '<td>' + flip.count + '</td>' +
+                        '<td>' + flip.owner_name + '</td>' +
+                        '<td class="price">' + price + '</td>' +
+                        '<td class="price">' + formatGold(flip.vendor_value) + '</td>' +
+                        '<td class="profit">' + formatGold(flip.profit) + '</td>' +
+                        '<td class="time-left">' + flip.time_left + '</td>' +
+                        '</tr>';
+                }).join('');
+            } catch (error) {
+                console.error('Error loading vendor flips:', error);
+                tbody.innerHTML = '<tr><td colspan="7" class="error">' + T.errorFlips + '</td></tr>';
+            }
+        }
+
+        // Compares the lowest buyout of each item with mod_auctionator's
+        // market price
+        async function loadMarket() {
+            const url = apiBase() + '/market-comparison?order=' + document.getElementById('marketOrder').value + houseParam();
+            const tbody = document.getElementById('marketBody');
+            try {
+                const response = await fetch(url);
+                if (!response.ok) {
+                    throw new Error((await response.json()).error.message);
+                }
+                const data = await response.json();
+                if (data.items.length === 0) {
+                    tbody.innerHTML = '<tr><td colspan="8" class="loading">' + T.noComparisons + '</td></tr>';
+                    return;
+                }
+                tbody.innerHTML = data.items.map(function(item) {
+                    const itemUrl = '/items/' + item.entry + realmQuery();
+                    return '<tr>' +
+                        '<td><a href="' + itemUrl + '" class="item-link"' + tooltipAttr('items/' + item.entry) + '><span class="quality-' + item.quality + '">' + item.item_name + '</span></a></td>' +
+                        '<td>' + item.listings + '</td>' +
+                        '<td>' + item.units + '</td>' +
+                        '<td class="price">' + formatGold(item.min_unit_buyout) + '</td>' +
+                        '<td class="price">' + formatGold(item.median_unit_buyout) + '</td>' +
+                        '<td class="price">' + formatGold(item.market_price) + '</td>' +
+                        '<td>' + item.percent_of_market + '%</td>' +
+                        '<td>' + new Date(item.scanned_at).toLocaleDateString() + '</td>' +
+                        '</tr>';
+                }).join('');
+            } catch (error) {
+                console.error('Error loading market comparison:', error);
+                tbody.innerHTML = '<tr><td colspan="8" class="error">' + T.errorMarket + '</td></tr>';
+            }
+        }
+
+        // loadCrafting prices the recipes of the selected profession,
+        // loading the professions the first time
+        async function loadCrafting() {
+            const select = document.getElementById('craftingSkill');
+            const tbody = document.getElementById('craftingBody');
+            try {
+                if (select.options.length === 0) {
+                    const response = await fetch('/api/crafting');
+                    if (!response.ok) {
+                        throw new Error((await response.json()).error.message);
+                    }
+                    const data = await response.json();
+                    data.professions.forEach(function(profession) {
+                        const option = document.createElement('option');
+                        option.value = profession.id;
+                        option.textContent = profession.name;
+                        select.appendChild(option);
+                    });
+                    if (select.options.length === 0) {
+                        tbody.innerHTML = '<tr><td colspan="7" class="loading">' + T.noProfessions + '</td></tr>';
+                        return;
+                    }
+                }
+                const response = await fetch(apiBase() + '/crafting/' + encodeURIComponent(select.value) + '?' + houseParam());
+                if (!response.ok) {
+                    throw new Error((await response.json()).error.message);
+                }
+                const data = await response.json();
+                if (data.crafts.length === 0) {
+                    tbody.innerHTML = '<tr><td colspan="7" class="loading">' + T.noRecipes + '</td></tr>';
+                    return;
+                }
+                tbody.innerHTML = data.crafts.map(function(craft) {
+                    const product = craft.product;
+                    const count = craft.min_count === craft.max_count ? craft.min_count : craft.min_count + '-' + craft.max_count;
+                    const reagents = craft.reagents.map(function(reagent) {
+                        const price = reagent.unit_price > 0 ? formatGold(reagent.unit_price) : T.notListed;
+                        return reagent.count + '× <a href="/items/' + reagent.entry + realmQuery() + '" class="item-link"' + tooltipAttr('items/' + reagent.entry) + '>' +
+                            '<span class="quality-' + reagent.quality + '">' + escapeHTML(reagent.name) + '</span></a> (' + price + ')';
+                    }).join('<br>');
+                    return '<tr>' +
+                        '<td><a href="/items/' + product.entry + realmQuery() + '" class="item-link"' + tooltipAttr('items/' + product.entry) + '><span class="quality-' + product.quality + '">' + escapeHTML(product.name) + '</span></a>' +
+                        (count !== 1 ? ' ×' + count : '') + '</td>' +
+                        '<td>' + craft.min_skill + '</td>' +
+                        '<td class="reagents">' + reagents + '</td>' +
+                        '<td class="price">' + (craft.priced ? formatGold(craft.cost) : '') + '</td>' +
+                        '<td class="price">' + (product.unit_price > 0 ? formatGold(product.unit_price) : T.notListed) + '</td>' +
+                        '<td class="price">' + (craft.priced ? formatGold(craft.revenue) : '') + '</td>' +
+                        (craft.priced ? '<td class="' + (craft.margin < 0 ? 'loss' : 'profit') + '">' + formatSignedGold(craft.margin) + ' (' + craft.margin_percent + '%)</td>' : '<td></td>') +
+                        '</tr>';
+                }).join('');
+            } catch (error) {
+                console.error('Error loading crafting:', error);
+                tbody.innerHTML = '<tr><td colspan="7" class="error">' + T.errorCrafting + '</td></tr>';
+            }
+        }
+
+        // loadYields values the listings of items that can be disenchanted,
+        // milled or prospected by what they break down into
+        async function loadYields() {
+            const method = document.getElementById('yieldMethod').value;
+            const below = document.getElementById('yieldBelow').checked;
+            const url = apiBase() + '/yields?' + (method ? 'method=' + method + '&' : '') + 'below_yield=' + below + houseParam();
+            const tbody = document.getElementById('yieldsBody');
+            const methods = {disenchant: T.methodDisenchant, milling: T.methodMilling, prospecting: T.methodProspecting};
+            try {
+                const response = await fetch(url);
+                if (!response.ok) {
+                    throw new Error((await response.json()).error.message);
+                }
+                const data = await response.json();
+                if (data.listings.length === 0) {
+                    tbody.innerHTML = '<tr><td colspan="8" class="loading">' + T.noYields + '</td></tr>';
+                    return;
+                }
+                tbody.innerHTML = data.listings.map(function(listing) {
+                    const itemUrl = '/items/' + listing.item_entry + realmQuery();
+                    const outputs = listing.outputs.map(function(output) {
+                        const price = output.unit_price > 0 ? formatGold(output.unit_price) : T.notListed;
+                        return output.expected.toFixed(2) + '× <a href="/items/' + output.entry + realmQuery() + '" class="item-link"' + tooltipAttr('items/' + output.entry) + '>' +
+                            '<span class="quality-' + output.quality + '">' + escapeHTML(output.name) + '</span></a> (' + price + ')';
+                    }).join('<br>');
+                    const method = methods[listing.method] + (listing.required_skill ? ' (' + listing.required_skill + ')' : '');
+                    return '<tr>' +
+                        '<td><a href="' + itemUrl + '" class="item-link"' + tooltipAttr('auctions/' + listing.id) + '><span class="quality-' + listing.quality + '">' + listing.item_name + '</span></a></td>' +
+                        '<td>' + listing.count + '</td>' +
+                        '<td>' + method + '</td>' +
+                        '<td class="price">' + formatGold(listing.buyout_price) + '</td>' +
+                        '<td class="reagents">' + outputs + '</td>' +
+                        '<td class="price">' + formatGold(listing.yield_value) + '</td>' +
+                        '<td class="' + (listing.below_yield ? 'profit' : 'loss') + '">' + formatSignedGold(listing.profit) + '</td>' +
+                        '<td class="time-left">' + listing.time_left + '</td>' +
+                        '</tr>';
+                }).join('');
+            } catch (error) {
+                console.error('Error loading yields:', error);
+                tbody.innerHTML = '<tr><td colspan="8" class="error">' + T.errorYields + '</td></tr>';
+            }
+        }
+
+        // loadMyAuctions lists the auctions of the characters on the account
+        // signed in, ending soonest first
+        async function loadMyAuctions() {
+            const url = apiBase() + '/my-auctions?sort=time&limit=200' + houseParam();
+            const tbody = document.getElementById('mineBody');
+            try {
+                const response = await fetch(url);
+                if (!response.ok) {
+                    throw new Error((await response.json()).error.message);
+                }
+                const data = await response.json();
+                if (data.auctions.length === 0) {
+                    tbody.innerHTML = '<tr><td colspan="7" class="loading">' + T.noMyAuctions + '</td></tr>';
+                    return;
+                }
+                tbody.innerHTML = data.auctions.map(function(auction) {
+                    const itemUrl = '/items/' + auction.item_entry + realmQuery();
+                    return '<tr>' +
+                        '<td><a href="' + itemUrl + '" class="item-link"' + tooltipAttr('auctions/' + auction.id) + '><span class="quality-' + auction.quality + '">' + auction.item_name + '</span></a></td>' +
+                        '<td>' + auction.count + '</td>' +
+                        '<td>' + auction.owner_name + '</td>' +
+                        '<td class="price">' + formatGold(auction.last_bid || auction.start_bid) + '</td>' +
+                        '<td class="price">' + (auction.buyout_price > 0 ? formatGold(auction.buyout_price) : T.noBuyout) + '</td>' +
+                        '<td class="price"' + auctionatorTitle(auction) + '>' + (auction.buyout_price > 0 && auction.count > 1 ? formatGold(auction.unit_buyout) : '') + '</td>' +
+                        '<td class="time-left">' + auction.time_left + '</td>' +
+                        '</tr>';
+                }).join('');
+            } catch (error) {
+                console.error('Error loading my auctions:', error);
+                tbody.innerHTML = '<tr><td colspan="7" class="error">' + T.errorMyAuctions + '</td></tr>';
+            }
+        }
+
+        async function signOut(event) {
+            event.preventDefault();
+            await fetch('/api/logout', {method: 'POST'});
+            location.reload();
+        }
+
+        function resetPaging() {
+            currentPage = 1;
+            pageCursors = [''];
+        }
+
+        function toggleSellers() {
+            const sellersSection = document.getElementById('sellersSection');
+            const button = event.target;
+            
+            if (sellersSection.style.display === 'none') {
+                sellersSection.style.display = 'block';
+                button.textContent = T.hideSellers;
+                loadSellers();
+            } else {
+                sellersSection.style.display = 'none';
+                button.textContent = T.showSellers;
+            }
+        }
+
+        async function loadSellers() {
+            try {
+                const response = await fetch(apiBase() + '/sellers?' + houseParam());
+                const data = await response.json();
+                currentSellers = data.sellers;
+                sortSellers();
+            } catch (error) {
+                console.error('Error loading sellers:', error);
+                document.getElementById('sellersBody').innerHTML = 
+                    '<tr><td colspan="4" class="error">' + T.errorSellers + '</td></tr>';
+            }
+        }
+
+        function sortSellers() {
+            if (!currentSellers || currentSellers.length === 0) {
+                displaySellers([]);
+                return;
+            }
+
+            const sortedSellers = [...currentSellers].sort((a, b) => {
+                let aVal, bVal;
+
+                switch (sellersSortColumn) {
+                    case 'name':
+                        aVal = a.name.toLowerCase();
+                        bVal = b.name.toLowerCase();
+                        break;
+                    case 'total_auctions':
+                        aVal = a.total_auctions;
+                        bVal = b.total_auctions;
+                        break;
+                    case 'total_value':
+                        aVal = a.total_value;
+                        bVal = b.total_value;
+                        break;
+                    case 'unique_items':
+                        aVal = a.unique_items;
+                        bVal = b.unique_items;
+                        break;
+                    default:
+                        return 0;
+                }
+
+                if (aVal < bVal) return sellersSortDirection === 'asc' ? -1 : 1;
+                if (aVal > bVal) return sellersSortDirection === 'asc' ? 1 : -1;
+                return 0;
+            });
+
+            displaySellers(sortedSellers);
+        }
+
+        function displaySellers(sellers) {
+            const tbody = document.getElementById('sellersBody');
+            
+            if (sellers.length === 0) {
+                tbody.innerHTML = '<tr><td colspan="4" class="loading">' + T.noSellers + '</td></tr>';
+                return;
+            }
+
+            tbody.innerHTML = sellers.map(function(seller) {
+                return '<tr>' +
+                    '<td>' + seller.name + '</td>' +
+                    '<td>' + seller.total_auctions.toLocaleString() + '</td>' +
+                    '<td class="price">' + formatGold(seller.total_value) + '</td>' +
+                    '<td>' + seller.unique_items.toLocaleString() + '</td>' +
+                    '</tr>';
+            }).join('');
+        }
+
+        function displayAuctions(auctions) {
+            const tbody = document.getElementById('auctionsBody');
+            
+            if (auctions.length === 0) {
+                tbody.innerHTML = '<tr><td colspan="9" class="loading">' + T.noAuctions + '</td></tr>';
+                return;
+            }
+
+            tbody.innerHTML = auctions.map(function(auction) {
+                const itemUrl = '/items/' + auction.item_entry + realmQuery();
+                return '<tr data-id="' + auction.id + '">' +
+                    '<td><a href="' + itemUrl + '" class="item-link"' + tooltipAttr('auctions/' + auction.id) + '><span class="quality-' + auction.quality + '">' + auction.item_name + '</span></a></td>' +
+                    '<td><span class="quality-' + auction.quality + '">' + getQualityName(auction.quality) + '</span></td>' +
+                    '<td>' + auction.item_level + '</td>' +
+                    '<td>' + auction.count + '</td>' +
+                    '<td>' + auction.owner_name + '</td>' +
+                    '<td class="price bid">' + formatGold(auction.last_bid || auction.start_bid) + '</td>' +
+                    '<td class="price">' + (auction.buyout_price > 0 ? formatGold(auction.buyout_price) : T.noBuyout) + '</td>' +
+                    '<td class="price"' + auctionatorTitle(auction) + '>' + (auction.buyout_price > 0 && auction.count > 1 ? formatGold(auction.unit_buyout) : '') + '</td>' +
+                    '<td class="time-left">' + auction.time_left + '</td>' +
+                    '</tr>';
+            }).join('');
+        }
+
+        // auctionatorTitle shows mod_auctionator's market price of an
+        // auction's item on hover
+        function auctionatorTitle(auction) {
+            if (!auction.auctionator_price) return '';
+            return ' title="' + T.auctionatorPrice + ': ' + formatGold(auction.auctionator_price) + '"';
+        }
+
+        // Pages are fetched with the cursor returned by the previous page, so
+        // the cursors seen so far are kept to allow stepping back.
+        function updatePagination(limit, total, nextCursor) {
+            const pagination = document.getElementById('pagination');
+            const pages = Math.max(1, Math.ceil(total / limit));
+            pageCursors[currentPage] = nextCursor;
+            pagination.innerHTML = '';
+            
+            if (currentPage > 1) {
+                pagination.innerHTML += '<button onclick="changePage(' + (currentPage - 1) + ')">' + T.previous + '</button>';
+            }
+            
+            pagination.innerHTML += '<button class="active">' + currentPage + ' / ' + pages + '</button>';
+            if (nextCursor) {
+                pagination.innerHTML += '<button onclick="changePage(' + (currentPage + 1) + ')">' + T.next + '</button>';
+            }
+        }
+
+        function changePage(page) {
+            currentPage = page;
+            loadAuctions();
+        }
+
+        function formatGold(copper) {
+            if (!copper) return '0c';
+            
+            const gold = Math.floor(copper / 10000);
+            const silver = Math.floor((copper % 10000) / 100);
+            const copperRemainder = copper % 100;
+            
+            let result = '';
+            if (gold > 0) result += gold + 'g ';
+            if (silver > 0) result += silver + 's ';
+            if (copperRemainder > 0 || result === '') result += copperRemainder + 'c';
+            
+            return result.trim();
+        }
+
+        function formatSignedGold(copper) {
+            return copper < 0 ? '-' + formatGold(-copper) : formatGold(copper);
+        }
+
+        // Item tooltips are shown on hover over item links carrying the API
+        // path of their tooltip: an auction's, with its enchantments, or an
+        // item template's
+        function tooltipAttr(path) {
+            return tooltipsEnabled ? ' data-tooltip="' + path + '"' : '';
+        }
+
+        document.addEventListener('mouseover', function(event) {
+            const link = event.target.closest && event.target.closest('[data-tooltip]');
+            if (!link || link === tooltipTarget) return;
+            tooltipTarget = link;
+            showTooltip(link, event);
+        });
+
+        document.addEventListener('mouseout', function(event) {
+            if (tooltipTarget && !tooltipTarget.contains(event.relatedTarget)) {
+                tooltipTarget = null;
+                document.getElementById('tooltip').style.display = 'none';
+            }
+        });
+
+        document.addEventListener('mousemove', function(event) {
+            if (tooltipTarget) positionTooltip(event);
+        });
+
+        async function showTooltip(link, event) {
+            const url = apiBase() + '/' + link.getAttribute('data-tooltip') + '/tooltip';
+            if (!(url in tooltipCache)) {
+                tooltipCache[url] = fetch(url).then(function(response) {
+                    return response.ok ? response.json() : null;
+                }).catch(function() {
+                    return null;
+                });
+            }
+            const item = await tooltipCache[url];
+            if (!item || tooltipTarget !== link) return;
+            const tooltip = document.getElementById('tooltip');
+            tooltip.innerHTML = renderTooltip(item);
+            tooltip.style.display = 'block';
+            positionTooltip(event);
+        }
+
+        function positionTooltip(event) {
+            const tooltip = document.getElementById('tooltip');
+            const x = Math.min(event.clientX + 16, window.innerWidth - tooltip.offsetWidth - 8);
+            const y = Math.min(event.clientY + 16, window.innerHeight - tooltip.offsetHeight - 8);
+            tooltip.style.left = Math.max(8, x) + 'px';
+            tooltip.style.top = Math.max(8, y) + 'px';
+        }
+
+        // Keys of the stats of item_template.stat_type; the primary stats
+        // are listed as such, the others as equip effects
+        const primaryStats = {0: 'statMana', 1: 'statHealth', 3: 'statAgility', 4: 'statStrength', 5: 'statIntellect', 6: 'statSpirit', 7: 'statStamina'};
+        const equipStats = {
+            12: 'statDefense', 13: 'statDodge', 14: 'statParry', 15: 'statBlock',
+            16: 'statHit', 17: 'statHit', 18: 'statHit', 19: 'statCrit', 20: 'statCrit', 21: 'statCrit',
+            28: 'statHaste', 29: 'statHaste', 30: 'statHaste', 31: 'statHit', 32: 'statCrit',
+            35: 'statResilience', 36: 'statHaste', 37: 'statExpertise', 38: 'statAttackPower',
+            39: 'statRangedAttackPower', 40: 'statFeralAttackPower', 41: 'statHealing', 42: 'statSpellDamage',
+            43: 'statManaRegen', 44: 'statArmorPen', 45: 'statSpellPower', 46: 'statHealthRegen',
+            47: 'statSpellPen', 48: 'statBlockValue'
+        };
+        const slotKeys = {
+            1: 'slotHead', 2: 'slotNeck', 3: 'slotShoulder', 4: 'slotShirt', 5: 'slotChest', 6: 'slotWaist',
+            7: 'slotLegs', 8: 'slotFeet', 9: 'slotWrist', 10: 'slotHands', 11: 'slotFinger', 12: 'slotTrinket',
+            13: 'slotOneHand', 14: 'slotOffHand', 15: 'slotRanged', 16: 'slotBack', 17: 'slotTwoHand',
+            19: 'slotTabard', 20: 'slotChest', 21: 'slotMainHand', 22: 'slotOffHand', 23: 'slotHeldOffHand',
+            24: 'slotProjectile', 25: 'slotThrown', 26: 'slotRanged', 28: 'slotRelic'
+        };
+        const weaponKeys = {
+            0: 'weaponAxe', 1: 'weaponAxe', 2: 'weaponBow', 3: 'weaponGun', 4: 'weaponMace', 5: 'weaponMace',
+            6: 'weaponPolearm', 7: 'weaponSword', 8: 'weaponSword', 10: 'weaponStaff', 13: 'weaponFist',
+            14: 'weaponMisc', 15: 'weaponDagger', 18: 'weaponCrossbow', 19: 'weaponWand', 20: 'weaponFishingPole'
+        };
+        const armorKeys = {
+            1: 'armorCloth', 2: 'armorLeather', 3: 'armorMail', 4: 'armorPlate', 6: 'armorShield',
+            7: 'armorLibram', 8: 'armorIdol', 9: 'armorTotem', 10: 'armorSigil'
+        };
+        const bondingKeys = {1: 'bindPickup', 2: 'bindEquip', 3: 'bindUse', 4: 'questItem'};
+        const schoolKeys = {1: 'schoolHoly', 2: 'schoolFire', 3: 'schoolNature', 4: 'schoolFrost', 5: 'schoolShadow', 6: 'schoolArcane'};
+        const socketKeys = {1: 'socketMeta', 2: 'socketRed', 4: 'socketYellow', 8: 'socketBlue'};
+        const triggerKeys = {0: 'triggerUse', 1: 'triggerEquip', 2: 'triggerChance', 5: 'triggerUse', 6: 'triggerUse'};
+        const tooltipQualityColors = ['#9d9d9d', '#ffffff', '#1eff00', '#0070dd', '#a335ee', '#ff8000', '#e6cc80', '#e6cc80'];
+
+        // fill replaces the {placeholders} of a translated text
+        function fill(text, values) {
+            return text.replace(/\{(\w+)\}/g, function(match, name) {
+                return name in values ? values[name] : match;
+            });
+        }
+
+        // renderTooltip lays an item out the way the game's tooltip does
+        function renderTooltip(item) {
+            const lines = [];
+            const line = function(text, cls) {
+                lines.push('<div' + (cls ? ' class="' + cls + '"' : '') + '>' + text + '</div>');
+            };
+            const row = function(left, right) {
+                lines.push('<div class="tooltip-row"><span>' + left + '</span><span>' + right + '</span></div>');
+            };
+            const enchantments = item.enchantments || [];
+            const enchantmentName = function(e) {
+                return escapeHTML(e.name || '#' + e.id);
+            };
+
+            lines.push('<div class="tooltip-name" style="color: ' + (tooltipQualityColors[item.quality] || '#ffffff') + '">' + escapeHTML(item.name) + '</div>');
+            if (item.item_level > 1) line(fill(T.itemLevelN, {n: item.item_level}), 'tooltip-gold');
+            if (bondingKeys[item.bonding]) line(T[bondingKeys[item.bonding]]);
+            if (item.max_count === 1) line(T.unique);
+
+            const slot = slotKeys[item.inventory_type] ? T[slotKeys[item.inventory_type]] : '';
+            const subclass = item.class === 2 ? weaponKeys[item.subclass] : item.class === 4 ? armorKeys[item.subclass] : '';
+            if (slot || subclass) row(slot, subclass ? T[subclass] : '');
+            if (item.container_slots > 0) line(fill(T.bagSlots, {n: item.container_slots}));
+
+            (item.damage || []).forEach(function(d, i) {
+                const values = {min: Math.round(d.min), max: Math.round(d.max), school: T[schoolKeys[d.school]] || ''};
+                if (i === 0) {
+                    row(fill(d.school ? T.damageRangeSchool : T.damageRange, values), fill(T.speed, {n: (item.delay / 1000).toFixed(2)}));
+                } else {
+                    line(fill(T.damageRangeSchool, values));
+                }
+            });
+            if (item.dps > 0) line(fill(T.dps, {n: item.dps.toFixed(1)}));
+            if (item.armor > 0) line(fill(T.armorValue, {n: item.armor}));
+            if (item.block > 0) line(fill(T.blockValue, {n: item.block}));
+
+            const equips = [];
+            (item.stats || []).forEach(function(stat) {
+                if (primaryStats[stat.type]) {
+                    line(fill(T[primaryStats[stat.type]], {n: stat.value}));
+                } else {
+                    equips.push(fill(T[equipStats[stat.type]] || T.statOther, {n: stat.value, type: stat.type}));
+                }
+            });
+            (item.resistances || []).forEach(function(r) {
+                line(fill(T.resistance, {n: r.value, school: T[schoolKeys[r.school]]}));
+            });
+
+            enchantments.filter(e => e.kind === 'permanent' || e.kind === 'temporary' || e.kind === 'property').forEach(function(e) {
+                line(enchantmentName(e), 'tooltip-green');
+            });
+            (item.sockets || []).forEach(function(color, i) {
+                const gem = enchantments.find(e => e.kind === 'socket' && e.slot === 2 + i);
+                line(gem ? enchantmentName(gem) : T[socketKeys[color]] || T.socketRed, gem ? '' : 'tooltip-gray');
+            });
+            const prismatic = enchantments.find(e => e.kind === 'prismatic');
+            if (prismatic) line(enchantmentName(prismatic));
+            if (item.socket_bonus) {
+                const active = enchantments.some(e => e.kind === 'bonus');
+                line(fill(T.socketBonus, {name: enchantmentName(item.socket_bonus)}), active ? 'tooltip-green' : 'tooltip-gray');
+            }
+
+            if (item.max_durability > 0) line(fill(T.durability, {n: item.max_durability}));
+            if (item.required_level > 1) line(fill(T.requiresLevel, {n: item.required_level}));
+            equips.forEach(function(text) {
+                line(T.triggerEquip + ' ' + text, 'tooltip-green');
+            });
+            (item.spells || []).forEach(function(spell) {
+                const text = escapeHTML(spell.description || spell.name || '#' + spell.id);
+                line((T[triggerKeys[spell.trigger]] || T.triggerUse) + ' ' + text, 'tooltip-green');
+            });
+
+            if (item.set) {
+                line('<br>' + T.setPieces, 'tooltip-gold');
+                item.set.pieces.forEach(function(piece) {
+                    line('&nbsp;&nbsp;' + escapeHTML(piece.name), 'tooltip-gray');
+                });
+            }
+            if (item.description) line('"' + escapeHTML(item.description) + '"', 'tooltip-gold');
+            if (item.sell_price > 0) line(T.sellPrice + ' ' + formatGold(item.sell_price));
+            return lines.join('');
+        }
+
+        function escapeHTML(text) {
+            const div = document.createElement('div');
+            div.textContent = text;
+            return div.innerHTML;
+        }
+
+        function getQualityName(quality) {
+            const qualities = [T.qualityPoor, T.qualityCommon, T.qualityUncommon, T.qualityRare, T.qualityEpic, T.qualityLegendary];
+            return qualities[quality] || T.qualityUnknown;
+        }
+    </script>
+</body>
+</html>`
@@ -0,0 +1,705 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// ItemMarket summarizes the current listings of a single item. Buyout figures
+// are per unit and only consider listings that have a buyout.
+type ItemMarket struct {
+	Listings       int `json:"listings"`
+	Quantity       int `json:"quantity"`
+	BuyoutListings int `json:"buyout_listings"`
+	MinBuyout      int `json:"min_buyout"`
+	MedianBuyout   int `json:"median_buyout"`
+	MaxBuyout      int `json:"max_buyout"`
+	MeanBuyout     int `json:"mean_buyout"`
+	// Suffixes summarizes the listings of each random property or suffix
+	// of the item apart, since they sell for different prices
+	Suffixes []SuffixMarket `json:"suffixes,omitempty"`
+}
+
+// SuffixMarket summarizes the listings of an item that share a random
+// property or suffix
+type SuffixMarket struct {
+	RandomPropertyID int    `json:"random_property_id"`
+	Suffix           string `json:"suffix"`
+	ItemMarket
+}
+
+var itemPage = template.Must(template.New("item").Parse(itemTemplate))
+
+// itemEntry reads the entry path value, writing an error response if it is
+// invalid
+func itemEntry(w http.ResponseWriter, r *http.Request) (int, bool) {
+	entry, err := strconv.Atoi(r.PathValue("entry"))
+	if err != nil || entry <= 0 {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid item entry")
+		return 0, false
+	}
+	return entry, true
+}
+
+func (s *Server) handleItemPage(w http.ResponseWriter, r *http.Request) {
+	entry, ok := itemEntry(w, r)
+	if !ok {
+		return
+	}
+
+	renderPage(w, r, itemPage, pageData(pageLocale(w, r), map[string]interface{}{
+		"Entry": entry,
+	}))
+}
+
+func (s *Server) handleGetItem(w http.ResponseWriter, r *http.Request, rm *realm) {
+	entry, ok := itemEntry(w, r)
+	if !ok {
+		return
+	}
+	house, err := parseHouse(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid house")
+		return
+	}
+	locale, err := parseLocale(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid locale")
+		return
+	}
+
+	item, err := rm.Auctions.Item(r.Context(), entry, locale)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "Item not found")
+		return
+	}
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	auctions, err := rm.Auctions.ItemAuctions(r.Context(), entry, house, locale)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"item":     item,
+		"market":   summarizeMarket(auctions),
+		"auctions": auctions,
+	})
+}
+
+// summarizeMarket computes per-unit statistics over an item's listings, and
+// over those of each of its random properties and suffixes, ordered by name
+func summarizeMarket(auctions []store.AuctionItem) ItemMarket {
+	market := summarizeListings(auctions)
+	bySuffix := make(map[int][]store.AuctionItem)
+	for _, auction := range auctions {
+		if auction.RandomPropertyID != 0 {
+			bySuffix[auction.RandomPropertyID] = append(bySuffix[auction.RandomPropertyID], auction)
+		}
+	}
+	for id, listings := range bySuffix {
+		market.Suffixes = append(market.Suffixes, SuffixMarket{
+			RandomPropertyID: id,
+			Suffix:           listings[0].Suffix,
+			ItemMarket:       summarizeListings(listings),
+		})
+	}
+	sort.Slice(market.Suffixes, func(i, j int) bool {
+		a, b := market.Suffixes[i], market.Suffixes[j]
+		if a.Suffix != b.Suffix {
+			return a.Suffix < b.Suffix
+		}
+		return a.RandomPropertyID < b.RandomPropertyID
+	})
+	return market
+}
+
+// summarizeListings computes per-unit statistics over listings
+func summarizeListings(auctions []store.AuctionItem) ItemMarket {
+	var market ItemMarket
+	var buyouts []store.UnitListing
+	for _, auction := range auctions {
+		market.Listings++
+		market.Quantity += auction.Count
+		if auction.BuyoutPrice > 0 && auction.Count > 0 {
+			buyouts = append(buyouts, store.UnitListing{
+				Count:   auction.Count,
+				Buyout:  auction.BuyoutPrice,
+				PerUnit: auction.UnitBuyout,
+			})
+			market.MaxBuyout = max(market.MaxBuyout, auction.UnitBuyout)
+		}
+	}
+
+	summary := store.SummarizeListings(buyouts)
+	market.BuyoutListings = summary.Listings
+	market.MinBuyout = summary.MinBuyout
+	market.MedianBuyout = summary.MedianBuyout
+	market.MeanBuyout = summary.MeanBuyout
+	return market
+}
+
+func (s *Server) handleGetItemHistory(w http.ResponseWriter, r *http.Request, rm *realm) {
+	if rm.History == nil {
+		writeError(w, r, http.StatusServiceUnavailable, codeUnavailable, "Price history is not available")
+		return
+	}
+	entry, ok := itemEntry(w, r)
+	if !ok {
+		return
+	}
+
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	if days < 1 {
+		days = 7
+	}
+	if days > 365 {
+		days = 365
+	}
+	since := time.Now().UTC().AddDate(0, 0, -days)
+
+	history, err := rm.History.ItemHistory(r.Context(), entry, since)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"entry":   entry,
+		"days":    days,
+		"history": history,
+	})
+}
+
+// Item detail page, sharing the look of indexTemplate
+const itemTemplate = `<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.T.item}} {{.Entry}} - {{.T.title}}</title>
+    <style>
+        * {
+            margin: 0;
+            padding: 0;
+            box-sizing: border-box;
+        }
+
+        body {
+            font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+            background: linear-gradient(135deg, #1e3c72 0%, #2a5298 100%);
+            color: #333;
+            min-height: 100vh;
+        }
+
+        .container {
+            max-width: 1400px;
+            margin: 0 auto;
+            padding: 20px;
+        }
+
+        .header {
+            text-align: center;
+            margin-bottom: 30px;
+            color: white;
+        }
+
+        .header h1 {
+            font-size: 2.5rem;
+            margin-bottom: 10px;
+            text-shadow: 2px 2px 4px rgba(0,0,0,0.5);
+        }
+
+        .header a {
+            color: white;
+            opacity: 0.9;
+        }
+
+        .stats-grid {
+            display: grid;
+            grid-template-columns: repeat(auto-fit, minmax(180px, 1fr));
+            gap: 20px;
+            margin-bottom: 30px;
+        }
+
+        .stat-card {
+            background: rgba(255, 255, 255, 0.95);
+            padding: 20px;
+            border-radius: 10px;
+            text-align: center;
+            box-shadow: 0 4px 6px rgba(0,0,0,0.1);
+        }
+
+        .stat-number {
+            font-size: 1.5rem;
+            font-weight: bold;
+            color: #2a5298;
+            margin-bottom: 5px;
+        }
+
+        .stat-label {
+            color: #666;
+            font-size: 0.9rem;
+        }
+
+        .auctions-table {
+            background: rgba(255, 255, 255, 0.95);
+            border-radius: 10px;
+            overflow: hidden;
+            box-shadow: 0 4px 6px rgba(0,0,0,0.1);
+            margin-bottom: 20px;
+        }
+
+        .table-header {
+            background: #2a5298;
+            color: white;
+            padding: 15px 20px;
+            font-weight: bold;
+        }
+
+        .table-container {
+            overflow-x: auto;
+        }
+
+        table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+
+        th, td {
+            padding: 12px 15px;
+            text-align: left;
+            border-bottom: 1px solid #ddd;
+        }
+
+        th {
+            background: #f8f9fa;
+            font-weight: 600;
+        }
+
+        tr:hover {
+            background: #f5f5f5;
+        }
+
+        .quality-0 { color: #9d9d9d; }
+        .quality-1 { color: #ffffff; text-shadow: 1px 1px 2px rgba(0,0,0,0.8); }
+        .quality-2 { color: #1eff00; text-shadow: 1px 1px 2px rgba(0,0,0,0.5); }
+        .quality-3 { color: #0070dd; }
+        .quality-4 { color: #a335ee; }
+        .quality-5 { color: #ff8000; }
+
+        .price {
+            font-weight: bold;
+            color: #2a5298;
+        }
+
+        .loading {
+            text-align: center;
+            padding: 40px;
+            color: #666;
+        }
+
+        .error {
+            background: #ffebee;
+            color: #c62828;
+            padding: 15px;
+            border-radius: 5px;
+            margin: 10px 0;
+        }
+
+        .chart {
+            padding: 20px;
+        }
+
+        .chart svg {
+            width: 100%;
+            height: 200px;
+        }
+
+        .warning {
+            background: #fff8e1;
+            color: #8d6e00;
+            padding: 15px;
+            border-radius: 5px;
+            margin: 10px 0;
+        }
+
+        tr.overpriced {
+            background: #fff8e1;
+        }
+
+        .source-kind {
+            color: #666;
+            font-size: 0.8rem;
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1 id="itemName">{{.T.loading}}</h1>
+            <p>
+                <a href="/">&larr; {{.T.backToAuctions}}</a> &middot;
+                <a href="https://www.wowhead.com/wotlk/item={{.Entry}}" target="_blank">{{.T.viewOnWowhead}}</a>
+            </p>
+        </div>
+
+        <div class="stats-grid">
+            <div class="stat-card">
+                <div class="stat-number" id="minBuyout">-</div>
+                <div class="stat-label">{{.T.lowestPerUnit}}</div>
+            </div>
+            <div class="stat-card">
+                <div class="stat-number" id="medianBuyout">-</div>
+                <div class="stat-label">{{.T.medianPerUnit}}</div>
+            </div>
+            <div class="stat-card">
+                <div class="stat-number" id="maxBuyout">-</div>
+                <div class="stat-label">{{.T.highestPerUnit}}</div>
+            </div>
+            <div class="stat-card">
+                <div class="stat-number" id="quantity">-</div>
+                <div class="stat-label">{{.T.quantityListed}}</div>
+            </div>
+            <div class="stat-card">
+                <div class="stat-number" id="vendorSell">-</div>
+                <div class="stat-label">{{.T.vendorSellsFor}}</div>
+            </div>
+            <div class="stat-card">
+                <div class="stat-number" id="vendorBuy">-</div>
+                <div class="stat-label">{{.T.vendorBuyPrice}}</div>
+            </div>
+            <div class="stat-card">
+                <div class="stat-number" id="auctionatorPrice">-</div>
+                <div class="stat-label">{{.T.auctionatorPrice}}</div>
+            </div>
+        </div>
+
+        <div class="auctions-table">
+            <div class="table-header">
+                <h2>{{.T.priceHistory}}</h2>
+            </div>
+            <div class="chart" id="historyChart">
+                <div class="loading">{{.T.loadingHistory}}</div>
+            </div>
+        </div>
+
+        <div class="auctions-table" id="suffixesPanel" style="display: none;">
+            <div class="table-header">
+                <h2>{{.T.pricesBySuffix}}</h2>
+            </div>
+            <div class="table-container">
+                <table>
+                    <thead>
+                        <tr>
+                            <th>{{.T.suffix}}</th>
+                            <th>{{.T.listings}}</th>
+                            <th>{{.T.quantityListed}}</th>
+                            <th>{{.T.lowestPerUnit}}</th>
+                            <th>{{.T.medianPerUnit}}</th>
+                        </tr>
+                    </thead>
+                    <tbody id="suffixesBody"></tbody>
+                </table>
+            </div>
+        </div>
+
+        <div class="auctions-table" id="sourcesPanel" style="display: none;">
+            <div class="table-header">
+                <h2>{{.T.itemSources}}</h2>
+            </div>
+            <div class="chart">
+                <div class="warning" id="overpricedNote" style="display: none;"></div>
+                <div class="table-container" id="sourcesBody"></div>
+            </div>
+        </div>
+
+        <div class="auctions-table">
+            <div class="table-header">
+                <h2>{{.T.currentListings}}</h2>
+            </div>
+            <div class="table-container">
+                <table>
+                    <thead>
+                        <tr>
+                            <th>{{.T.suffix}}</th>
+                            <th>{{.T.count}}</th>
+                            <th>{{.T.seller}}</th>
+                            <th>{{.T.currentBid}}</th>
+                            <th>{{.T.buyout}}</th>
+                            <th>{{.T.perUnit}}</th>
+                            <th>{{.T.timeLeft}}</th>
+                        </tr>
+                    </thead>
+                    <tbody id="auctionsBody">
+                        <tr>
+                            <td colspan="7" class="loading">{{.T.loadingListings}}</td>
+                        </tr>
+                    </tbody>
+                </table>
+            </div>
+        </div>
+    </div>
+
+    <script>
+        const T = {{.T}};
+        const itemEntry = {{.Entry}};
+        const realm = new URLSearchParams(location.search).get('realm');
+        const apiBase = realm ? '/api/realms/' + encodeURIComponent(realm) : '/api';
+
+        document.addEventListener('DOMContentLoaded', function() {
+            loadItem();
+            loadHistory();
+        });
+
+        async function loadItem() {
+            try {
+                const response = await fetch(apiBase + '/items/' + itemEntry);
+                if (!response.ok) {
+                    throw new Error((await response.json()).error.message);
+                }
+                const data = await response.json();
+                displayItem(data.item, data.market);
+                displaySuffixes(data.market.suffixes || []);
+                displayAuctionatorPrice(data.auctions);
+                displayAuctions(data.auctions);
+                loadSources();
+            } catch (error) {
+                console.error('Error loading item:', error);
+                document.getElementById('itemName').textContent = T.itemNotFound;
+                document.getElementById('auctionsBody').innerHTML =
+                    '<tr><td colspan="7" class="error">' + T.errorItem + '</td></tr>';
+            }
+        }
+
+        function displayItem(item, market) {
+            const name = document.getElementById('itemName');
+            name.textContent = item.name;
+            name.className = 'quality-' + item.quality;
+            document.title = item.name + ' - WoW Auction House Viewer';
+
+            document.getElementById('minBuyout').textContent = formatGold(market.min_buyout);
+            document.getElementById('medianBuyout').textContent = formatGold(market.median_buyout);
+            document.getElementById('maxBuyout').textContent = formatGold(market.max_buyout);
+            document.getElementById('quantity').textContent = market.quantity.toLocaleString();
+            document.getElementById('vendorSell').textContent = formatGold(item.sell_price);
+            document.getElementById('vendorBuy').textContent = formatGold(item.buy_price);
+        }
+
+        // Random properties and suffixes sell for different prices, so the
+        // listings of each are summarized apart
+        function displaySuffixes(suffixes) {
+            if (suffixes.length === 0) return;
+            document.getElementById('suffixesBody').innerHTML = suffixes.map(function(s) {
+                return '<tr>' +
+                    '<td>' + (s.suffix || '#' + s.random_property_id) + '</td>' +
+                    '<td>' + s.listings + '</td>' +
+                    '<td>' + s.quantity + '</td>' +
+                    '<td class="price">' + formatGold(s.min_buyout) + '</td>' +
+                    '<td class="price">' + formatGold(s.median_buyout) + '</td>' +
+                    '</tr>';
+            }).join('');
+            document.getElementById('suffixesPanel').style.display = '';
+        }
+
+        // Every listing carries mod_auctionator's market price of the item,
+        // when it has one
+        function displayAuctionatorPrice(auctions) {
+            const listing = auctions.find(a => a.auctionator_price > 0);
+            if (!listing) return;
+            const element = document.getElementById('auctionatorPrice');
+            element.textContent = formatGold(listing.auctionator_price);
+            element.title = T.scanned + ': ' + new Date(listing.auctionator_scanned_at).toLocaleString();
+        }
+
+        function displayAuctions(auctions) {
+            const tbody = document.getElementById('auctionsBody');
+
+            if (auctions.length === 0) {
+                tbody.innerHTML = '<tr><td colspan="7" class="loading">' + T.noListings + '</td></tr>';
+                return;
+            }
+
+            auctions.sort((a, b) => (a.unit_buyout || Infinity) - (b.unit_buyout || Infinity));
+            tbody.innerHTML = auctions.map(function(auction) {
+                return '<tr data-id="' + auction.id + '">' +
+                    '<td>' + (auction.suffix || '') + '</td>' +
+                    '<td>' + auction.count + '</td>' +
+                    '<td>' + auction.owner_name + '</td>' +
+                    '<td class="price">' + formatGold(auction.last_bid || auction.start_bid) + '</td>' +
+                    '<td class="price">' + (auction.buyout_price > 0 ? formatGold(auction.buyout_price) : T.noBuyout) + '</td>' +
+                    '<td class="price">' + (auction.unit_buyout > 0 ? formatGold(auction.unit_buyout) : '-') + '</td>' +
+                    '<td>' + auction.time_left + '</td>' +
+                    '</tr>';
+            }).join('');
+        }
+
+        // Sources are only served when the world database is configured for
+        // them; without them the panel stays hidden
+        async function loadSources() {
+            const panel = document.getElementById('sourcesPanel');
+            try {
+                const response = await fetch(apiBase + '/items/' + itemEntry + '/sources');
+                if (!response.ok) return;
+                const data = await response.json();
+                panel.style.display = '';
+                displaySources(data.sources);
+                flagOverpriced(data.overpriced, data.markup);
+            } catch (error) {
+                console.error('Error loading sources:', error);
+                panel.style.display = '';
+                document.getElementById('sourcesBody').innerHTML = '<div class="error">' + T.errorSources + '</div>';
+            }
+        }
+
+        function displaySources(sources) {
+            const wowhead = (kind, id, name) =>
+                '<a href="https://www.wowhead.com/wotlk/' + kind + '=' + id + '" target="_blank">' + escapeHTML(name) + '</a>';
+            let html = '';
+            if (sources.drops.length > 0) {
+                html += sourceTable([T.droppedBy, T.dropChance, T.averageCount], sources.drops.map(function(d) {
+                    const object = d.kind === 'gameobject';
+                    return '<tr>' +
+                        '<td>' + wowhead(object ? 'object' : 'npc', d.entry, d.name) +
+                        ' <span class="source-kind">' + (object ? T.kindGameObject : T.kindCreature) + '</span></td>' +
+                        '<td>' + formatPercent(d.chance) + '</td>' +
+                        '<td>' + d.expected.toFixed(2) + '</td>' +
+                        '</tr>';
+                }));
+            }
+            if (sources.vendors.length > 0) {
+                html += sourceTable([T.soldBy, T.price, T.stock], sources.vendors.map(function(v) {
+                    const cost = [];
+                    if (v.price > 0 || !v.extended_cost) cost.push(formatGold(v.price) + (v.buy_count > 1 ? ' / ' + v.buy_count : ''));
+                    if (v.extended_cost) {
+                        if (v.extended_cost.honor_points) cost.push(v.extended_cost.honor_points + ' ' + T.honorPoints);
+                        if (v.extended_cost.arena_points) cost.push(v.extended_cost.arena_points + ' ' + T.arenaPoints);
+                        (v.extended_cost.items || []).forEach(function(item) {
+                            cost.push(item.count + '&times; <a href="/items/' + item.entry + '">' + escapeHTML(item.name || '#' + item.entry) + '</a>');
+                        });
+                    }
+                    return '<tr>' +
+                        '<td>' + wowhead('npc', v.entry, v.name) + '</td>' +
+                        '<td class="price">' + cost.join(' + ') + '</td>' +
+                        '<td>' + (v.max_count ? v.max_count : T.unlimited) + '</td>' +
+                        '</tr>';
+                }));
+            }
+            if (sources.quests.length > 0) {
+                html += sourceTable([T.quest, T.level, T.count], sources.quests.map(function(q) {
+                    return '<tr>' +
+                        '<td>' + wowhead('quest', q.id, q.title) +
+                        (q.choice ? ' <span class="source-kind">' + T.rewardChoice + '</span>' : '') + '</td>' +
+                        '<td>' + (q.level > 0 ? q.level : q.min_level) + '</td>' +
+                        '<td>' + q.count + '</td>' +
+                        '</tr>';
+                }));
+            }
+            document.getElementById('sourcesBody').innerHTML = html || '<div class="loading">' + T.noSources + '</div>';
+        }
+
+        function sourceTable(headings, rows) {
+            return '<table><thead><tr>' + headings.map(h => '<th>' + h + '</th>').join('') + '</tr></thead>' +
+                '<tbody>' + rows.join('') + '</tbody></table>';
+        }
+
+        // Listings of an item vendors sell for gold are flagged when they
+        // cost far more than buying it from the vendor
+        function flagOverpriced(listings, markup) {
+            if (listings.length === 0) return;
+            const note = document.getElementById('overpricedNote');
+            note.textContent = fill(T.overpricedNote, {n: listings.length, percent: markup});
+            note.style.display = '';
+            listings.forEach(function(listing) {
+                const row = document.querySelector('#auctionsBody tr[data-id="' + listing.id + '"]');
+                if (!row) return;
+                row.classList.add('overpriced');
+                row.title = T.overpriced + ': ' + listing.percent_of_vendor + '%';
+            });
+        }
+
+        async function loadHistory() {
+            const chart = document.getElementById('historyChart');
+            try {
+                const response = await fetch(apiBase + '/items/' + itemEntry + '/history?days=7');
+                if (!response.ok) {
+                    chart.innerHTML = '<div class="loading">' + T.historyDisabled + '</div>';
+                    return;
+                }
+                const data = await response.json();
+                displayHistory(data.history);
+            } catch (error) {
+                console.error('Error loading history:', error);
+                chart.innerHTML = '<div class="error">' + T.errorHistory + '</div>';
+            }
+        }
+
+        function displayHistory(history) {
+            const chart = document.getElementById('historyChart');
+            if (history.length < 2) {
+                chart.innerHTML = '<div class="loading">' + T.historyTooShort + '</div>';
+                return;
+            }
+
+            const width = 1000, height = 200, pad = 10;
+            const times = history.map(p => new Date(p.time).getTime());
+            const prices = history.map(p => p.median_buyout);
+            const minT = Math.min(...times), maxT = Math.max(...times);
+            const minP = Math.min(...prices), maxP = Math.max(...prices);
+            const x = t => pad + (t - minT) / ((maxT - minT) || 1) * (width - 2 * pad);
+            const y = p => height - pad - (p - minP) / ((maxP - minP) || 1) * (height - 2 * pad);
+            const points = history.map((p, i) => x(times[i]) + ',' + y(prices[i])).join(' ');
+
+            chart.innerHTML =
+                '<svg viewBox="0 0 ' + width + ' ' + height + '" preserveAspectRatio="none">' +
+                '<polyline fill="none" stroke="#2a5298" stroke-width="2" points="' + points + '"/>' +
+                '</svg>' +
+                '<div class="stat-label">' + T.low + ' ' + formatGold(minP) + ' &middot; ' + T.high + ' ' + formatGold(maxP) + '</div>';
+        }
+
+        function formatPercent(percent) {
+            return (percent >= 10 ? percent.toFixed(0) : percent.toFixed(2).replace(/\.?0+$/, '')) + '%';
+        }
+
+        function fill(text, values) {
+            return text.replace(/\{(\w+)\}/g, function(match, name) {
+                return name in values ? values[name] : match;
+            });
+        }
+
+        function escapeHTML(text) {
+            const div = document.createElement('div');
+            div.textContent = text;
+            return div.innerHTML;
+        }
+
+        function formatGold(copper) {
+            if (!copper) return '0c';
+
+            const gold = Math.floor(copper / 10000);
+            const silver = Math.floor((copper % 10000) / 100);
+            const copperRemainder = copper % 100;
+
+            let result = '';
+            if (gold > 0) result += gold + 'g ';
+            if (silver > 0) result += silver + 's ';
+            if (copperRemainder > 0 || result === '') result += copperRemainder + 'c';
+
+            return result.trim();
+        }
+    </script>
+</body>
+</html>`
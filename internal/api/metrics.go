@@ -0,0 +1,103 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/metrics"
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// economyCacheTTL limits how often scrapes query the auction house
+const economyCacheTTL = 30 * time.Second
+
+// economyCache holds the last economy query, so frequent scrapes stay cheap
+type economyCache struct {
+	sync.Mutex
+	at    time.Time
+	stats []store.EconomyStats
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	metrics.HTTPRequests.Write(w)
+	metrics.HTTPDuration.Write(w)
+	metrics.QueryErrors.Write(w)
+	if s.dbStats != nil {
+		metrics.WriteDBStats(w, s.dbStats())
+	}
+	if s.items != nil {
+		writeItemCache(w, s.items.Status())
+	}
+	s.writeStreams(w)
+	s.writeEconomy(w, r)
+}
+
+// writeStreams reports the clients streaming auction changes, by realm
+func (s *Server) writeStreams(w io.Writer) {
+	fmt.Fprintf(w, "# HELP ah_stream_subscribers Clients connected to the auction stream.\n# TYPE ah_stream_subscribers gauge\n")
+	for _, rm := range s.realms {
+		fmt.Fprintf(w, "ah_stream_subscribers{realm=\"%d\"} %d\n", rm.ID, rm.feed.subscriberCount())
+	}
+}
+
+// writeItemCache reports the size and age of the item template cache
+func writeItemCache(w io.Writer, status store.ItemCacheStatus) {
+	fmt.Fprintf(w, "# HELP ah_item_cache_templates Item templates held in memory.\n# TYPE ah_item_cache_templates gauge\n")
+	fmt.Fprintf(w, "ah_item_cache_templates %d\n", status.Items)
+	if !status.LoadedAt.IsZero() {
+		fmt.Fprintf(w, "# HELP ah_item_cache_loaded_timestamp_seconds Time of the last item template reload.\n# TYPE ah_item_cache_loaded_timestamp_seconds gauge\n")
+		fmt.Fprintf(w, "ah_item_cache_loaded_timestamp_seconds %d\n", status.LoadedAt.Unix())
+	}
+}
+
+// writeEconomy reports live auction house gauges by realm, house and quality
+func (s *Server) writeEconomy(w io.Writer, r *http.Request) {
+	stats := make([][]store.EconomyStats, len(s.realms))
+	for i, rm := range s.realms {
+		stats[i] = rm.economy.get(r, rm.Auctions)
+	}
+
+	gauges := []struct {
+		name  string
+		help  string
+		value func(store.EconomyStats) int
+	}{
+		{"ah_auction_listings", "Live auctions.", func(e store.EconomyStats) int { return e.Listings }},
+		{"ah_auction_buyout_value_copper", "Sum of buyout prices of live auctions, in copper.", func(e store.EconomyStats) int { return e.BuyoutValue }},
+		{"ah_auction_active_bids", "Live auctions that have received a bid.", func(e store.EconomyStats) int { return e.ActiveBids }},
+		{"ah_auction_unique_sellers", "Distinct characters with live auctions.", func(e store.EconomyStats) int { return e.UniqueSellers }},
+	}
+	for _, g := range gauges {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", g.name, g.help, g.name)
+		for i, rm := range s.realms {
+			for _, e := range stats[i] {
+				fmt.Fprintf(w, "%s{realm=\"%d\",house=\"%d\",quality=\"%d\"} %d\n", g.name, rm.ID, e.House, e.Quality, g.value(e))
+			}
+		}
+	}
+}
+
+// get returns the cached stats, querying auctions again once they are older
+// than economyCacheTTL. Failures are logged and leave the old stats in place.
+func (c *economyCache) get(r *http.Request, auctions store.AuctionRepository) []store.EconomyStats {
+	c.Lock()
+	defer c.Unlock()
+
+	if time.Since(c.at) > economyCacheTTL {
+		stats, err := auctions.Economy(r.Context())
+		if err != nil {
+			metrics.QueryErrors.Inc(r.Pattern)
+			log.Printf("[%s] Error querying economy metrics: %v", requestID(r), err)
+		} else {
+			c.stats = stats
+			c.at = time.Now()
+		}
+	}
+	return c.stats
+}
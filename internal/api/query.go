@@ -0,0 +1,153 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// parseAuctionQuery reads filters, sorting and pagination from query
+// parameters. Errors name the offending parameter and are safe to show to
+// clients.
+func parseAuctionQuery(values url.Values) (store.AuctionQuery, error) {
+	q := store.AuctionQuery{Sort: "time", Page: 1, Limit: defaultPageSize}
+	p := queryParser{values: values}
+	f := &q.Filter
+
+	f.Search = strings.TrimSpace(values.Get("q"))
+	f.Seller = strings.TrimSpace(values.Get("seller"))
+	f.Suffix = strings.TrimSpace(values.Get("suffix"))
+	f.ItemEntry = p.int("entry")
+	f.Class = p.optionalInt("class")
+	f.Subclass = p.optionalInt("subclass")
+	f.InventoryType = p.optionalInt("inventory_type")
+	f.Quality = p.intRange("quality")
+	f.ItemLevel = p.intRange("item_level")
+	f.RequiredLevel = p.intRange("required_level")
+	f.Count = p.intRange("count")
+	f.Buyout = p.intRange("buyout")
+	f.UnitBuyout = p.intRange("unit_buyout")
+	f.Bid = p.intRange("bid")
+	f.HasBid = p.optionalBool("has_bid")
+
+	if value := values.Get("time_left"); value != "" {
+		for _, bucket := range strings.Split(value, ",") {
+			bucket = strings.TrimSpace(bucket)
+			if !store.ValidTimeLeft(bucket) {
+				p.fail("time_left")
+				break
+			}
+			f.TimeLeft = append(f.TimeLeft, bucket)
+		}
+	}
+
+	if page := p.int("page"); page > 1 {
+		q.Page = page
+	}
+	if limit := p.int("limit"); limit > 0 {
+		q.Limit = min(limit, maxPageSize)
+	}
+
+	if sort := values.Get("sort"); sort != "" {
+		if !store.ValidSort(sort) {
+			p.fail("sort")
+		}
+		q.Sort = sort
+	}
+	switch values.Get("order") {
+	case "", "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		p.fail("order")
+	}
+
+	if p.err != nil {
+		return q, p.err
+	}
+
+	if value := values.Get("cursor"); value != "" {
+		cursor, err := store.DecodeCursor(value)
+		if err != nil {
+			return q, err
+		}
+		if cursor.Sort != q.Sort || cursor.Desc != q.Desc {
+			return q, errors.New("cursor does not match sort and order")
+		}
+		q.Cursor = cursor
+	}
+	return q, nil
+}
+
+// queryParser reads typed query parameters, remembering the first invalid one
+type queryParser struct {
+	values url.Values
+	err    error
+}
+
+func (p *queryParser) fail(name string) {
+	if p.err == nil {
+		p.err = fmt.Errorf("invalid value for %s", name)
+	}
+}
+
+func (p *queryParser) optionalInt(name string) *int {
+	value := p.values.Get(name)
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		p.fail(name)
+		return nil
+	}
+	return &n
+}
+
+func (p *queryParser) int(name string) int {
+	if n := p.optionalInt(name); n != nil {
+		return *n
+	}
+	return 0
+}
+
+// intRange reads name_min and name_max
+func (p *queryParser) intRange(name string) store.IntRange {
+	r := store.IntRange{
+		Min: p.optionalInt(name + "_min"),
+		Max: p.optionalInt(name + "_max"),
+	}
+	if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+		p.fail(name + "_min")
+	}
+	return r
+}
+
+func (p *queryParser) optionalBool(name string) *bool {
+	value := p.values.Get(name)
+	if value == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		p.fail(name)
+		return nil
+	}
+	return &b
+}
+
+func orderName(desc bool) string {
+	if desc {
+		return "desc"
+	}
+	return "asc"
+}
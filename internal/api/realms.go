@@ -0,0 +1,125 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// Realm is one game realm and the repositories holding its data
+type Realm struct {
+	ID   int
+	Name string
+
+	Auctions store.AuctionRepository
+	// History and Alerts are nil when the application database is
+	// unavailable, which disables the endpoints that need them
+	History store.HistoryRepository
+	Alerts  store.AlertRepository
+}
+
+// realm is a Realm being served
+type realm struct {
+	Realm
+	economy    economyCache
+	references referenceCache
+	feed       auctionFeed
+}
+
+// realmHandler handles a request for one realm
+type realmHandler func(w http.ResponseWriter, r *http.Request, rm *realm)
+
+// handleRealm registers a realm's API route under /api/realms/{realm}, and
+// at its plain /api path for the default realm
+func (s *Server) handleRealm(pattern string, handler realmHandler) {
+	serve := func(w http.ResponseWriter, r *http.Request) {
+		rm := s.defaultRealm()
+		if value := r.PathValue("realm"); value != "" {
+			if rm = s.findRealm(value); rm == nil {
+				writeError(w, r, http.StatusNotFound, codeNotFound, "Realm not found")
+				return
+			}
+		}
+		handler(w, r, rm)
+	}
+
+	method, path, _ := strings.Cut(pattern, " ")
+	s.mux.HandleFunc(pattern, serve)
+	s.mux.HandleFunc(method+" /api/realms/{realm}"+strings.TrimPrefix(path, "/api"), serve)
+}
+
+func (s *Server) defaultRealm() *realm {
+	return s.realms[0]
+}
+
+// findRealm returns the realm with the given ID, or nil. Names are accepted
+// too, ignoring case, for the convenience of Discord users.
+func (s *Server) findRealm(value string) *realm {
+	id, err := strconv.Atoi(value)
+	for _, rm := range s.realms {
+		if (err == nil && rm.ID == id) || (err != nil && strings.EqualFold(rm.Name, value)) {
+			return rm
+		}
+	}
+	return nil
+}
+
+// realmInfo describes a realm to clients
+type realmInfo struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Default bool   `json:"default"`
+}
+
+func (s *Server) handleGetRealms(w http.ResponseWriter, r *http.Request) {
+	realms := make([]realmInfo, len(s.realms))
+	for i, rm := range s.realms {
+		realms[i] = realmInfo{ID: rm.ID, Name: rm.Name, Default: i == 0}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"realms": realms,
+	})
+}
+
+// handleGetRealmStats reports the statistics of every realm and their sum.
+// Characters belong to one realm, so unique sellers add up exactly, but an
+// item listed on two realms counts twice towards the total unique items.
+func (s *Server) handleGetRealmStats(w http.ResponseWriter, r *http.Request) {
+	house, err := parseHouse(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid house")
+		return
+	}
+
+	type realmStats struct {
+		realmInfo
+		Stats store.AuctionHouseStats `json:"stats"`
+	}
+	realms := make([]realmStats, len(s.realms))
+	var total store.AuctionHouseStats
+	for i, rm := range s.realms {
+		stats, err := rm.Auctions.Stats(r.Context(), house)
+		if err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
+		realms[i] = realmStats{realmInfo{ID: rm.ID, Name: rm.Name, Default: i == 0}, stats}
+
+		total.TotalItems += stats.TotalItems
+		total.TotalValue += stats.TotalValue
+		total.ActiveBids += stats.ActiveBids
+		total.UniqueOwners += stats.UniqueOwners
+		total.UniqueItems += stats.UniqueItems
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"realms": realms,
+		"total":  total,
+	})
+}
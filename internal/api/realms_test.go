@@ -0,0 +1,122 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// newTwoRealmServer serves the test store as realm 1 and a second realm
+// with a single auction as realm 2
+func newTwoRealmServer(t *testing.T) *Server {
+	t.Helper()
+	first := newTestStore()
+
+	second := store.NewMemory()
+	second.Now = func() time.Time { return now }
+	second.AddItem(store.ItemTemplate{Entry: 2589, Name: "Linen Cloth", Quality: 1})
+	second.AddAuction(store.AuctionItem{ID: 1, HouseID: wow.HouseHorde, ItemOwner: 10, OwnerName: "Dave", ItemEntry: 2589, Count: 5, BuyoutPrice: 400, StartBid: 200, Time: int(now.Add(time.Hour).Unix())})
+
+	return newTestServer(t, Config{Realms: []Realm{
+		{ID: 1, Name: "Azeroth", Auctions: first, Alerts: first},
+		{ID: 2, Name: "Outland", Auctions: second, Alerts: second},
+	}, AdminToken: "s3cret"})
+}
+
+func TestNewValidatesRealms(t *testing.T) {
+	if _, err := New(Config{}); err == nil {
+		t.Error("New() accepted a config without realms")
+	}
+	m := newTestStore()
+	if _, err := New(Config{Realms: []Realm{{ID: 1, Auctions: m}, {ID: 1, Auctions: m}}}); err == nil {
+		t.Error("New() accepted a repeated realm ID")
+	}
+}
+
+func TestGetRealms(t *testing.T) {
+	s := newTwoRealmServer(t)
+
+	var resp struct {
+		Realms []realmInfo `json:"realms"`
+	}
+	get(t, s, "/api/realms", http.StatusOK, &resp)
+	want := []realmInfo{{ID: 1, Name: "Azeroth", Default: true}, {ID: 2, Name: "Outland"}}
+	if len(resp.Realms) != 2 || resp.Realms[0] != want[0] || resp.Realms[1] != want[1] {
+		t.Errorf("realms = %+v, want %+v", resp.Realms, want)
+	}
+}
+
+func TestRealmRoutes(t *testing.T) {
+	s := newTwoRealmServer(t)
+
+	var resp auctionsResponse
+	get(t, s, "/api/realms/2/auctions", http.StatusOK, &resp)
+	if resp.Total != 1 || resp.Auctions[0].OwnerName != "Dave" {
+		t.Errorf("realm 2 auctions = %+v", resp.Auctions)
+	}
+	get(t, s, "/api/realms/outland/search?q=dave", http.StatusOK, &resp)
+	if resp.Total != 1 {
+		t.Errorf("realm 2 by name: total = %d, want 1", resp.Total)
+	}
+	get(t, s, "/api/auctions", http.StatusOK, &resp)
+	if resp.Total != 5 {
+		t.Errorf("default realm: total = %d, want 5", resp.Total)
+	}
+	get(t, s, "/api/realms/1/auctions", http.StatusOK, &resp)
+	if resp.Total != 5 {
+		t.Errorf("realm 1: total = %d, want 5", resp.Total)
+	}
+
+	var errResp errorResponse
+	get(t, s, "/api/realms/9/stats", http.StatusNotFound, &errResp)
+	if errResp.Error.Message != "Realm not found" {
+		t.Errorf("unknown realm error = %+v", errResp.Error)
+	}
+}
+
+func TestAlertsArePerRealm(t *testing.T) {
+	s := newTwoRealmServer(t)
+
+	sendAs(t, s, withAdminToken, http.MethodPost, "/api/realms/2/alerts",
+		`{"item_entry":2589,"max_unit_buyout":100,"webhook_url":"https://discord.com/api/webhooks/1/x"}`,
+		http.StatusCreated, nil)
+
+	var list struct {
+		Alerts []store.AlertRule `json:"alerts"`
+	}
+	sendAs(t, s, withAdminToken, http.MethodGet, "/api/realms/2/alerts", "", http.StatusOK, &list)
+	if len(list.Alerts) != 1 {
+		t.Errorf("realm 2 alerts = %+v, want 1", list.Alerts)
+	}
+	sendAs(t, s, withAdminToken, http.MethodGet, "/api/alerts", "", http.StatusOK, &list)
+	if len(list.Alerts) != 0 {
+		t.Errorf("realm 1 alerts = %+v, want none", list.Alerts)
+	}
+}
+
+func TestGetRealmStats(t *testing.T) {
+	s := newTwoRealmServer(t)
+
+	var resp struct {
+		Realms []struct {
+			ID    int                     `json:"id"`
+			Stats store.AuctionHouseStats `json:"stats"`
+		} `json:"realms"`
+		Total store.AuctionHouseStats `json:"total"`
+	}
+	get(t, s, "/api/realms/stats", http.StatusOK, &resp)
+	if len(resp.Realms) != 2 || resp.Realms[0].Stats.TotalItems != 5 || resp.Realms[1].Stats.TotalItems != 1 {
+		t.Errorf("realms = %+v", resp.Realms)
+	}
+	if resp.Total.TotalItems != 6 || resp.Total.TotalValue != 50003990 || resp.Total.UniqueOwners != 4 {
+		t.Errorf("total = %+v", resp.Total)
+	}
+
+	get(t, s, "/api/realms/stats?house=horde", http.StatusOK, &resp)
+	if resp.Total.TotalItems != 3 {
+		t.Errorf("horde total items = %d, want 3", resp.Total.TotalItems)
+	}
+}
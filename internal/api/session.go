@@ -0,0 +1,117 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// sessionCookie holds the signed session of a signed-in account
+	sessionCookie = "ah_session"
+	// defaultSessionTTL is how long a sign-in lasts unless configured
+	// otherwise
+	defaultSessionTTL = 24 * time.Hour
+	// defaultAdminGMLevel is the gmlevel from which accounts may use the
+	// admin features unless configured otherwise: game masters
+	defaultAdminGMLevel = 2
+)
+
+// session is the account a session cookie was issued to. The cookie is
+// signed rather than stored, so the account's gmlevel is as of sign-in.
+type session struct {
+	AccountID int    `json:"id"`
+	Username  string `json:"name"`
+	GMLevel   int    `json:"gm"`
+	Expires   int64  `json:"exp"`
+}
+
+// encodeSession signs a session for its cookie
+func (s *Server) encodeSession(sess session) string {
+	payload, _ := json.Marshal(sess)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(encoded))
+}
+
+// decodeSession verifies a session cookie, rejecting forged and expired
+// ones
+func (s *Server) decodeSession(value string, now time.Time) (session, bool) {
+	var sess session
+	encoded, signature, ok := strings.Cut(value, ".")
+	if !ok {
+		return sess, false
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.sign(encoded)) {
+		return sess, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || json.Unmarshal(payload, &sess) != nil {
+		return sess, false
+	}
+	return sess, now.Unix() < sess.Expires
+}
+
+func (s *Server) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, s.sessionKey)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+// session returns the signed-in account of a request, if any
+func (s *Server) session(r *http.Request) (session, bool) {
+	if s.accounts == nil {
+		return session{}, false
+	}
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return session{}, false
+	}
+	return s.decodeSession(cookie.Value, time.Now())
+}
+
+// isAdmin reports whether a session may use the admin features
+func (s *Server) isAdmin(sess session) bool {
+	return sess.GMLevel >= s.adminGMLevel
+}
+
+// adminSession returns the session of a request signed in with an account
+// that may use the admin features
+func (s *Server) adminSession(r *http.Request) (session, bool) {
+	sess, ok := s.session(r)
+	return sess, ok && s.isAdmin(sess)
+}
+
+// setSessionCookie signs the browser in, or out when value is empty
+func (s *Server) setSessionCookie(w http.ResponseWriter, value string, expires time.Time) {
+	cookie := &http.Cookie{
+		Name:     sessionCookie,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(s.baseURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	}
+	if value == "" {
+		cookie.MaxAge = -1
+	}
+	http.SetCookie(w, cookie)
+}
+
+// sameOrigin reports whether a request comes from this site's own pages.
+// Browsers send Origin with every cross-site request that changes data, so
+// its absence is taken as a request from outside a browser.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
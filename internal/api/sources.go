@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// defaultVendorMarkup is the lowest unit buyout, as a percentage of the
+// vendor's price, that /api/items/{entry}/sources flags by default
+const defaultVendorMarkup = 200
+
+// handleGetItemSources lists the creatures, gameobjects, vendors and quests
+// an item comes from, with the listings of an item vendors sell for gold
+// that cost markup percent of the vendor's price or more
+func (s *Server) handleGetItemSources(w http.ResponseWriter, r *http.Request, rm *realm) {
+	entry, ok := itemEntry(w, r)
+	if !ok {
+		return
+	}
+	p := queryParser{values: r.URL.Query()}
+	markup := defaultVendorMarkup
+	if n := p.optionalInt("markup"); n != nil {
+		if *n < 100 {
+			p.fail("markup")
+		}
+		markup = *n
+	}
+	if p.err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, p.err.Error())
+		return
+	}
+	house, err := parseHouse(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid house")
+		return
+	}
+	locale, err := parseLocale(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid locale")
+		return
+	}
+
+	if _, err := rm.Auctions.Item(r.Context(), entry, locale); errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "Item not found")
+		return
+	} else if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	sources, err := s.sources.ItemSources(r.Context(), entry, locale)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	auctions, err := rm.Auctions.ItemAuctions(r.Context(), entry, house, locale)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	vendorPrice := sources.VendorUnitPrice()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sources":      sources,
+		"vendor_price": vendorPrice,
+		"markup":       markup,
+		"overpriced":   store.FindOverpriced(auctions, vendorPrice, markup),
+		"house":        house,
+		"locale":       locale,
+	})
+}
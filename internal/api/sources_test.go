@@ -0,0 +1,100 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// addSources makes Linen Cloth drop from two creatures and sell for 40
+// copper at a vendor
+func addSources(m *store.Memory) {
+	m.AddItemSources(store.ItemSources{
+		Entry: 2589,
+		Drops: []store.DropSource{
+			{Kind: store.SourceCreature, Entry: 38, Name: "Defias Thug", Chance: 20, Expected: 0.3},
+			{Kind: store.SourceCreature, Entry: 116, Name: "Defias Bandit", Chance: 35.5, Expected: 0.5},
+		},
+		Vendors: []store.VendorSource{{Entry: 1250, Name: "Drake Lindgren", Price: 40, BuyCount: 1}},
+		Quests:  []store.QuestSource{{ID: 33, Title: "Wolves Across the Border", Level: 2, MinLevel: 1, Count: 2}},
+	})
+}
+
+func withSources(cfg *Config, m *store.Memory) {
+	cfg.Sources = m
+}
+
+type sourcesResponse struct {
+	Sources     store.ItemSources         `json:"sources"`
+	VendorPrice int                       `json:"vendor_price"`
+	Markup      int                       `json:"markup"`
+	Overpriced  []store.OverpricedListing `json:"overpriced"`
+}
+
+func overpricedID(l store.OverpricedListing) int {
+	return l.ID
+}
+
+func TestGetItemSources(t *testing.T) {
+	s := newStoreServer(t, addSources, withSources)
+
+	var resp sourcesResponse
+	get(t, s, "/api/items/2589/sources", http.StatusOK, &resp)
+	if d := resp.Sources.Drops; len(d) != 2 || d[0].Entry != 116 || d[1].Entry != 38 {
+		t.Errorf("drops = %+v, want the most likely first", d)
+	}
+	if len(resp.Sources.Vendors) != 1 || len(resp.Sources.Quests) != 1 || resp.Sources.Quests[0].Count != 2 {
+		t.Errorf("sources = %+v, want one vendor and one quest", resp.Sources)
+	}
+	// Every listing costs at least twice the vendor's 40 copper
+	if resp.VendorPrice != 40 || resp.Markup != defaultVendorMarkup {
+		t.Errorf("vendor price = %d, markup = %d", resp.VendorPrice, resp.Markup)
+	}
+	if len(resp.Overpriced) == 0 || resp.Overpriced[0].PercentOfVendor != 375 {
+		t.Errorf("overpriced = %+v, want 375%% of the vendor's price first", resp.Overpriced)
+	}
+
+	if rec := get(t, s, "/items/2589", http.StatusOK, nil); !strings.Contains(rec.Body.String(), `id="sourcesPanel"`) {
+		t.Error("item page has no sources panel")
+	}
+}
+
+func TestItemSourceRequests(t *testing.T) {
+	s := newStoreServer(t, addSources, withSources)
+
+	for _, tt := range []struct {
+		name       string
+		s          *Server
+		target     string
+		want       int
+		overpriced []int
+	}{
+		// The dearest per unit first
+		{"linen cloth", s, "/api/items/2589/sources", http.StatusOK, []int{2, 1, 5}},
+		{"higher markup", s, "/api/items/2589/sources?markup=300", http.StatusOK, []int{2}},
+		{"horde", s, "/api/items/2589/sources?house=horde", http.StatusOK, []int{5}},
+		// Wool Cloth comes from nowhere known, so nothing is flagged
+		{"no sources", s, "/api/items/2592/sources", http.StatusOK, nil},
+		// Without the loot, vendor and quest tables, neither is Linen Cloth
+		{"no source tables", newStoreServer(t, nil, withSources), "/api/items/2589/sources", http.StatusOK, nil},
+		{"unknown item", s, "/api/items/999999/sources", http.StatusNotFound, nil},
+		{"bad item", s, "/api/items/abc/sources", http.StatusBadRequest, nil},
+		{"markup under 100", s, "/api/items/2589/sources?markup=50", http.StatusBadRequest, nil},
+		{"no source repository", newStoreServer(t, addSources, nil), "/api/items/2589/sources", http.StatusNotFound, nil},
+	} {
+		if tt.want != http.StatusOK {
+			get(t, tt.s, tt.target, tt.want, nil)
+			continue
+		}
+		var resp sourcesResponse
+		get(t, tt.s, tt.target, tt.want, &resp)
+		if got := ids(resp.Overpriced, overpricedID); !equalIDs(got, tt.overpriced) {
+			t.Errorf("%s: overpriced IDs = %v, want %v", tt.name, got, tt.overpriced)
+		}
+		if tt.overpriced == nil && resp.VendorPrice != 0 {
+			t.Errorf("%s: vendor price = %d, want none", tt.name, resp.VendorPrice)
+		}
+	}
+}
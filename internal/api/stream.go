@@ -0,0 +1,313 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/metrics"
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// Event types of the auction stream
+const (
+	eventListed    = "listed"
+	eventBid       = "bid"
+	eventSold      = "sold"
+	eventExpired   = "expired"
+	eventCancelled = "cancelled"
+	// eventRemoved is an auction that ended early whose item could not be
+	// found to tell a sale from a cancellation
+	eventRemoved = "removed"
+)
+
+const (
+	// defaultStreamInterval is how often the auctions of a realm are polled
+	// while clients are streaming them, unless configured otherwise
+	defaultStreamInterval = 10 * time.Second
+	// streamHeartbeat is how often an idle stream sends a comment, so
+	// proxies do not time it out
+	streamHeartbeat = 30 * time.Second
+	// streamBuffer is the number of polls a client may fall behind before
+	// it misses events
+	streamBuffer = 16
+)
+
+// auctionEvent is one change to an auction between two polls
+type auctionEvent struct {
+	Type    string            `json:"type"`
+	Auction store.AuctionItem `json:"auction"`
+}
+
+// auctionFeed polls the live auctions of one realm while any client is
+// subscribed and publishes the changes to all of them, so every open page
+// shares one query
+type auctionFeed struct {
+	realm    string
+	auctions store.AuctionRepository
+	interval time.Duration
+	// now returns the current time, which tells expired auctions from
+	// those that ended early
+	now func() time.Time
+
+	mu          sync.Mutex
+	subscribers map[chan []auctionEvent]struct{}
+	// stop ends the running poll, and is nil while there is none
+	stop context.CancelFunc
+}
+
+// subscribe returns a channel receiving the events of each poll that found
+// changes, starting to poll for the first subscriber. Call unsubscribe when
+// done; the poll stops with the last subscriber.
+func (f *auctionFeed) subscribe() (events <-chan []auctionEvent, unsubscribe func()) {
+	ch := make(chan []auctionEvent, streamBuffer)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.subscribers == nil {
+		f.subscribers = make(map[chan []auctionEvent]struct{})
+	}
+	f.subscribers[ch] = struct{}{}
+	if f.stop == nil {
+		ctx, stop := context.WithCancel(context.Background())
+		f.stop = stop
+		go f.poll(ctx)
+	}
+
+	return ch, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.subscribers, ch)
+		if len(f.subscribers) == 0 && f.stop != nil {
+			f.stop()
+			f.stop = nil
+		}
+	}
+}
+
+// subscriberCount returns the number of clients streaming the realm
+func (f *auctionFeed) subscriberCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subscribers)
+}
+
+// poll compares the live auctions every interval until ctx is cancelled.
+// The first successful poll is the baseline that later ones are compared
+// against, so subscribers only hear of changes.
+func (f *auctionFeed) poll(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	var last map[int]store.AuctionItem
+	for {
+		next, err := f.snapshot(ctx)
+		switch {
+		case err != nil:
+			if ctx.Err() == nil {
+				metrics.QueryErrors.Inc("stream")
+				log.Printf("Polling auctions of %s: %v", f.realm, err)
+			}
+		case last == nil:
+			last = next
+		default:
+			events := f.changes(ctx, last, next)
+			last = next
+			f.publish(ctx, events)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// snapshot returns the live auctions by ID
+func (f *auctionFeed) snapshot(ctx context.Context) (map[int]store.AuctionItem, error) {
+	auctions, err := f.auctions.LiveAuctions(ctx, wow.DefaultLocale)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]store.AuctionItem, len(auctions))
+	for _, a := range auctions {
+		byID[a.ID] = a
+	}
+	return byID, nil
+}
+
+// changes returns the events between two polls, looking up where the items
+// of auctions that ended early went
+func (f *auctionFeed) changes(ctx context.Context, last, next map[int]store.AuctionItem) []auctionEvent {
+	events, early := diffAuctions(last, next, f.now())
+	if len(early) > 0 {
+		guids := make([]int, len(early))
+		for i, a := range early {
+			guids[i] = a.ItemGUID
+		}
+		owners, err := f.auctions.ItemOwners(ctx, guids)
+		if err != nil {
+			metrics.QueryErrors.Inc("stream")
+			log.Printf("Looking up ended auctions of %s: %v", f.realm, err)
+		}
+		for _, a := range early {
+			events = append(events, auctionEvent{Type: endedEarly(a, owners), Auction: a})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Auction.ID < events[j].Auction.ID
+	})
+	return events
+}
+
+// diffAuctions compares two polls of the live auctions by ID. New auctions
+// are listed and changed bids or bidders are bids. Auctions that ran out of
+// time expired, or sold to their highest bidder; those that ended before
+// then are returned as early, for endedEarly.
+func diffAuctions(last, next map[int]store.AuctionItem, now time.Time) (events []auctionEvent, early []store.AuctionItem) {
+	for id, a := range next {
+		old, ok := last[id]
+		switch {
+		case !ok:
+			events = append(events, auctionEvent{Type: eventListed, Auction: a})
+		case a.LastBid != old.LastBid || a.BuyGUID != old.BuyGUID:
+			events = append(events, auctionEvent{Type: eventBid, Auction: a})
+		}
+	}
+	for id, a := range last {
+		if _, ok := next[id]; ok {
+			continue
+		}
+		switch {
+		case int64(a.Time) > now.Unix():
+			early = append(early, a)
+		case a.LastBid > 0:
+			events = append(events, auctionEvent{Type: eventSold, Auction: a})
+		default:
+			events = append(events, auctionEvent{Type: eventExpired, Auction: a})
+		}
+	}
+	return events, early
+}
+
+// endedEarly tells whether an auction that ended before running out of time
+// was bought out or cancelled. Either way the game server mails the item,
+// to the buyer or back to the seller, and makes the recipient its owner.
+// owners holds the item owners found, and is nil if they could not be read.
+func endedEarly(a store.AuctionItem, owners map[int]int) string {
+	owner, ok := owners[a.ItemGUID]
+	switch {
+	case !ok:
+		return eventRemoved
+	case owner == a.ItemOwner:
+		return eventCancelled
+	default:
+		return eventSold
+	}
+}
+
+// publish sends events to every subscriber, unless the poll has been
+// stopped. Subscribers that have fallen streamBuffer polls behind miss them
+// rather than hold up the others.
+func (f *auctionFeed) publish(ctx context.Context, events []auctionEvent) {
+	if len(events) == 0 {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
+	for ch := range f.subscribers {
+		select {
+		case ch <- events:
+		default:
+		}
+	}
+}
+
+// handleStream streams auction changes as server-sent events, each named by
+// its type with the auction as JSON data. The house parameter limits the
+// events to one house.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request, rm *realm) {
+	house, err := parseHouse(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid house")
+		return
+	}
+	locale, err := parseLocale(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid locale")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, r, http.StatusInternalServerError, codeInternal, "Streaming unsupported")
+		return
+	}
+
+	events, unsubscribe := rm.feed.subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			io.WriteString(w, ": ping\n\n")
+		case batch := <-events:
+			for _, e := range rm.localizeEvents(r, batch, house, locale) {
+				data, err := json.Marshal(e)
+				if err != nil {
+					log.Printf("[%s] encoding %s event: %v", requestID(r), e.Type, err)
+					continue
+				}
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// localizeEvents returns the events of a batch in one house, or all when
+// house is 0, with their items named in locale. The feed reads auctions in
+// the default locale; if they cannot be renamed, they keep those names.
+func (rm *realm) localizeEvents(r *http.Request, batch []auctionEvent, house int, locale string) []auctionEvent {
+	var events []auctionEvent
+	var auctions []store.AuctionItem
+	for _, e := range batch {
+		if house == 0 || e.Auction.HouseID == house {
+			events = append(events, e)
+			auctions = append(auctions, e.Auction)
+		}
+	}
+	if locale == wow.DefaultLocale || len(events) == 0 {
+		return events
+	}
+	localized, err := rm.Auctions.LocalizeAuctions(r.Context(), auctions, locale)
+	if err != nil {
+		log.Printf("[%s] localizing stream events: %v", requestID(r), err)
+		return events
+	}
+	for i := range events {
+		events[i].Auction = localized[i]
+	}
+	return events
+}
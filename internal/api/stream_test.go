@@ -0,0 +1,153 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+func TestDiffAuctions(t *testing.T) {
+	later := int(now.Add(time.Hour).Unix())
+	past := int(now.Add(-time.Minute).Unix())
+	last := map[int]store.AuctionItem{
+		1: {ID: 1, Time: later},
+		2: {ID: 2, Time: later},
+		3: {ID: 3, Time: later, LastBid: 100, BuyGUID: 20},
+		4: {ID: 4, Time: past},
+		5: {ID: 5, Time: past, LastBid: 100},
+		6: {ID: 6, Time: later, ItemGUID: 60, ItemOwner: 10},
+		7: {ID: 7, Time: later, ItemGUID: 70, ItemOwner: 10},
+		8: {ID: 8, Time: later, ItemGUID: 80, ItemOwner: 10},
+	}
+	next := map[int]store.AuctionItem{
+		1: {ID: 1, Time: later},
+		2: {ID: 2, Time: later, LastBid: 50, BuyGUID: 20},
+		3: {ID: 3, Time: later, LastBid: 100, BuyGUID: 21},
+		9: {ID: 9, Time: later},
+	}
+
+	events, early := diffAuctions(last, next, now)
+	got := make(map[int]string)
+	for _, e := range events {
+		got[e.Auction.ID] = e.Type
+	}
+	owners := map[int]int{60: 10, 70: 11}
+	for _, a := range early {
+		got[a.ID] = endedEarly(a, owners)
+	}
+
+	want := map[int]string{
+		2: eventBid,
+		3: eventBid,
+		4: eventExpired,
+		5: eventSold,
+		6: eventCancelled,
+		7: eventSold,
+		8: eventRemoved,
+		9: eventListed,
+	}
+	if len(got) != len(want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+	for id, typ := range want {
+		if got[id] != typ {
+			t.Errorf("auction %d: event %q, want %q", id, got[id], typ)
+		}
+	}
+}
+
+// streamEvent is an event read from /api/stream
+type streamEvent struct {
+	name  string
+	event auctionEvent
+}
+
+// readEvents reads events from a stream until it has n of them
+func readEvents(t *testing.T, scanner *bufio.Scanner, n int) []streamEvent {
+	t.Helper()
+	var events []streamEvent
+	var name string
+	for len(events) < n && scanner.Scan() {
+		line := scanner.Text()
+		if value, ok := strings.CutPrefix(line, "event: "); ok {
+			name = value
+		}
+		if value, ok := strings.CutPrefix(line, "data: "); ok {
+			e := streamEvent{name: name}
+			if err := json.Unmarshal([]byte(value), &e.event); err != nil {
+				t.Fatalf("decoding event %q: %v", value, err)
+			}
+			events = append(events, e)
+		}
+	}
+	if len(events) < n {
+		t.Fatalf("stream ended after %d events: %v", len(events), scanner.Err())
+	}
+	return events
+}
+
+func TestStream(t *testing.T) {
+	m := newTestStore()
+	m.AddItemName(2589, "deDE", "Leinenstoff")
+	m.AddRandomProperty(store.RandomProperty{ID: -5, Suffix: "of the Monkey"})
+	later := int(now.Add(time.Hour).Unix())
+	m.AddAuction(store.AuctionItem{ID: 20, HouseID: wow.HouseAlliance, ItemGUID: 200, ItemOwner: 10, ItemEntry: 2589, Count: 1, StartBid: 100, Time: later})
+	m.AddAuction(store.AuctionItem{ID: 21, HouseID: wow.HouseAlliance, ItemGUID: 210, ItemOwner: 10, ItemEntry: 2592, Count: 1, BuyoutPrice: 500, Time: later})
+	s := newTestServer(t, Config{Realms: testRealms(Realm{Auctions: m}), StreamInterval: 5 * time.Millisecond})
+	s.defaultRealm().feed.now = m.Now
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/stream?house=alliance&locale=deDE", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /api/stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); resp.StatusCode != http.StatusOK || ct != "text/event-stream" {
+		t.Fatalf("status = %d, content type %q", resp.StatusCode, ct)
+	}
+
+	rec := get(t, s, "/metrics", http.StatusOK, nil)
+	if !strings.Contains(rec.Body.String(), `ah_stream_subscribers{realm="1"} 1`) {
+		t.Error("metrics do not count the subscriber")
+	}
+
+	// Let the first poll take the baseline, then change the auction house
+	time.Sleep(50 * time.Millisecond)
+	m.AddAuction(store.AuctionItem{ID: 22, HouseID: wow.HouseAlliance, ItemGUID: 220, ItemOwner: 11, ItemEntry: 2589, RandomPropertyID: -5, Count: 5, StartBid: 100, Time: later})
+	m.AddAuction(store.AuctionItem{ID: 23, HouseID: wow.HouseHorde, ItemGUID: 230, ItemOwner: 11, ItemEntry: 2589, Count: 5, StartBid: 100, Time: later})
+	m.UpdateAuction(store.AuctionItem{ID: 20, HouseID: wow.HouseAlliance, ItemGUID: 200, ItemOwner: 10, ItemEntry: 2589, Count: 1, StartBid: 100, LastBid: 150, BuyGUID: 11, Time: later})
+	m.RemoveAuction(21, 11)
+
+	got := make(map[int]streamEvent)
+	for _, e := range readEvents(t, bufio.NewScanner(resp.Body), 3) {
+		if e.name != e.event.Type {
+			t.Errorf("event named %q carries type %q", e.name, e.event.Type)
+		}
+		got[e.event.Auction.ID] = e
+	}
+	want := map[int]string{20: eventBid, 21: eventSold, 22: eventListed}
+	for id, typ := range want {
+		if got[id].name != typ {
+			t.Errorf("auction %d: event %q, want %q", id, got[id].name, typ)
+		}
+	}
+	if bid := got[20].event.Auction; bid.LastBid != 150 || bid.ItemName != "Leinenstoff" {
+		t.Errorf("bid event auction = %+v", bid)
+	}
+	// Translated names keep the random suffix
+	if listed := got[22].event.Auction; listed.ItemName != "Leinenstoff of the Monkey" || listed.Suffix != "of the Monkey" {
+		t.Errorf("listed event auction = %+v, want the suffix kept", listed)
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// handleGetItemTooltip describes an item template the way the game's tooltip
+// does. Item templates are shared by every realm.
+func (s *Server) handleGetItemTooltip(w http.ResponseWriter, r *http.Request, rm *realm) {
+	entry, ok := itemEntry(w, r)
+	if !ok {
+		return
+	}
+	locale, err := parseLocale(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid locale")
+		return
+	}
+
+	tooltip, ok := s.itemTooltip(w, r, entry, locale)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tooltip)
+}
+
+// handleGetAuctionTooltip describes the item of a live auction with its
+// random property or suffix and the enchantments, gems and socket bonus the
+// item carries
+func (s *Server) handleGetAuctionTooltip(w http.ResponseWriter, r *http.Request, rm *realm) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid auction id")
+		return
+	}
+	locale, err := parseLocale(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid locale")
+		return
+	}
+
+	instance, err := rm.Auctions.AuctionInstance(r.Context(), id, locale)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "Auction not found")
+		return
+	}
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	tooltip, ok := s.itemTooltip(w, r, instance.ItemEntry, locale)
+	if !ok {
+		return
+	}
+
+	ids := make([]int, len(instance.Enchantments))
+	for i, e := range instance.Enchantments {
+		ids[i] = e.ID
+	}
+	names, err := s.tooltips.EnchantmentNames(r.Context(), locale, ids)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	tooltip.Enchantments = instance.Enchantments
+	for i := range tooltip.Enchantments {
+		tooltip.Enchantments[i].Name = names[tooltip.Enchantments[i].ID]
+	}
+	// The enchantments of a known random property or suffix are listed as
+	// the stats they grant
+	if random := instance.Random; random != nil {
+		if random.Suffix != "" {
+			tooltip.Name += " " + random.Suffix
+		}
+		tooltip.Stats = append(tooltip.Stats, random.Stats...)
+		tooltip.Resistances = append(tooltip.Resistances, random.Resistances...)
+		enchantments := tooltip.Enchantments[:0]
+		for _, e := range tooltip.Enchantments {
+			if e.Kind != "property" {
+				enchantments = append(enchantments, e)
+			}
+		}
+		tooltip.Enchantments = enchantments
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tooltip)
+}
+
+// itemTooltip looks up the tooltip of an item, writing an error response if
+// it cannot
+func (s *Server) itemTooltip(w http.ResponseWriter, r *http.Request, entry int, locale string) (store.ItemTooltip, bool) {
+	tooltip, err := s.tooltips.ItemTooltip(r.Context(), entry, locale)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, codeNotFound, "Item not found")
+		return tooltip, false
+	}
+	if err != nil {
+		writeQueryError(w, r, err)
+		return tooltip, false
+	}
+	return tooltip, true
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// addTooltips describes Thunderfury, with a socketed one on auction as
+// auction 7, and names its enchantments
+func addTooltips(m *store.Memory) {
+	addThunderfury(m)
+	m.AddEnchantment(1900, "Crusader")
+	m.AddEnchantment(2877, "+4 Agility")
+}
+
+// addThunderfury is addTooltips without the enchantment names, as a world
+// database without spellitemenchantment_dbc reads
+func addThunderfury(m *store.Memory) {
+	m.AddItemName(19019, "deDE", "Donnerzorn, Gesegnete Klinge des Windsuchers")
+	m.AddItemTooltip(store.ItemTooltip{
+		Entry:         19019,
+		Name:          "Thunderfury, Blessed Blade of the Windseeker",
+		Quality:       5,
+		ItemLevel:     80,
+		RequiredLevel: 60,
+		Class:         2,
+		Subclass:      7,
+		InventoryType: 13,
+		Bonding:       1,
+		MaxCount:      1,
+		Damage:        []store.ItemDamage{{Min: 44, Max: 115}, {Min: 16, Max: 30, School: 3}},
+		Delay:         1900,
+		Stats:         []store.ItemStat{{Type: 3, Value: 5}, {Type: 7, Value: 8}},
+		Resistances:   []store.ItemResistance{{School: 2, Value: 8}, {School: 3, Value: 9}},
+		Sockets:       []int{2},
+		SocketBonus:   &store.ItemEnchantment{Slot: 5, Kind: "bonus", ID: 2877},
+		Spells:        []store.ItemSpell{{ID: 21992, Trigger: 2, Name: "Thunderfury"}},
+		MaxDurability: 125,
+		SellPrice:     255355,
+	})
+	m.AddAuction(store.AuctionItem{ID: 7, HouseID: wow.HouseNeutral, ItemGUID: 700, ItemOwner: 12, OwnerName: "Carol", ItemEntry: 19019, Count: 1, BuyoutPrice: 60000000, Time: int(now.Add(time.Hour).Unix())})
+	m.SetEnchantments(700, "1900 0 0 0 0 0 3520 0 0 0 0 0 0 0 0 2877 0 0")
+}
+
+func withTooltips(cfg *Config, m *store.Memory) {
+	cfg.Tooltips = m
+}
+
+func TestItemTooltip(t *testing.T) {
+	s := newStoreServer(t, addTooltips, withTooltips)
+
+	var tooltip store.ItemTooltip
+	get(t, s, "/api/items/19019/tooltip", http.StatusOK, &tooltip)
+	if tooltip.Name != "Thunderfury, Blessed Blade of the Windseeker" || tooltip.DPS != 53.9 || len(tooltip.Stats) != 2 {
+		t.Errorf("tooltip = %+v", tooltip)
+	}
+	if tooltip.SocketBonus == nil || tooltip.SocketBonus.Name != "+4 Agility" {
+		t.Errorf("socket bonus = %+v, want +4 Agility", tooltip.SocketBonus)
+	}
+	if len(tooltip.Enchantments) != 0 {
+		t.Errorf("item template enchantments = %+v, want none", tooltip.Enchantments)
+	}
+}
+
+func TestAuctionTooltip(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		fill func(m *store.Memory)
+		want []store.ItemEnchantment
+	}{
+		{"named", addTooltips, []store.ItemEnchantment{
+			{Slot: 0, Kind: "permanent", ID: 1900, Name: "Crusader"},
+			{Slot: 2, Kind: "socket", ID: 3520},
+			{Slot: 5, Kind: "bonus", ID: 2877, Name: "+4 Agility"},
+		}},
+		// Without enchantment names the enchantments are still listed
+		{"unnamed", addThunderfury, []store.ItemEnchantment{
+			{Slot: 0, Kind: "permanent", ID: 1900},
+			{Slot: 2, Kind: "socket", ID: 3520},
+			{Slot: 5, Kind: "bonus", ID: 2877},
+		}},
+	} {
+		s := newStoreServer(t, tt.fill, withTooltips)
+
+		var tooltip store.ItemTooltip
+		get(t, s, "/api/auctions/7/tooltip", http.StatusOK, &tooltip)
+		if len(tooltip.Enchantments) != len(tt.want) {
+			t.Errorf("%s: enchantments = %+v, want %+v", tt.name, tooltip.Enchantments, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if tooltip.Enchantments[i] != tt.want[i] {
+				t.Errorf("%s: enchantment %d = %+v, want %+v", tt.name, i, tooltip.Enchantments[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestTooltipRequests(t *testing.T) {
+	s := newStoreServer(t, addTooltips, withTooltips)
+
+	for _, tt := range []struct {
+		name   string
+		s      *Server
+		target string
+		want   int
+		item   string
+	}{
+		{"item", s, "/api/items/19019/tooltip", http.StatusOK, "Thunderfury, Blessed Blade of the Windseeker"},
+		{"translated item", s, "/api/items/19019/tooltip?locale=deDE", http.StatusOK, "Donnerzorn, Gesegnete Klinge des Windsuchers"},
+		{"auction", s, "/api/auctions/7/tooltip", http.StatusOK, "Thunderfury, Blessed Blade of the Windseeker"},
+		{"unknown item", s, "/api/items/2/tooltip", http.StatusNotFound, ""},
+		{"bad item", s, "/api/items/abc/tooltip", http.StatusBadRequest, ""},
+		// Auctions of items without a tooltip, expired and unknown auctions
+		{"auction without tooltip", s, "/api/auctions/1/tooltip", http.StatusNotFound, ""},
+		{"expired auction", s, "/api/auctions/6/tooltip", http.StatusNotFound, ""},
+		{"unknown auction", s, "/api/auctions/999/tooltip", http.StatusNotFound, ""},
+		{"bad auction", s, "/api/auctions/0/tooltip", http.StatusBadRequest, ""},
+		{"no tooltip repository", newStoreServer(t, addTooltips, nil), "/api/items/19019/tooltip", http.StatusNotFound, ""},
+		{"no tooltip repository for auctions", newStoreServer(t, addTooltips, nil), "/api/auctions/7/tooltip", http.StatusNotFound, ""},
+	} {
+		if tt.want != http.StatusOK {
+			get(t, tt.s, tt.target, tt.want, nil)
+			continue
+		}
+		var tooltip store.ItemTooltip
+		get(t, tt.s, tt.target, tt.want, &tooltip)
+		if tooltip.Name != tt.item {
+			t.Errorf("%s: name = %q, want %q", tt.name, tooltip.Name, tt.item)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// yieldCacheTTL limits how often the loot templates are read, which only
+// change when the world database is updated
+const yieldCacheTTL = time.Hour
+
+// yieldCache holds the loot templates of disenchanting, milling and
+// prospecting, which every realm shares
+type yieldCache struct {
+	sync.Mutex
+	at     time.Time
+	yields store.Yields
+}
+
+// get returns the cached yields, reading them again once they are older
+// than yieldCacheTTL
+func (c *yieldCache) get(ctx context.Context, repo store.YieldRepository) (store.Yields, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.at.IsZero() && time.Since(c.at) <= yieldCacheTTL {
+		return c.yields, nil
+	}
+	yields, err := repo.Yields(ctx)
+	if err != nil {
+		return yields, err
+	}
+	c.yields, c.at = yields, time.Now()
+	return c.yields, nil
+}
+
+// handleGetYields values the listings of items that can be disenchanted,
+// milled or prospected by what they break down into, most profitable first
+func (s *Server) handleGetYields(w http.ResponseWriter, r *http.Request, rm *realm) {
+	p := queryParser{values: r.URL.Query()}
+	q := store.YieldQuery{
+		Method:  r.URL.Query().Get("method"),
+		Quality: p.intRange("quality"),
+	}
+	if q.Method != "" && !store.ValidYieldMethod(q.Method) {
+		p.fail("method")
+	}
+	if below := p.optionalBool("below_yield"); below != nil {
+		q.BelowYield = *below
+	}
+	limit := defaultPageSize
+	if n := p.int("limit"); n > 0 {
+		limit = min(n, maxPageSize)
+	}
+	if p.err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, p.err.Error())
+		return
+	}
+	house, err := parseHouse(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid house")
+		return
+	}
+	q.House = house
+	locale, err := parseLocale(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid locale")
+		return
+	}
+
+	yields, err := s.yieldCache.get(r.Context(), s.yields)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	auctions, err := rm.Auctions.LiveAuctions(r.Context(), locale)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	houses, err := rm.Auctions.Houses(r.Context())
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	listings := store.FindYieldListings(auctions, yields, houses, q)
+	total := len(listings)
+	listings = listings[:min(limit, total)]
+
+	// Materials are named from the item templates, since not all of them
+	// are listed
+	items := make(map[int]store.ItemTemplate)
+	for i := range listings {
+		for j := range listings[i].Outputs {
+			output := &listings[i].Outputs[j]
+			item, ok := items[output.Entry]
+			if !ok {
+				item, err = rm.Auctions.Item(r.Context(), output.Entry, locale)
+				if errors.Is(err, store.ErrNotFound) {
+					item, err = store.ItemTemplate{Entry: output.Entry, Name: "Unknown Item"}, nil
+				}
+				if err != nil {
+					writeQueryError(w, r, err)
+					return
+				}
+				items[output.Entry] = item
+			}
+			output.Name, output.Quality = item.Name, item.Quality
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"listings": listings,
+		"total":    total,
+		"limit":    limit,
+		"method":   q.Method,
+		"house":    house,
+		"locale":   locale,
+	})
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// addYields makes Bandit Cinches disenchant into two Strange Dust, with one
+// cinch listed for less and one for more than that
+func addYields(m *store.Memory) {
+	addYieldItems(m)
+	m.AddDisenchant(9776, store.Disenchant{ID: 1, RequiredSkill: 1})
+	m.AddLoot(store.YieldDisenchant, 1, store.LootRow{Item: 10940, Chance: 100, MinCount: 2, MaxCount: 2})
+	m.AddLoot(store.YieldDisenchant, 1, store.LootRow{Reference: 10, Chance: 100, MaxCount: 1})
+	m.AddLoot("reference", 10, store.LootRow{Item: 10938, Chance: 0, MinCount: 1, MaxCount: 1})
+}
+
+// addYieldItems lists the Bandit Cinches and Strange Dust of addYields
+// without any loot templates, as a world database without them reads
+func addYieldItems(m *store.Memory) {
+	m.AddItem(store.ItemTemplate{Entry: 9776, Name: "Bandit Cinch", Quality: 2, ItemLevel: 10, InventoryType: 6, Class: 4})
+	m.AddItem(store.ItemTemplate{Entry: 10940, Name: "Strange Dust", Quality: 1, ItemLevel: 10, Class: 7, Stackable: 20})
+	m.AddItem(store.ItemTemplate{Entry: 10938, Name: "Lesser Magic Essence", Quality: 2, ItemLevel: 10, Class: 7, Stackable: 20})
+	m.AddItemName(10940, "deDE", "Seltsamer Staub")
+
+	expires := int(now.Unix()) + 3600
+	m.AddAuction(store.AuctionItem{ID: 7, HouseID: wow.HouseAlliance, ItemOwner: 10, OwnerName: "Alice", ItemEntry: 9776, Count: 1, BuyoutPrice: 100, Time: expires})
+	m.AddAuction(store.AuctionItem{ID: 8, HouseID: wow.HouseAlliance, ItemOwner: 10, OwnerName: "Alice", ItemEntry: 9776, Count: 1, BuyoutPrice: 1000, Time: expires})
+	m.AddAuction(store.AuctionItem{ID: 9, HouseID: wow.HouseAlliance, ItemOwner: 11, OwnerName: "Bob", ItemEntry: 10940, Count: 10, BuyoutPrice: 1000, Time: expires})
+}
+
+func withYields(cfg *Config, m *store.Memory) {
+	cfg.Yields = m
+}
+
+type yieldsResponse struct {
+	Listings []store.YieldListing `json:"listings"`
+	Total    int                  `json:"total"`
+}
+
+func yieldID(l store.YieldListing) int {
+	return l.ID
+}
+
+func TestGetYields(t *testing.T) {
+	s := newStoreServer(t, addYields, withYields)
+
+	// Two dust at 100 each make 200, 190 after the Alliance house's cut.
+	// Essences are never listed and the reference without a chance never
+	// drops.
+	var resp yieldsResponse
+	get(t, s, "/api/yields?below_yield=true", http.StatusOK, &resp)
+	if resp.Total != 1 || len(resp.Listings) != 1 {
+		t.Fatalf("listings = %+v, want 1", resp.Listings)
+	}
+	l := resp.Listings[0]
+	if l.ID != 7 || l.Method != store.YieldDisenchant || l.YieldValue != 190 || l.Profit != 90 || !l.BelowYield || l.RequiredSkill != 1 {
+		t.Errorf("listing = %+v, want auction 7 with 90 profit", l)
+	}
+	if len(l.Outputs) != 1 || l.Outputs[0].Name != "Strange Dust" || l.Outputs[0].Expected != 2 || l.Outputs[0].UnitPrice != 100 {
+		t.Errorf("outputs = %+v, want 2 Strange Dust at 100", l.Outputs)
+	}
+
+	get(t, s, "/api/yields?method=disenchant&locale=deDE", http.StatusOK, &resp)
+	if len(resp.Listings) != 2 {
+		t.Fatalf("listings = %+v, want 2", resp.Listings)
+	}
+	if resp.Listings[1].BelowYield || resp.Listings[1].Profit != -810 {
+		t.Errorf("second listing = %+v, want a 810 loss", resp.Listings[1])
+	}
+	if name := resp.Listings[0].Outputs[0].Name; name != "Seltsamer Staub" {
+		t.Errorf("deDE output name = %q", name)
+	}
+
+	if rec := get(t, s, "/", http.StatusOK, nil); !strings.Contains(rec.Body.String(), `id="yieldsTab"`) {
+		t.Error("home page has no yields tab")
+	}
+}
+
+func TestYieldListings(t *testing.T) {
+	s := newStoreServer(t, addYields, withYields)
+
+	for _, tt := range []struct {
+		name   string
+		s      *Server
+		target string
+		want   int
+		ids    []int
+	}{
+		{"all", s, "/api/yields", http.StatusOK, []int{7, 8}},
+		{"below yield", s, "/api/yields?below_yield=true", http.StatusOK, []int{7}},
+		{"disenchanting", s, "/api/yields?method=disenchant&locale=deDE", http.StatusOK, []int{7, 8}},
+		{"milling", s, "/api/yields?method=milling", http.StatusOK, nil},
+		{"horde", s, "/api/yields?house=horde", http.StatusOK, nil},
+		{"unknown method", s, "/api/yields?method=smelting", http.StatusBadRequest, nil},
+		{"bad below_yield", s, "/api/yields?below_yield=maybe", http.StatusBadRequest, nil},
+		// Without loot templates nothing breaks down into anything
+		{"no loot tables", newStoreServer(t, addYieldItems, withYields), "/api/yields", http.StatusOK, nil},
+		{"no yield repository", newStoreServer(t, addYields, nil), "/api/yields", http.StatusNotFound, nil},
+	} {
+		var resp yieldsResponse
+		if tt.want != http.StatusOK {
+			get(t, tt.s, tt.target, tt.want, nil)
+			continue
+		}
+		get(t, tt.s, tt.target, tt.want, &resp)
+		if got := ids(resp.Listings, yieldID); !equalIDs(got, tt.ids) || resp.Total != len(tt.ids) {
+			t.Errorf("%s: listing IDs = %v of %d, want %v", tt.name, got, resp.Total, tt.ids)
+		}
+	}
+}
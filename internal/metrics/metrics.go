@@ -0,0 +1,176 @@
+// Package metrics implements the small subset of the Prometheus text
+// exposition format this application needs, without external dependencies.
+package metrics
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Application metrics shared by the API and background jobs
+var (
+	HTTPRequests = NewCounterVec("ah_http_requests_total",
+		"HTTP requests handled, by route pattern, method and status code.",
+		"route", "method", "status")
+	HTTPDuration = NewHistogramVec("ah_http_request_duration_seconds",
+		"HTTP request latency by route pattern.",
+		[]float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		"route")
+	QueryErrors = NewCounterVec("ah_db_query_errors_total",
+		"Failed database queries, by the route or background job that ran them.",
+		"source")
+)
+
+// CounterVec is a counter partitioned by label values
+type CounterVec struct {
+	mu     sync.Mutex
+	name   string
+	help   string
+	labels []string
+	values map[string]float64
+}
+
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{name: name, help: help, labels: labels, values: make(map[string]float64)}
+}
+
+// Inc adds one to the counter with the given label values, in the order the
+// labels were declared
+func (c *CounterVec) Inc(labelValues ...string) {
+	key := formatLabels(c.labels, labelValues)
+	c.mu.Lock()
+	c.values[key]++
+	c.mu.Unlock()
+}
+
+// Value returns the current count for the given label values
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	key := formatLabels(c.labels, labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[key]
+}
+
+func (c *CounterVec) Write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s{%s} %g\n", c.name, key, c.values[key])
+	}
+}
+
+// HistogramVec is a histogram partitioned by label values
+type HistogramVec struct {
+	mu      sync.Mutex
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+	series  map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return &HistogramVec{name: name, help: help, labels: labels, buckets: buckets, series: make(map[string]*histogram)}
+}
+
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := formatLabels(h.labels, labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			s.counts[i]++
+			break
+		}
+	}
+	s.count++
+	s.sum += value
+}
+
+func (h *HistogramVec) Write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%g\"} %d\n", h.name, key, bound, cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", h.name, key, s.count)
+		fmt.Fprintf(w, "%s_sum{%s} %g\n", h.name, key, s.sum)
+		fmt.Fprintf(w, "%s_count{%s} %d\n", h.name, key, s.count)
+	}
+}
+
+// dbStatsMetrics are the connection pool statistics written by WriteDBStats
+var dbStatsMetrics = []struct {
+	name, help, kind string
+	value            func(sql.DBStats) float64
+}{
+	{"ah_db_max_open_connections", "Maximum number of open connections to the database.", "gauge", func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }},
+	{"ah_db_open_connections", "Established connections, both in use and idle.", "gauge", func(s sql.DBStats) float64 { return float64(s.OpenConnections) }},
+	{"ah_db_in_use_connections", "Connections currently in use.", "gauge", func(s sql.DBStats) float64 { return float64(s.InUse) }},
+	{"ah_db_idle_connections", "Idle connections.", "gauge", func(s sql.DBStats) float64 { return float64(s.Idle) }},
+	{"ah_db_wait_count_total", "Connections waited for.", "counter", func(s sql.DBStats) float64 { return float64(s.WaitCount) }},
+	{"ah_db_wait_duration_seconds_total", "Time spent waiting for connections.", "counter", func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }},
+	{"ah_db_max_idle_closed_total", "Connections closed due to SetMaxIdleConns.", "counter", func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }},
+	{"ah_db_max_lifetime_closed_total", "Connections closed due to SetConnMaxLifetime.", "counter", func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }},
+}
+
+// WriteDBStats writes the statistics of each connection pool, labelled with
+// its key in pools
+func WriteDBStats(w io.Writer, pools map[string]sql.DBStats) {
+	names := sortedKeys(pools)
+	for _, m := range dbStatsMetrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		for _, name := range names {
+			fmt.Fprintf(w, "%s{%s} %g\n", m.name, formatLabels([]string{"db"}, []string{name}), m.value(pools[name]))
+		}
+	}
+}
+
+// labelEscaper escapes label values as the exposition format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatLabels renders label pairs as they appear inside braces
+func formatLabels(names, values []string) string {
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		pairs[i] = name + `="` + labelEscaper.Replace(value) + `"`
+	}
+	return strings.Join(pairs, ",")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
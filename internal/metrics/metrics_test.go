@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCounterVecWrite(t *testing.T) {
+	c := NewCounterVec("test_total", "Test counter.", "route", "status")
+	c.Inc("GET /b", "200")
+	c.Inc("GET /a", "500")
+	c.Inc("GET /b", "200")
+
+	var sb strings.Builder
+	c.Write(&sb)
+	want := `# HELP test_total Test counter.
+# TYPE test_total counter
+test_total{route="GET /a",status="500"} 1
+test_total{route="GET /b",status="200"} 2
+`
+	if sb.String() != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", sb.String(), want)
+	}
+}
+
+func TestHistogramVecWrite(t *testing.T) {
+	h := NewHistogramVec("test_seconds", "Test histogram.", []float64{0.1, 1}, "route")
+	h.Observe(0.05, "x")
+	h.Observe(0.5, "x")
+	h.Observe(5, "x")
+
+	var sb strings.Builder
+	h.Write(&sb)
+	want := `# HELP test_seconds Test histogram.
+# TYPE test_seconds histogram
+test_seconds_bucket{route="x",le="0.1"} 1
+test_seconds_bucket{route="x",le="1"} 2
+test_seconds_bucket{route="x",le="+Inf"} 3
+test_seconds_sum{route="x"} 5.55
+test_seconds_count{route="x"} 3
+`
+	if sb.String() != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", sb.String(), want)
+	}
+}
+
+func TestLabelValuesAreEscaped(t *testing.T) {
+	c := NewCounterVec("test_total", "Test counter.", "source")
+	c.Inc(`say "hi"`)
+
+	var sb strings.Builder
+	c.Write(&sb)
+	if !strings.Contains(sb.String(), `test_total{source="say \"hi\""} 1`) {
+		t.Errorf("label not escaped:\n%s", sb.String())
+	}
+}
+
+func TestWriteDBStats(t *testing.T) {
+	var sb strings.Builder
+	WriteDBStats(&sb, map[string]sql.DBStats{
+		"world":      {OpenConnections: 2},
+		"characters": {OpenConnections: 5, WaitDuration: 1500 * time.Millisecond},
+	})
+	out := sb.String()
+
+	for _, want := range []string{
+		"# TYPE ah_db_open_connections gauge\nah_db_open_connections{db=\"characters\"} 5\nah_db_open_connections{db=\"world\"} 2\n",
+		"# TYPE ah_db_wait_duration_seconds_total counter\n",
+		`ah_db_wait_duration_seconds_total{db="characters"} 1.5`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// AHBotQualities is the number of item qualities mod_auctionhousebot
+// configures, poor (grey) to artifact (yellow)
+const AHBotQualities = 7
+
+// ahbotColors name the qualities in the columns of mod_auctionhousebot
+var ahbotColors = [AHBotQualities]string{"grey", "white", "green", "blue", "purple", "orange", "yellow"}
+
+// AHBotConfig is the configuration of mod_auctionhousebot for one auction
+// house, from acore_world.mod_auctionhousebot. Per-quality settings are
+// indexed by item quality.
+type AHBotConfig struct {
+	AuctionHouse int    `json:"auction_house"`
+	Name         string `json:"name"`
+	// MinItems and MaxItems bound the number of listings the bot keeps. A
+	// MinItems of 0 means the same as MaxItems.
+	MinItems int `json:"min_items"`
+	MaxItems int `json:"max_items"`
+	// TradeGoodsPercent and ItemsPercent split the listings by quality and
+	// between trade goods and other items; together they add up to 100
+	TradeGoodsPercent [AHBotQualities]int `json:"trade_goods_percent"`
+	ItemsPercent      [AHBotQualities]int `json:"items_percent"`
+	// MinPrice and MaxPrice bound buyouts as a percentage of the vendor
+	// price
+	MinPrice [AHBotQualities]int `json:"min_price"`
+	MaxPrice [AHBotQualities]int `json:"max_price"`
+	// MinBidPrice and MaxBidPrice bound starting bids as a percentage of
+	// the buyout
+	MinBidPrice [AHBotQualities]int `json:"min_bid_price"`
+	MaxBidPrice [AHBotQualities]int `json:"max_bid_price"`
+	// MaxStack caps stack sizes; 0 allows full stacks
+	MaxStack [AHBotQualities]int `json:"max_stack"`
+	// BuyerPrice is the multiple of the vendor price the bot pays when it
+	// buys from players
+	BuyerPrice [AHBotQualities]int `json:"buyer_price"`
+	// BuyerBiddingInterval is in minutes
+	BuyerBiddingInterval int `json:"buyer_bidding_interval"`
+	BuyerBidsPerInterval int `json:"buyer_bids_per_interval"`
+}
+
+// ahbotColumns returns the setting columns of mod_auctionhousebot, in the
+// order fields returns them
+func ahbotColumns() []string {
+	columns := []string{"minitems", "maxitems"}
+	for _, color := range ahbotColors {
+		columns = append(columns, "percent"+color+"tradegoods")
+	}
+	for _, color := range ahbotColors {
+		columns = append(columns, "percent"+color+"items")
+	}
+	for _, color := range ahbotColors {
+		columns = append(columns, "minprice"+color, "maxprice"+color)
+	}
+	for _, color := range ahbotColors {
+		columns = append(columns, "minbidprice"+color, "maxbidprice"+color)
+	}
+	for _, color := range ahbotColors {
+		columns = append(columns, "maxstack"+color, "buyerprice"+color)
+	}
+	return append(columns, "buyerbiddinginterval", "buyerbidsperinterval")
+}
+
+// fields returns pointers to the settings of c, in the order of
+// ahbotColumns
+func (c *AHBotConfig) fields() []interface{} {
+	fields := []interface{}{&c.MinItems, &c.MaxItems}
+	for q := range c.TradeGoodsPercent {
+		fields = append(fields, &c.TradeGoodsPercent[q])
+	}
+	for q := range c.ItemsPercent {
+		fields = append(fields, &c.ItemsPercent[q])
+	}
+	for q := range c.MinPrice {
+		fields = append(fields, &c.MinPrice[q], &c.MaxPrice[q])
+	}
+	for q := range c.MinBidPrice {
+		fields = append(fields, &c.MinBidPrice[q], &c.MaxBidPrice[q])
+	}
+	for q := range c.MaxStack {
+		fields = append(fields, &c.MaxStack[q], &c.BuyerPrice[q])
+	}
+	return append(fields, &c.BuyerBiddingInterval, &c.BuyerBidsPerInterval)
+}
+
+// Validate checks the settings of c, returning a message that is safe to
+// show the admin who submitted them
+func (c AHBotConfig) Validate() error {
+	if c.MinItems < 0 || c.MaxItems < 0 {
+		return errors.New("min_items and max_items must not be negative")
+	}
+	if c.MinItems > c.MaxItems {
+		return errors.New("min_items must not exceed max_items")
+	}
+
+	total := 0
+	for q := 0; q < AHBotQualities; q++ {
+		for _, percent := range []int{c.TradeGoodsPercent[q], c.ItemsPercent[q]} {
+			if percent < 0 || percent > 100 {
+				return errors.New("percentages must be between 0 and 100")
+			}
+			total += percent
+		}
+		if c.MinPrice[q] < 0 || c.MinPrice[q] > c.MaxPrice[q] {
+			return fmt.Errorf("min_price of %s items must be between 0 and max_price", ahbotColors[q])
+		}
+		if c.MinBidPrice[q] < 0 || c.MinBidPrice[q] > c.MaxBidPrice[q] || c.MaxBidPrice[q] > 100 {
+			return fmt.Errorf("bid prices of %s items must be between 0 and 100, min_bid_price first", ahbotColors[q])
+		}
+		if c.MaxStack[q] < 0 || c.BuyerPrice[q] < 0 {
+			return errors.New("max_stack and buyer_price must not be negative")
+		}
+	}
+	if total != 100 {
+		return fmt.Errorf("trade_goods_percent and items_percent add up to %d, not 100", total)
+	}
+
+	if c.BuyerBiddingInterval < 1 || c.BuyerBidsPerInterval < 0 {
+		return errors.New("buyer_bidding_interval must be at least 1 and buyer_bids_per_interval not negative")
+	}
+	return nil
+}
+
+// DisabledItem is an item mod_auctionhousebot or mod_auctionator never
+// lists, from their disabled items tables in acore_world
+type DisabledItem struct {
+	Entry int `json:"entry"`
+	// Name is empty when the item has no template
+	Name string `json:"name"`
+}
+
+// HouseDistribution counts the live auctions of one house the way
+// mod_auctionhousebot splits its listings: by quality, with trade goods
+// apart from other items. Qualities the bot does not stock only count
+// towards Total.
+type HouseDistribution struct {
+	House      int                 `json:"house"`
+	Total      int                 `json:"total"`
+	TradeGoods [AHBotQualities]int `json:"trade_goods"`
+	Items      [AHBotQualities]int `json:"items"`
+}
+
+// listingCount is the number of live auctions of one item in one house
+type listingCount struct {
+	house, entry, listings int
+}
+
+// distribute adds up counts by house, class and quality of their items.
+// Auctions of items without a template count as poor items.
+func distribute(counts []listingCount, items map[int]ItemTemplate) []HouseDistribution {
+	byHouse := make(map[int]*HouseDistribution)
+	for _, c := range counts {
+		d, ok := byHouse[c.house]
+		if !ok {
+			d = &HouseDistribution{House: c.house}
+			byHouse[c.house] = d
+		}
+		d.Total += c.listings
+
+		item := items[c.entry]
+		if item.Quality < 0 || item.Quality >= AHBotQualities {
+			continue
+		}
+		if item.Class == wow.ItemClassTradeGoods {
+			d.TradeGoods[item.Quality] += c.listings
+		} else {
+			d.Items[item.Quality] += c.listings
+		}
+	}
+
+	result := make([]HouseDistribution, 0, len(byHouse))
+	for _, d := range byHouse {
+		result = append(result, *d)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].House < result[j].House })
+	return result
+}
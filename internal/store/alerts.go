@@ -0,0 +1,49 @@
+package store
+
+import (
+	"strings"
+	"time"
+)
+
+// AlertRule fires a webhook when a live auction matches it. A rule matches an
+// item by entry or by name pattern, where '*' is a wildcard and a pattern
+// without one matches anywhere in the name. AccountID is the game account
+// that owns the rule, or 0 for rules made with the admin token.
+type AlertRule struct {
+	ID            int       `json:"id"`
+	AccountID     int       `json:"account_id"`
+	Name          string    `json:"name"`
+	ItemEntry     int       `json:"item_entry"`
+	NamePattern   string    `json:"name_pattern"`
+	MaxUnitBuyout int       `json:"max_unit_buyout"`
+	House         int       `json:"house"`
+	MinQuantity   int       `json:"min_quantity"`
+	WebhookURL    string    `json:"webhook_url,omitempty"`
+	Enabled       bool      `json:"enabled"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Filter returns the auction filter selecting the listings this rule fires
+// for
+func (rule AlertRule) Filter() AuctionFilter {
+	maxBuyout := rule.MaxUnitBuyout
+	f := AuctionFilter{
+		House:      rule.House,
+		ItemEntry:  rule.ItemEntry,
+		UnitBuyout: IntRange{Max: &maxBuyout},
+	}
+	if rule.NamePattern != "" {
+		pattern := EscapeLike(rule.NamePattern)
+		if strings.Contains(pattern, "*") {
+			f.ItemNameLike = strings.ReplaceAll(pattern, "*", "%")
+		} else {
+			f.ItemNameLike = "%" + pattern + "%"
+		}
+	}
+	if rule.MinQuantity > 0 {
+		minQuantity := rule.MinQuantity
+		f.Count = IntRange{Min: &minQuantity}
+	}
+	return f
+}
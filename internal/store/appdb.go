@@ -0,0 +1,440 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// appMigrations holds the schema of the application's own database, which
+// stores data the game server does not keep (such as price history and alert
+// rules). Each entry is applied exactly once, in order; append new statements
+// to the end and never edit ones that have already shipped.
+var appMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS price_snapshot (
+		id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+		taken_at DATETIME NOT NULL,
+		auctions INT UNSIGNED NOT NULL DEFAULT 0,
+		PRIMARY KEY (id),
+		KEY idx_taken_at (taken_at)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS item_price_snapshot (
+		snapshot_id BIGINT UNSIGNED NOT NULL,
+		item_entry INT UNSIGNED NOT NULL,
+		listings INT UNSIGNED NOT NULL DEFAULT 0,
+		quantity INT UNSIGNED NOT NULL DEFAULT 0,
+		min_buyout BIGINT UNSIGNED NOT NULL DEFAULT 0,
+		median_buyout BIGINT UNSIGNED NOT NULL DEFAULT 0,
+		mean_buyout BIGINT UNSIGNED NOT NULL DEFAULT 0,
+		PRIMARY KEY (snapshot_id, item_entry),
+		KEY idx_item_entry (item_entry, snapshot_id),
+		CONSTRAINT fk_item_price_snapshot FOREIGN KEY (snapshot_id)
+			REFERENCES price_snapshot (id) ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS alert_rule (
+		id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+		name VARCHAR(100) NOT NULL DEFAULT '',
+		item_entry INT UNSIGNED NOT NULL DEFAULT 0,
+		name_pattern VARCHAR(255) NOT NULL DEFAULT '',
+		max_unit_buyout BIGINT UNSIGNED NOT NULL DEFAULT 0,
+		house_id TINYINT UNSIGNED NOT NULL DEFAULT 0,
+		min_quantity INT UNSIGNED NOT NULL DEFAULT 0,
+		webhook_url VARCHAR(512) NOT NULL,
+		enabled TINYINT(1) NOT NULL DEFAULT 1,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		PRIMARY KEY (id)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS alert_delivery (
+		rule_id INT UNSIGNED NOT NULL,
+		auction_id INT UNSIGNED NOT NULL,
+		delivered_at DATETIME NOT NULL,
+		PRIMARY KEY (rule_id, auction_id),
+		KEY idx_delivered_at (delivered_at),
+		CONSTRAINT fk_alert_delivery_rule FOREIGN KEY (rule_id)
+			REFERENCES alert_rule (id) ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	// Data recorded before realms were supported belongs to realm 1, the
+	// default realm of a fresh AzerothCore install
+	`ALTER TABLE price_snapshot
+		ADD COLUMN realm_id INT UNSIGNED NOT NULL DEFAULT 1 AFTER id,
+		ADD KEY idx_realm_taken_at (realm_id, taken_at)`,
+	`ALTER TABLE alert_rule
+		ADD COLUMN realm_id INT UNSIGNED NOT NULL DEFAULT 1 AFTER id,
+		ADD KEY idx_realm (realm_id)`,
+	`CREATE TABLE IF NOT EXISTS admin_audit (
+		id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+		changed_at DATETIME NOT NULL,
+		actor VARCHAR(100) NOT NULL DEFAULT '',
+		action VARCHAR(50) NOT NULL,
+		target VARCHAR(100) NOT NULL DEFAULT '',
+		before_value JSON NULL,
+		after_value JSON NULL,
+		request_id VARCHAR(64) NOT NULL DEFAULT '',
+		PRIMARY KEY (id),
+		KEY idx_changed_at (changed_at)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	// Rules made before they had owners are left to the admin token
+	`ALTER TABLE alert_rule
+		ADD COLUMN account_id INT UNSIGNED NOT NULL DEFAULT 0 AFTER realm_id,
+		ADD KEY idx_account (account_id)`,
+}
+
+// AppDB stores price history, alert rules and the admin audit log in the
+// application's own database. Every realm shares the database; an AppDB
+// reads and writes the data of one realm, see Realm. The audit log belongs to
+// no realm.
+type AppDB struct {
+	db    *sql.DB
+	realm int
+}
+
+var (
+	_ HistoryRepository = (*AppDB)(nil)
+	_ AlertRepository   = (*AppDB)(nil)
+	_ AuditRepository   = (*AppDB)(nil)
+)
+
+// OpenAppDB connects to the application database and brings its schema up to
+// date. The database itself must already exist. The returned AppDB holds
+// the data of realm 1.
+func OpenAppDB(dsn string) (*AppDB, error) {
+	conn, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := migrateAppDB(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &AppDB{db: conn, realm: 1}, nil
+}
+
+// Realm returns an AppDB holding the data of the given realm. It shares the
+// connection pool of s.
+func (s *AppDB) Realm(id int) *AppDB {
+	return &AppDB{db: s.db, realm: id}
+}
+
+// Close closes the underlying connection pool, shared by every realm
+func (s *AppDB) Close() error {
+	return s.db.Close()
+}
+
+func migrateAppDB(conn *sql.DB) error {
+	_, err := conn.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INT UNSIGNED NOT NULL,
+		applied_at DATETIME NOT NULL,
+		PRIMARY KEY (version)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`)
+	if err != nil {
+		return fmt.Errorf("creating schema_migrations: %w", err)
+	}
+
+	var current int
+	if err := conn.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("reading schema version: %w", err)
+	}
+
+	for i := current; i < len(appMigrations); i++ {
+		version := i + 1
+		if _, err := conn.Exec(appMigrations[i]); err != nil {
+			return fmt.Errorf("applying migration %d: %w", version, err)
+		}
+		if _, err := conn.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, NOW())`, version); err != nil {
+			return fmt.Errorf("recording migration %d: %w", version, err)
+		}
+		log.Printf("Applied app database migration %d", version)
+	}
+	return nil
+}
+
+func (s *AppDB) SaveSnapshot(ctx context.Context, takenAt time.Time, auctions int, prices map[int]PricePoint) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `INSERT INTO price_snapshot (realm_id, taken_at, auctions) VALUES (?, ?, ?)`, s.realm, takenAt.UTC(), auctions)
+	if err != nil {
+		return err
+	}
+	snapshotID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO item_price_snapshot
+			(snapshot_id, item_entry, listings, quantity, min_buyout, median_buyout, mean_buyout)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for entry, p := range prices {
+		_, err := stmt.ExecContext(ctx, snapshotID, entry, p.Listings, p.Quantity, p.MinBuyout, p.MedianBuyout, p.MeanBuyout)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *AppDB) PruneSnapshots(ctx context.Context, before time.Time) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM price_snapshot WHERE realm_id = ? AND taken_at < ?`, s.realm, before.UTC())
+	return err
+}
+
+func (s *AppDB) ItemHistory(ctx context.Context, entry int, since time.Time) ([]PricePoint, error) {
+	query := `
+		SELECT
+			ps.taken_at, ips.listings, ips.quantity,
+			ips.min_buyout, ips.median_buyout, ips.mean_buyout
+		FROM item_price_snapshot ips
+		JOIN price_snapshot ps ON ips.snapshot_id = ps.id
+		WHERE ips.item_entry = ?
+		AND ps.realm_id = ?
+		AND ps.taken_at >= ?
+		ORDER BY ps.taken_at ASC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, entry, s.realm, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []PricePoint{}
+	for rows.Next() {
+		var p PricePoint
+		err := rows.Scan(&p.Time, &p.Listings, &p.Quantity, &p.MinBuyout, &p.MedianBuyout, &p.MeanBuyout)
+		if err != nil {
+			return nil, fmt.Errorf("scanning price point: %w", err)
+		}
+		history = append(history, p)
+	}
+	return history, rows.Err()
+}
+
+// MedianPrices ranks each item's snapshots by median buyout and averages
+// the middle one or two
+func (s *AppDB) MedianPrices(ctx context.Context, since time.Time) (map[int]int, error) {
+	query := `
+		SELECT item_entry, AVG(median_buyout)
+		FROM (
+			SELECT
+				ips.item_entry, ips.median_buyout,
+				ROW_NUMBER() OVER (PARTITION BY ips.item_entry ORDER BY ips.median_buyout) AS position,
+				COUNT(*) OVER (PARTITION BY ips.item_entry) AS points
+			FROM item_price_snapshot ips
+			JOIN price_snapshot ps ON ips.snapshot_id = ps.id
+			WHERE ps.realm_id = ?
+			AND ps.taken_at >= ?
+			AND ips.median_buyout > 0
+		) ranked
+		WHERE position IN (FLOOR((points + 1) / 2), FLOOR(points / 2) + 1)
+		GROUP BY item_entry
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, s.realm, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	medians := make(map[int]int)
+	for rows.Next() {
+		var entry int
+		var price float64
+		if err := rows.Scan(&entry, &price); err != nil {
+			return nil, fmt.Errorf("scanning median price: %w", err)
+		}
+		medians[entry] = int(price)
+	}
+	return medians, rows.Err()
+}
+
+const alertRuleColumns = `
+	id, account_id, name, item_entry, name_pattern, max_unit_buyout, house_id,
+	min_quantity, webhook_url, enabled, created_at, updated_at
+`
+
+func scanAlertRule(row interface{ Scan(...interface{}) error }) (AlertRule, error) {
+	var rule AlertRule
+	err := row.Scan(
+		&rule.ID, &rule.AccountID, &rule.Name, &rule.ItemEntry, &rule.NamePattern, &rule.MaxUnitBuyout,
+		&rule.House, &rule.MinQuantity, &rule.WebhookURL, &rule.Enabled,
+		&rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return rule, ErrNotFound
+	}
+	return rule, err
+}
+
+func (s *AppDB) AlertRules(ctx context.Context, enabledOnly bool) ([]AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rule WHERE realm_id = ?`
+	if enabledOnly {
+		query += ` AND enabled = 1`
+	}
+	query += ` ORDER BY id`
+
+	rows, err := s.db.QueryContext(ctx, query, s.realm)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []AlertRule{}
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (s *AppDB) AlertRule(ctx context.Context, id int) (AlertRule, error) {
+	return scanAlertRule(s.db.QueryRowContext(ctx, `SELECT `+alertRuleColumns+` FROM alert_rule WHERE id = ? AND realm_id = ?`, id, s.realm))
+}
+
+func (s *AppDB) CreateAlertRule(ctx context.Context, rule AlertRule) (AlertRule, error) {
+	now := time.Now()
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO alert_rule
+			(realm_id, account_id, name, item_entry, name_pattern, max_unit_buyout,
+			 house_id, min_quantity, webhook_url, enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, s.realm, rule.AccountID, rule.Name, rule.ItemEntry, rule.NamePattern, rule.MaxUnitBuyout, rule.House,
+		rule.MinQuantity, rule.WebhookURL, rule.Enabled, now, now)
+	if err != nil {
+		return rule, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return rule, err
+	}
+	return s.AlertRule(ctx, int(id))
+}
+
+// UpdateAlertRule keeps the deliveries already made, so tightening a rule does
+// not re-send auctions it already fired for
+func (s *AppDB) UpdateAlertRule(ctx context.Context, rule AlertRule) (AlertRule, error) {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE alert_rule SET
+			name = ?, item_entry = ?, name_pattern = ?, max_unit_buyout = ?, house_id = ?,
+			min_quantity = ?, webhook_url = ?, enabled = ?, updated_at = ?
+		WHERE id = ? AND realm_id = ?
+	`, rule.Name, rule.ItemEntry, rule.NamePattern, rule.MaxUnitBuyout, rule.House,
+		rule.MinQuantity, rule.WebhookURL, rule.Enabled, time.Now(), rule.ID, s.realm)
+	if err != nil {
+		return rule, err
+	}
+	// MySQL reports zero affected rows for updates that change nothing, so a
+	// missing rule is detected when reading it back instead
+	return s.AlertRule(ctx, rule.ID)
+}
+
+func (s *AppDB) DeleteAlertRule(ctx context.Context, id int) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM alert_rule WHERE id = ? AND realm_id = ?`, id, s.realm)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *AppDB) DeliveredAuctions(ctx context.Context, ruleID int) (map[int]bool, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT auction_id FROM alert_delivery WHERE rule_id = ?`, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	delivered := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		delivered[id] = true
+	}
+	return delivered, rows.Err()
+}
+
+func (s *AppDB) RecordDeliveries(ctx context.Context, ruleID int, auctionIDs []int, at time.Time) error {
+	for _, id := range auctionIDs {
+		_, err := s.db.ExecContext(ctx, `INSERT IGNORE INTO alert_delivery (rule_id, auction_id, delivered_at) VALUES (?, ?, ?)`,
+			ruleID, id, at)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *AppDB) PruneDeliveries(ctx context.Context, before time.Time) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM alert_delivery WHERE delivered_at < ?`, before)
+	return err
+}
+
+// nullJSON stores an empty JSON value as NULL. Values are sent as strings,
+// as MySQL refuses to read JSON from binary strings.
+func nullJSON(value json.RawMessage) interface{} {
+	if len(value) == 0 {
+		return nil
+	}
+	return string(value)
+}
+
+func (s *AppDB) RecordAudit(ctx context.Context, entry AuditEntry) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO admin_audit
+			(changed_at, actor, action, target, before_value, after_value, request_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, entry.Time.UTC(), entry.Actor, entry.Action, entry.Target,
+		nullJSON(entry.Before), nullJSON(entry.After), entry.RequestID)
+	return err
+}
+
+func (s *AppDB) AuditLog(ctx context.Context, limit int) ([]AuditEntry, error) {
+	query := `
+		SELECT id, changed_at, actor, action, target, before_value, after_value, request_id
+		FROM admin_audit
+		ORDER BY id DESC
+		LIMIT ?
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []AuditEntry{}
+	for rows.Next() {
+		var e AuditEntry
+		var before, after []byte
+		if err := rows.Scan(&e.ID, &e.Time, &e.Actor, &e.Action, &e.Target, &before, &after, &e.RequestID); err != nil {
+			return nil, fmt.Errorf("scanning audit entry: %w", err)
+		}
+		e.Before, e.After = before, after
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
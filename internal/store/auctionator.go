@@ -0,0 +1,121 @@
+package store
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// joinMarketPrice copies mod_auctionator's market price of an auction's item
+// from prices
+func joinMarketPrice(a *AuctionItem, prices map[int]MarketPrice) {
+	a.AuctionatorPrice, a.AuctionatorScannedAt = 0, time.Time{}
+	if price, ok := prices[a.ItemEntry]; ok && price.AveragePrice > 0 {
+		a.AuctionatorPrice, a.AuctionatorScannedAt = price.AveragePrice, price.ScannedAt
+	}
+}
+
+// MarketComparison compares the live listings of one item with its
+// mod_auctionator market price. Buyouts are per unit, over the listings
+// with a buyout, and the median is weighted by stack size like
+// SummarizeListings.
+type MarketComparison struct {
+	Entry            int       `json:"entry"`
+	ItemName         string    `json:"item_name"`
+	Quality          int       `json:"quality"`
+	Listings         int       `json:"listings"`
+	Units            int       `json:"units"`
+	MinUnitBuyout    int       `json:"min_unit_buyout"`
+	MedianUnitBuyout int       `json:"median_unit_buyout"`
+	MarketPrice      int       `json:"market_price"`
+	ScannedAt        time.Time `json:"scanned_at"`
+	// PercentOfMarket is the lowest buyout as a percentage of the market
+	// price
+	PercentOfMarket int `json:"percent_of_market"`
+}
+
+// CompareQuery selects market comparisons. Zero values match everything.
+type CompareQuery struct {
+	House   int
+	Quality IntRange
+	// Desc lists the items priced furthest above the market first, instead
+	// of the cheapest
+	Desc bool
+}
+
+// CompareMarket compares the auctions matching q with the market prices
+// joined into them, item by item. Items without a market price or a buyout
+// are left out. Items are ordered by PercentOfMarket, cheapest first unless
+// q.Desc is set.
+func CompareMarket(auctions []AuctionItem, q CompareQuery) []MarketComparison {
+	byEntry := make(map[int]*MarketComparison)
+	listings := make(map[int][]UnitListing)
+	for _, a := range auctions {
+		if a.AuctionatorPrice <= 0 || a.BuyoutPrice <= 0 || a.Count <= 0 {
+			continue
+		}
+		if (q.House != 0 && a.HouseID != q.House) || !q.Quality.contains(a.Quality) {
+			continue
+		}
+		c, ok := byEntry[a.ItemEntry]
+		if !ok {
+			c = &MarketComparison{
+				Entry:         a.ItemEntry,
+				ItemName:      a.ItemName,
+				Quality:       a.Quality,
+				MinUnitBuyout: a.UnitBuyout,
+				MarketPrice:   a.AuctionatorPrice,
+				ScannedAt:     a.AuctionatorScannedAt,
+			}
+			byEntry[a.ItemEntry] = c
+		}
+		c.Listings++
+		c.Units += a.Count
+		c.MinUnitBuyout = min(c.MinUnitBuyout, a.UnitBuyout)
+		listings[a.ItemEntry] = append(listings[a.ItemEntry], UnitListing{Count: a.Count, Buyout: a.BuyoutPrice, PerUnit: a.UnitBuyout})
+	}
+
+	comparisons := make([]MarketComparison, 0, len(byEntry))
+	for entry, c := range byEntry {
+		c.MedianUnitBuyout = SummarizeListings(listings[entry]).MedianBuyout
+		c.PercentOfMarket = c.MinUnitBuyout * 100 / c.MarketPrice
+		comparisons = append(comparisons, *c)
+	}
+	sort.Slice(comparisons, func(i, j int) bool {
+		a, b := comparisons[i], comparisons[j]
+		if a.PercentOfMarket != b.PercentOfMarket {
+			return (a.PercentOfMarket < b.PercentOfMarket) != q.Desc
+		}
+		return a.Entry < b.Entry
+	})
+	return comparisons
+}
+
+// AuctionatorClass is mod_auctionator's configuration of one item class and
+// subclass, from acore_world.mod_auctionator_itemclass_config, which decides
+// what its seller lists
+type AuctionatorClass struct {
+	Class    int `json:"class"`
+	Subclass int `json:"subclass"`
+	// Name is from mod_auctionator_item_class, and empty when it has none
+	Name string `json:"name"`
+	// Bonding is the lowest item quality listed, despite its name
+	Bonding int `json:"bonding"`
+	// MaxCount is how many distinct items of the class the seller keeps
+	// listed
+	MaxCount int `json:"max_count"`
+	// StackCount is the number of items in each stack it lists
+	StackCount int `json:"stack_count"`
+}
+
+// Validate checks the settings of c, returning a message that is safe to
+// show the admin who submitted them
+func (c AuctionatorClass) Validate() error {
+	if c.MaxCount < 0 {
+		return errors.New("max_count must not be negative")
+	}
+	if c.StackCount < 1 {
+		return errors.New("stack_count must be at least 1")
+	}
+	return nil
+}
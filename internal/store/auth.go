@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Account is a game account from acore_auth.account. Salt and Verifier are
+// the SRP6 credentials the auth server checks passwords against, see
+// wow.CheckSRP6Password.
+type Account struct {
+	ID       int
+	Username string
+	Salt     []byte
+	Verifier []byte
+	// GMLevel is the highest gmlevel of the account in account_access on
+	// the realm it was looked up for or on every realm (RealmID -1), and 0
+	// for players
+	GMLevel int
+	// Banned is set while the account has an active ban that is permanent or
+	// not yet lifted
+	Banned bool
+}
+
+// Auth reads game accounts from the AzerothCore auth database
+type Auth struct {
+	db *sql.DB
+}
+
+var _ AccountRepository = (*Auth)(nil)
+
+// NewAuth returns a repository reading accounts from the auth database db
+func NewAuth(db *sql.DB) *Auth {
+	return &Auth{db: db}
+}
+
+// Account looks the username up through the case-insensitive collation of
+// account.username, as the auth server does. Bans are checked the way the
+// auth server checks them: a ban whose unbandate equals its bandate is
+// permanent, others end at their unbandate.
+func (a *Auth) Account(ctx context.Context, username string, realm int) (Account, error) {
+	query := `
+		SELECT a.id, a.username, a.salt, a.verifier,
+			COALESCE((
+				SELECT MAX(aa.gmlevel) FROM account_access aa
+				WHERE aa.id = a.id AND aa.RealmID IN (-1, ?)
+			), 0),
+			EXISTS (
+				SELECT 1 FROM account_banned b
+				WHERE b.id = a.id AND b.active = 1
+					AND (b.unbandate = b.bandate OR b.unbandate > UNIX_TIMESTAMP())
+			)
+		FROM account a
+		WHERE a.username = ?
+	`
+
+	var account Account
+	err := a.db.QueryRowContext(ctx, query, realm, username).Scan(
+		&account.ID, &account.Username, &account.Salt, &account.Verifier,
+		&account.GMLevel, &account.Banned,
+	)
+	if err == sql.ErrNoRows {
+		return account, ErrNotFound
+	}
+	return account, err
+}
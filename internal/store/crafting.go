@@ -0,0 +1,147 @@
+package store
+
+import (
+	"sort"
+	"strings"
+)
+
+// Profession is a skill line of skillline_dbc with recipes, such as
+// Alchemy or Cooking
+type Profession struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Recipe is a spell of a profession that creates an item, from
+// skilllineability_dbc and spell_dbc
+type Recipe struct {
+	SpellID int    `json:"spell_id"`
+	Name    string `json:"name"`
+	// MinSkill is the skill the recipe is learned at, and TrivialLow and
+	// TrivialHigh where it turns green and grey
+	MinSkill    int `json:"min_skill"`
+	TrivialLow  int `json:"trivial_low"`
+	TrivialHigh int `json:"trivial_high"`
+	// Product is the item created, between MinCount and MaxCount at a time
+	Product  CraftItem   `json:"product"`
+	MinCount int         `json:"min_count"`
+	MaxCount int         `json:"max_count"`
+	Reagents []CraftItem `json:"reagents"`
+}
+
+// CraftItem is the product or a reagent of a recipe. Count is how many of a
+// reagent one craft takes.
+type CraftItem struct {
+	Entry   int    `json:"entry"`
+	Name    string `json:"name"`
+	Quality int    `json:"quality"`
+	Count   int    `json:"count,omitempty"`
+	// UnitPrice is the cheapest per-unit buyout of the item on the auction
+	// house, or 0 when none is listed
+	UnitPrice int `json:"unit_price"`
+}
+
+// Craft prices a recipe at the current auction house buyouts
+type Craft struct {
+	Recipe
+	// Priced reports whether the product and every reagent are listed.
+	// Without them, cost and margin are unknown and left at 0.
+	Priced bool `json:"priced"`
+	// Cost is what buying the reagents of one craft costs
+	Cost int `json:"cost"`
+	// Revenue is what selling the product of one craft at its cheapest
+	// buyout earns after the auction house's consignment cut
+	Revenue int `json:"revenue"`
+	Margin  int `json:"margin"`
+	// MarginPercent is the margin as a percentage of the cost
+	MarginPercent int `json:"margin_percent"`
+}
+
+// createItemEffect is the spell effect of spell_dbc that creates an item
+const createItemEffect = 24
+
+// createdCount returns how many items a create item effect makes at least and
+// at most, from its base points and die sides, the way the worldserver rolls
+// them
+func createdCount(basePoints, dieSides int) (int, int) {
+	if dieSides <= 1 {
+		count := max(basePoints+dieSides, 1)
+		return count, count
+	}
+	return basePoints + 1, basePoints + dieSides
+}
+
+// PriceCrafts prices recipes with the cheapest buyouts of the auctions
+// listed in house, or in every house when house is 0, most profitable first.
+// The product is sold in the house of its cheapest listing, whose
+// consignment rate houses supply. Recipes missing prices come last, by name.
+func PriceCrafts(recipes []Recipe, auctions []AuctionItem, houses []AuctionHouse, house int) []Craft {
+	cuts := make(map[int]int, len(houses))
+	for _, h := range houses {
+		cuts[h.ID] = h.ConsignmentRate
+	}
+	cheapest := make(map[int]AuctionItem)
+	for _, a := range auctions {
+		if (house != 0 && a.HouseID != house) || a.BuyoutPrice <= 0 || a.Count <= 0 {
+			continue
+		}
+		if c, ok := cheapest[a.ItemEntry]; !ok || a.UnitBuyout < c.UnitBuyout {
+			cheapest[a.ItemEntry] = a
+		}
+	}
+
+	crafts := make([]Craft, 0, len(recipes))
+	for _, recipe := range recipes {
+		craft := Craft{Recipe: recipe, Priced: true}
+		craft.Reagents = append([]CraftItem(nil), recipe.Reagents...)
+		for i, reagent := range craft.Reagents {
+			listing, ok := cheapest[reagent.Entry]
+			if !ok {
+				craft.Priced = false
+				continue
+			}
+			craft.Reagents[i].UnitPrice = listing.UnitBuyout
+			craft.Cost += listing.UnitBuyout * reagent.Count
+		}
+		product, ok := cheapest[recipe.Product.Entry]
+		if ok {
+			craft.Product.UnitPrice = product.UnitBuyout
+		}
+		if !ok || !craft.Priced {
+			craft.Priced, craft.Cost = false, 0
+			crafts = append(crafts, craft)
+			continue
+		}
+		value := product.UnitBuyout * (recipe.MinCount + recipe.MaxCount) / 2
+		craft.Revenue = value - value*cuts[product.HouseID]/100
+		craft.Margin = craft.Revenue - craft.Cost
+		if craft.Cost > 0 {
+			craft.MarginPercent = craft.Margin * 100 / craft.Cost
+		}
+		crafts = append(crafts, craft)
+	}
+
+	sort.Slice(crafts, func(i, j int) bool {
+		a, b := crafts[i], crafts[j]
+		if a.Priced != b.Priced {
+			return a.Priced
+		}
+		if a.Priced && a.Margin != b.Margin {
+			return a.Margin > b.Margin
+		}
+		if an, bn := strings.ToLower(a.Name), strings.ToLower(b.Name); an != bn {
+			return an < bn
+		}
+		return a.SpellID < b.SpellID
+	})
+	return crafts
+}
+
+// nameCraftItem copies the name and quality of an item from items
+func nameCraftItem(c *CraftItem, items map[int]ItemTemplate) {
+	if item, ok := items[c.Entry]; ok {
+		c.Name, c.Quality = item.Name, item.Quality
+		return
+	}
+	c.Name = "Unknown Item"
+}
@@ -0,0 +1,206 @@
+package store
+
+import (
+	"sort"
+	"time"
+)
+
+// Sources of reference prices
+const (
+	// PriceSourceAuctionator is mod_auctionator's market price
+	PriceSourceAuctionator = "auctionator"
+	// PriceSourceMedian is the median of the recent price snapshots
+	PriceSourceMedian = "median"
+)
+
+// MarketPrice is an item's market price as last scanned by mod_auctionator,
+// from acore_characters.mod_auctionator_market_price
+type MarketPrice struct {
+	Entry int `json:"entry"`
+	// AveragePrice is per unit, in copper
+	AveragePrice int       `json:"average_price"`
+	ScannedAt    time.Time `json:"scanned_at"`
+}
+
+// ReferencePrice is the per-unit price an item is expected to sell for
+type ReferencePrice struct {
+	PerUnit int
+	Source  string
+}
+
+// ReferencePrices picks a reference price for every item that has one:
+// mod_auctionator's market price where it is set, the rolling median of our
+// own snapshots otherwise
+func ReferencePrices(market map[int]MarketPrice, medians map[int]int) map[int]ReferencePrice {
+	refs := make(map[int]ReferencePrice, len(market)+len(medians))
+	for entry, median := range medians {
+		if median > 0 {
+			refs[entry] = ReferencePrice{PerUnit: median, Source: PriceSourceMedian}
+		}
+	}
+	for entry, price := range market {
+		if price.AveragePrice > 0 {
+			refs[entry] = ReferencePrice{PerUnit: price.AveragePrice, Source: PriceSourceAuctionator}
+		}
+	}
+	return refs
+}
+
+// Deal is a live auction whose buyout is below its item's reference price
+type Deal struct {
+	AuctionItem
+	// MarketPrice is the per-unit reference price, from PriceSource
+	MarketPrice     int    `json:"market_price"`
+	PriceSource     string `json:"price_source"`
+	PercentOfMarket int    `json:"percent_of_market"`
+	// Profit is what buying out and reselling at the market price would
+	// earn after the auction house's consignment cut, in copper
+	Profit int `json:"profit"`
+}
+
+// DealQuery selects deals
+type DealQuery struct {
+	House   int
+	Quality IntRange
+	// MaxPercent is the highest buyout, as a percentage of the market
+	// price, that counts as a deal
+	MaxPercent int
+	// MinProfit leaves out smaller deals. Deals that would lose money are
+	// never returned.
+	MinProfit int
+}
+
+// FindDeals returns the auctions matching q whose buyout is at most
+// q.MaxPercent of the reference price of their items, most profitable
+// first. houses supplies the consignment rates by house ID.
+func FindDeals(auctions []AuctionItem, refs map[int]ReferencePrice, houses []AuctionHouse, q DealQuery) []Deal {
+	cuts := make(map[int]int, len(houses))
+	for _, h := range houses {
+		cuts[h.ID] = h.ConsignmentRate
+	}
+
+	deals := []Deal{}
+	for _, a := range auctions {
+		ref, ok := refs[a.ItemEntry]
+		if !ok || a.BuyoutPrice <= 0 || a.Count <= 0 {
+			continue
+		}
+		if (q.House != 0 && a.HouseID != q.House) || !q.Quality.contains(a.Quality) {
+			continue
+		}
+		value := ref.PerUnit * a.Count
+		if q.MaxPercent > 0 && a.BuyoutPrice*100 > value*q.MaxPercent {
+			continue
+		}
+		profit := value - value*cuts[a.HouseID]/100 - a.BuyoutPrice
+		if profit < max(q.MinProfit, 0) {
+			continue
+		}
+		deals = append(deals, Deal{
+			AuctionItem:     a,
+			MarketPrice:     ref.PerUnit,
+			PriceSource:     ref.Source,
+			PercentOfMarket: a.BuyoutPrice * 100 / value,
+			Profit:          profit,
+		})
+	}
+
+	sort.Slice(deals, func(i, j int) bool {
+		a, b := deals[i], deals[j]
+		if a.Profit != b.Profit {
+			return a.Profit > b.Profit
+		}
+		if a.PercentOfMarket != b.PercentOfMarket {
+			return a.PercentOfMarket < b.PercentOfMarket
+		}
+		return a.ID < b.ID
+	})
+	return deals
+}
+
+// median returns the middle of values, or the mean of the two middle ones,
+// sorting values in place
+func median(values []int) int {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Ints(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+// Ways of buying an auction
+const (
+	BuyBuyout = "buyout"
+	BuyBid    = "bid"
+)
+
+// MinBid returns the lowest bid the game server accepts on an auction: the
+// starting bid, or 5% over the current bid, rounded down, and at least one
+// copper more, as AuctionEntry::GetAuctionOutBid works it out
+func (a AuctionItem) MinBid() int {
+	if a.LastBid == 0 {
+		return a.StartBid
+	}
+	return a.LastBid + max(a.LastBid*5/100, 1)
+}
+
+// VendorFlip is a live auction that costs less than a vendor pays for its
+// items
+type VendorFlip struct {
+	AuctionItem
+	// Price is what buying the auction costs, by buyout or by the minimum
+	// bid as Buy says
+	Price int    `json:"price"`
+	Buy   string `json:"buy"`
+	// VendorValue is what a vendor pays for the whole stack
+	VendorValue int `json:"vendor_value"`
+	Profit      int `json:"profit"`
+}
+
+// FlipQuery selects vendor flips. Zero values match everything.
+type FlipQuery struct {
+	House   int
+	Quality IntRange
+}
+
+// FindVendorFlips returns the auctions matching q that can be bought for
+// less than their vendor value. A profitable buyout is preferred, as its
+// profit is guaranteed; otherwise the minimum bid is used, which only pays
+// off if the bid wins. Buyouts come first, then bids, each most profitable
+// first.
+func FindVendorFlips(auctions []AuctionItem, q FlipQuery) []VendorFlip {
+	flips := []VendorFlip{}
+	for _, a := range auctions {
+		if (q.House != 0 && a.HouseID != q.House) || !q.Quality.contains(a.Quality) {
+			continue
+		}
+		value := a.SellPrice * a.Count
+		flip := VendorFlip{AuctionItem: a, VendorValue: value}
+		switch {
+		case a.BuyoutPrice > 0 && a.BuyoutPrice < value:
+			flip.Price, flip.Buy = a.BuyoutPrice, BuyBuyout
+		case a.MinBid() < value:
+			flip.Price, flip.Buy = a.MinBid(), BuyBid
+		default:
+			continue
+		}
+		flip.Profit = value - flip.Price
+		flips = append(flips, flip)
+	}
+
+	sort.Slice(flips, func(i, j int) bool {
+		a, b := flips[i], flips[j]
+		if a.Buy != b.Buy {
+			return a.Buy == BuyBuyout
+		}
+		if a.Profit != b.Profit {
+			return a.Profit > b.Profit
+		}
+		return a.ID < b.ID
+	})
+	return flips
+}
@@ -0,0 +1,302 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SQL expressions for derived auction values
+const (
+	currentBidSQL = `IF(ah.lastbid > 0, ah.lastbid, ah.startbid)`
+	unitBuyoutSQL = `ah.buyoutprice DIV NULLIF(ii.count, 0)`
+	timeLeftSQL   = `(ah.time - UNIX_TIMESTAMP())`
+)
+
+// timeLeftBuckets are the inclusive ranges, in seconds, of the time left
+// buckets shown by the in-game auction house. A Max of 0 is unbounded.
+var timeLeftBuckets = map[string]struct{ Min, Max int }{
+	"short":     {0, 1799},
+	"medium":    {1800, 7199},
+	"long":      {7200, 43199},
+	"very_long": {43200, 0},
+}
+
+// ValidTimeLeft reports whether bucket is one of short, medium, long and
+// very_long
+func ValidTimeLeft(bucket string) bool {
+	_, ok := timeLeftBuckets[bucket]
+	return ok
+}
+
+// noBuyoutSortValue sorts auctions without a buyout after every real price,
+// which never exceeds the range of an unsigned 32-bit column
+const noBuyoutSortValue = 4294967296
+
+// auctionSort is an ORDER BY expression. Expressions never evaluate to NULL
+// so they can be compared in keyset pagination. Sorts on item template
+// fields have no expression, since items are joined in Go, and are sorted by
+// joinedAuction.sortKey instead.
+type auctionSort struct {
+	expr string
+	text bool
+	item bool
+}
+
+// auctionSorts maps sort names to their expression
+var auctionSorts = map[string]auctionSort{
+	"time":           {expr: "ah.time"},
+	"buyout":         {expr: fmt.Sprintf("IF(ah.buyoutprice > 0, ah.buyoutprice, %d)", noBuyoutSortValue)},
+	"unit_buyout":    {expr: fmt.Sprintf("COALESCE(IF(ah.buyoutprice > 0, %s, NULL), %d)", unitBuyoutSQL, noBuyoutSortValue)},
+	"bid":            {expr: currentBidSQL},
+	"count":          {expr: "COALESCE(ii.count, 0)"},
+	"quality":        {item: true},
+	"item_level":     {item: true},
+	"required_level": {item: true},
+	"name":           {text: true, item: true},
+	"seller":         {expr: "COALESCE(c.name, '')", text: true},
+}
+
+// ValidSort reports whether ListAuctions can sort by name
+func ValidSort(name string) bool {
+	_, ok := auctionSorts[name]
+	return ok
+}
+
+// IntRange is an inclusive range where either bound may be absent
+type IntRange struct {
+	Min *int
+	Max *int
+}
+
+func (r IntRange) contains(n int) bool {
+	return (r.Min == nil || n >= *r.Min) && (r.Max == nil || n <= *r.Max)
+}
+
+func (r IntRange) set() bool {
+	return r.Min != nil || r.Max != nil
+}
+
+// AuctionFilter narrows a listing of live auctions. Zero values match
+// everything.
+type AuctionFilter struct {
+	Search        string
+	ItemNameLike  string // SQL LIKE pattern matched against the item name
+	Seller        string
+	House         int
+	ItemEntry     int
+	Class         *int
+	Subclass      *int
+	InventoryType *int
+	Quality       IntRange
+	ItemLevel     IntRange
+	RequiredLevel IntRange
+	Count         IntRange
+	Buyout        IntRange
+	UnitBuyout    IntRange
+	Bid           IntRange
+	HasBid        *bool
+	TimeLeft      []string
+	// Account restricts the auctions to the characters of one game account
+	Account int
+	// Suffix is a term the name of the items' random property or suffix
+	// must contain
+	Suffix string
+}
+
+// AuctionQuery is a filtered, sorted page of auctions. A page starts either
+// after Cursor, when set, or at Page.
+type AuctionQuery struct {
+	Filter AuctionFilter
+	Sort   string
+	Desc   bool
+	Page   int
+	Limit  int
+	Cursor *Cursor
+	// Locale names the auctions' items. Search and item name filters and
+	// sorts use the translated names too. Empty means the default locale.
+	Locale string
+}
+
+// AuctionPage is one page of query results. NextCursor is empty on the last
+// page.
+type AuctionPage struct {
+	Auctions   []AuctionItem
+	Total      int
+	NextCursor string
+}
+
+// Cursor marks the last auction of a page so the next page can resume after
+// it without an OFFSET. It records the sort it was issued for, since the
+// position is meaningless under any other ordering.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d,omitempty"`
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+}
+
+// ErrInvalidCursor is returned by DecodeCursor for malformed cursors
+var ErrInvalidCursor = errors.New("invalid value for cursor")
+
+// Encode returns the opaque form of c handed to clients
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by Encode
+func DecodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	by, ok := auctionSorts[c.Sort]
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	if !by.text {
+		if _, err := strconv.ParseInt(c.Value, 10, 64); err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return &c, nil
+}
+
+// entryList renders item entries as a literal SQL list. Entries are integers,
+// so they are safe to inline, and inlining keeps long lists clear of the
+// placeholder limit.
+func entryList(entries []int) string {
+	sorted := append([]int(nil), entries...)
+	sort.Ints(sorted)
+	parts := make([]string, len(sorted))
+	for i, entry := range sorted {
+		parts[i] = strconv.Itoa(entry)
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// EscapeLike escapes the LIKE wildcards in s so it matches literally
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// where renders the filter as SQL conditions, each starting with AND, to
+// follow auctionFrom. Conditions on item template fields become a list of
+// the matching entries among items, which must hold every item listed when
+// the filter has such conditions or a Search. Searches and Suffix match the
+// names of random properties and suffixes in props.
+func (f AuctionFilter) where(items map[int]ItemTemplate, props RandomProperties) (string, []interface{}) {
+	var sb strings.Builder
+	var args []interface{}
+
+	add := func(cond string, condArgs ...interface{}) {
+		sb.WriteString(" AND ")
+		sb.WriteString(cond)
+		args = append(args, condArgs...)
+	}
+	addRange := func(expr string, r IntRange) {
+		if r.Min != nil {
+			add(expr+" >= ?", *r.Min)
+		}
+		if r.Max != nil {
+			add(expr+" <= ?", *r.Max)
+		}
+	}
+	match := f.matcher()
+
+	if f.Search != "" {
+		pattern := f.searchPattern()
+		var entries []int
+		for entry, item := range items {
+			if match.search(item.Name) {
+				entries = append(entries, entry)
+			}
+		}
+		conds := []string{"c.name LIKE ?"}
+		if len(entries) > 0 {
+			conds = append([]string{"ii.itemEntry IN " + entryList(entries)}, conds...)
+		}
+		if ids := props.matching(match.search); len(ids) > 0 {
+			conds = append(conds, "ii.randomPropertyId IN "+entryList(ids))
+		}
+		if len(conds) > 1 {
+			add("("+strings.Join(conds, " OR ")+")", pattern)
+		} else {
+			add(conds[0], pattern)
+		}
+	}
+	if f.Suffix != "" {
+		if ids := props.matching(match.suffix); len(ids) > 0 {
+			add("ii.randomPropertyId IN " + entryList(ids))
+		} else {
+			add("FALSE")
+		}
+	}
+	if f.hasItemConditions() {
+		var entries []int
+		for entry, item := range items {
+			if match.matchesItem(item) {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) > 0 {
+			add("ii.itemEntry IN " + entryList(entries))
+		} else {
+			add("FALSE")
+		}
+	}
+	if f.Seller != "" {
+		add("c.name = ?", f.Seller)
+	}
+	if f.House != 0 {
+		add("ah.houseid = ?", f.House)
+	}
+	if f.Account != 0 {
+		add("c.account = ?", f.Account)
+	}
+	if f.ItemEntry != 0 {
+		add("ii.itemEntry = ?", f.ItemEntry)
+	}
+	addRange("ii.count", f.Count)
+	addRange("ah.buyoutprice", f.Buyout)
+	if f.UnitBuyout.set() {
+		add("ah.buyoutprice > 0")
+		addRange(unitBuyoutSQL, f.UnitBuyout)
+	}
+	addRange(currentBidSQL, f.Bid)
+	if f.HasBid != nil {
+		if *f.HasBid {
+			add("ah.lastbid > 0")
+		} else {
+			add("ah.lastbid = 0")
+		}
+	}
+	if len(f.TimeLeft) > 0 {
+		conds := make([]string, 0, len(f.TimeLeft))
+		for _, name := range f.TimeLeft {
+			bucket, ok := timeLeftBuckets[name]
+			if !ok {
+				continue
+			}
+			if bucket.Max == 0 {
+				conds = append(conds, fmt.Sprintf("%s >= %d", timeLeftSQL, bucket.Min))
+			} else {
+				conds = append(conds, fmt.Sprintf("%s BETWEEN %d AND %d", timeLeftSQL, bucket.Min, bucket.Max))
+			}
+		}
+		if len(conds) > 0 {
+			add("(" + strings.Join(conds, " OR ") + ")")
+		}
+	}
+
+	return sb.String(), args
+}
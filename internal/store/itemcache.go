@@ -0,0 +1,155 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ItemCacheStatus describes the item templates held by an ItemCache
+type ItemCacheStatus struct {
+	Items int `json:"items"`
+	// Names counts translated item names, across every locale
+	Names int `json:"names"`
+	// LoadedAt is zero until the first successful refresh
+	LoadedAt time.Time `json:"loaded_at"`
+}
+
+// ItemCache keeps every item template and translated name in memory, so
+// auctions are decorated without querying the world database. Until the
+// first Refresh succeeds it passes lookups through to the world database.
+// Templates changed in the world database show up after the next Refresh.
+// Random properties are loaded for each locale when first asked for, and
+// again after every Refresh.
+type ItemCache struct {
+	world     ItemSource
+	load      func(ctx context.Context) ([]ItemTemplate, error)
+	loadNames func(ctx context.Context) (map[string]map[int]string, error)
+
+	mu       sync.RWMutex
+	items    map[int]ItemTemplate
+	names    map[string]map[int]string
+	loadedAt time.Time
+	// properties holds random properties by locale
+	properties map[string]RandomProperties
+}
+
+var (
+	_ ItemSource  = (*ItemCache)(nil)
+	_ ItemCatalog = (*ItemCache)(nil)
+)
+
+// NewItemCache returns an empty cache of the item templates of world. Call
+// Refresh to load it.
+func NewItemCache(world *World) *ItemCache {
+	return &ItemCache{world: world, load: world.AllItems, loadNames: world.AllItemNames}
+}
+
+// Refresh loads every item template and translated name, replacing the
+// cached copy once both tables have been read. On failure the previous copy
+// is kept.
+func (c *ItemCache) Refresh(ctx context.Context) (ItemCacheStatus, error) {
+	items, err := c.load(ctx)
+	if err != nil {
+		return c.Status(), err
+	}
+	names, err := c.loadNames(ctx)
+	if err != nil {
+		return c.Status(), err
+	}
+
+	byEntry := make(map[int]ItemTemplate, len(items))
+	for _, item := range items {
+		byEntry[item.Entry] = item
+	}
+
+	c.mu.Lock()
+	c.items = byEntry
+	c.names = names
+	c.loadedAt = time.Now()
+	c.properties = nil
+	c.mu.Unlock()
+	return c.Status(), nil
+}
+
+// Status describes the templates currently loaded
+func (c *ItemCache) Status() ItemCacheStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	status := ItemCacheStatus{Items: len(c.items), LoadedAt: c.loadedAt}
+	for _, names := range c.names {
+		status.Names += len(names)
+	}
+	return status
+}
+
+// cached returns the loaded templates and names, or nil before the first
+// refresh. The maps are replaced, never modified, so they may be read
+// without the lock.
+func (c *ItemCache) cached() (map[int]ItemTemplate, map[string]map[int]string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.items, c.names
+}
+
+// Items returns the templates of the given entries, keyed by entry. Entries
+// without a template are left out.
+func (c *ItemCache) Items(ctx context.Context, entries []int) (map[int]ItemTemplate, error) {
+	items, _ := c.cached()
+	if items == nil {
+		return c.world.Items(ctx, entries)
+	}
+	found := make(map[int]ItemTemplate, len(entries))
+	for _, entry := range entries {
+		if item, ok := items[entry]; ok {
+			found[entry] = item
+		}
+	}
+	return found, nil
+}
+
+// ItemNames returns the names of the given entries translated into locale,
+// keyed by entry. Untranslated entries are left out.
+func (c *ItemCache) ItemNames(ctx context.Context, locale string, entries []int) (map[int]string, error) {
+	items, names := c.cached()
+	if items == nil {
+		return c.world.ItemNames(ctx, locale, entries)
+	}
+	found := make(map[int]string)
+	for _, entry := range entries {
+		if name, ok := names[locale][entry]; ok {
+			found[entry] = name
+		}
+	}
+	return found, nil
+}
+
+// Houses lists the auction houses, which are few enough to read from the
+// world database every time
+func (c *ItemCache) Houses(ctx context.Context) ([]AuctionHouse, error) {
+	return c.world.Houses(ctx)
+}
+
+// RandomProperties returns every random property and suffix, named in
+// locale
+func (c *ItemCache) RandomProperties(ctx context.Context, locale string) (RandomProperties, error) {
+	locale = dbcLocale(locale)
+	c.mu.RLock()
+	props, ok := c.properties[locale]
+	c.mu.RUnlock()
+	if ok {
+		return props, nil
+	}
+
+	props, err := c.world.RandomProperties(ctx, locale)
+	if err != nil {
+		return props, err
+	}
+	c.mu.Lock()
+	if c.properties == nil {
+		c.properties = make(map[string]RandomProperties)
+	}
+	c.properties[locale] = props
+	c.mu.Unlock()
+	return props, nil
+}
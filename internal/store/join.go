@@ -0,0 +1,312 @@
+package store
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// joinedAuction is an auction together with the template of its item, which
+// lives in the world database and so is joined in Go rather than in SQL
+type joinedAuction struct {
+	AuctionItem
+	item    ItemTemplate
+	hasItem bool
+	// account owns the seller, when known
+	account int
+}
+
+// joinItem copies the item fields of an auction from items and resolves its
+// random property or suffix in props, appending the suffix to the item name
+func joinItem(a AuctionItem, items map[int]ItemTemplate, props RandomProperties) joinedAuction {
+	item, ok := items[a.ItemEntry]
+	a.ItemName, a.Quality, a.ItemLevel, a.SellPrice = "Unknown Item", 0, 0, 0
+	a.Suffix, a.RandomStats, a.RandomResistances = "", nil, nil
+	if ok {
+		a.ItemName, a.Quality, a.ItemLevel, a.SellPrice = item.Name, item.Quality, item.ItemLevel, item.SellPrice
+		if bonus, found := props.resolve(a.RandomPropertyID, item); found {
+			a.Suffix, a.RandomStats, a.RandomResistances = bonus.Suffix, bonus.Stats, bonus.Resistances
+			if bonus.Suffix != "" {
+				a.ItemName += " " + bonus.Suffix
+			}
+		}
+	}
+	return joinedAuction{AuctionItem: a, item: item, hasItem: ok}
+}
+
+// localize replaces the names in items with their translations from names
+func localize(items map[int]ItemTemplate, names map[int]string) {
+	for entry, name := range names {
+		if item, ok := items[entry]; ok {
+			item.Name = name
+			items[entry] = item
+		}
+	}
+}
+
+// translated reports whether locale needs item names other than the
+// item_template ones
+func translated(locale string) bool {
+	return locale != "" && locale != wow.DefaultLocale
+}
+
+// hasItemConditions reports whether the filter tests item template fields,
+// other than through Search
+func (f AuctionFilter) hasItemConditions() bool {
+	return f.ItemNameLike != "" || f.Class != nil || f.Subclass != nil || f.InventoryType != nil ||
+		f.Quality.set() || f.ItemLevel.set() || f.RequiredLevel.set()
+}
+
+// auctionMatcher is a filter with its LIKE patterns compiled, to test many
+// items and auctions against
+type auctionMatcher struct {
+	AuctionFilter
+	search, suffix, itemName func(string) bool
+}
+
+// matcher compiles the LIKE patterns of the filter
+func (f AuctionFilter) matcher() auctionMatcher {
+	m := auctionMatcher{AuctionFilter: f}
+	if f.Search != "" {
+		m.search = likeMatcher(f.searchPattern())
+	}
+	if f.Suffix != "" {
+		m.suffix = likeMatcher(f.suffixPattern())
+	}
+	if f.ItemNameLike != "" {
+		m.itemName = likeMatcher(f.ItemNameLike)
+	}
+	return m
+}
+
+// matchesItem reports whether an item passes the item template conditions
+// of the filter, see hasItemConditions
+func (f auctionMatcher) matchesItem(item ItemTemplate) bool {
+	if f.ItemNameLike != "" && !f.itemName(item.Name) {
+		return false
+	}
+	if f.Class != nil && item.Class != *f.Class {
+		return false
+	}
+	if f.Subclass != nil && item.Subclass != *f.Subclass {
+		return false
+	}
+	if f.InventoryType != nil && item.InventoryType != *f.InventoryType {
+		return false
+	}
+	return f.Quality.contains(item.Quality) && f.ItemLevel.contains(item.ItemLevel) &&
+		f.RequiredLevel.contains(item.RequiredLevel)
+}
+
+// searchPattern is the LIKE pattern Search matches item and seller names
+// against
+func (f AuctionFilter) searchPattern() string {
+	return "%" + f.Search + "%"
+}
+
+// suffixPattern is the LIKE pattern Suffix matches the names of random
+// properties and suffixes against
+func (f AuctionFilter) suffixPattern() string {
+	return "%" + EscapeLike(f.Suffix) + "%"
+}
+
+func (a joinedAuction) currentBid() int {
+	if a.LastBid > 0 {
+		return a.LastBid
+	}
+	return a.StartBid
+}
+
+// matches evaluates the whole filter in Go, the way where renders it in SQL
+func (a joinedAuction) matches(f auctionMatcher, now time.Time) bool {
+	if f.Search != "" {
+		if !a.hasItem || !f.search(a.item.Name) {
+			if (a.Suffix == "" || !f.search(a.Suffix)) && (a.OwnerName == "Unknown" || !f.search(a.OwnerName)) {
+				return false
+			}
+		}
+	}
+	if f.Suffix != "" && (a.Suffix == "" || !f.suffix(a.Suffix)) {
+		return false
+	}
+	if f.hasItemConditions() && (!a.hasItem || !f.matchesItem(a.item)) {
+		return false
+	}
+	if f.Seller != "" && !strings.EqualFold(a.OwnerName, f.Seller) {
+		return false
+	}
+	if f.House != 0 && a.HouseID != f.House {
+		return false
+	}
+	if f.Account != 0 && a.account != f.Account {
+		return false
+	}
+	if f.ItemEntry != 0 && a.ItemEntry != f.ItemEntry {
+		return false
+	}
+	if !f.Count.contains(a.Count) || !f.Buyout.contains(a.BuyoutPrice) || !f.Bid.contains(a.currentBid()) {
+		return false
+	}
+	if f.UnitBuyout.set() && (a.BuyoutPrice == 0 || a.Count == 0 || !f.UnitBuyout.contains(a.UnitBuyout)) {
+		return false
+	}
+	if f.HasBid != nil && *f.HasBid != (a.LastBid > 0) {
+		return false
+	}
+	if len(f.TimeLeft) > 0 {
+		left := a.Time - int(now.Unix())
+		found := false
+		for _, name := range f.TimeLeft {
+			bucket := timeLeftBuckets[name]
+			if left >= bucket.Min && (bucket.Max == 0 || left <= bucket.Max) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// sortKey mirrors the auctionSorts expressions. Text keys are folded to lower
+// case to match MySQL's collation.
+func (a joinedAuction) sortKey(name string) (int64, string) {
+	switch name {
+	case "buyout":
+		if a.BuyoutPrice > 0 {
+			return int64(a.BuyoutPrice), ""
+		}
+		return noBuyoutSortValue, ""
+	case "unit_buyout":
+		if a.BuyoutPrice > 0 && a.Count > 0 {
+			return int64(a.UnitBuyout), ""
+		}
+		return noBuyoutSortValue, ""
+	case "bid":
+		return int64(a.currentBid()), ""
+	case "count":
+		return int64(a.Count), ""
+	case "quality":
+		return int64(a.item.Quality), ""
+	case "item_level":
+		return int64(a.item.ItemLevel), ""
+	case "required_level":
+		return int64(a.item.RequiredLevel), ""
+	case "name":
+		return 0, strings.ToLower(a.item.Name)
+	case "seller":
+		if a.OwnerName == "Unknown" {
+			return 0, ""
+		}
+		return 0, strings.ToLower(a.OwnerName)
+	default:
+		return int64(a.Time), ""
+	}
+}
+
+// compareKeys orders two sort keys, then auction IDs
+func compareKeys(aNum int64, aText string, aID int, bNum int64, bText string, bID int) int {
+	switch {
+	case aNum != bNum:
+		if aNum < bNum {
+			return -1
+		}
+		return 1
+	case aText != bText:
+		return strings.Compare(aText, bText)
+	case aID != bID:
+		if aID < bID {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// pageAuctions sorts auctions that already passed the filter and returns the
+// page q asks for, with the same cursors ListAuctions issues in SQL
+func pageAuctions(matched []joinedAuction, q AuctionQuery) AuctionPage {
+	page := AuctionPage{Auctions: []AuctionItem{}, Total: len(matched)}
+	by := auctionSorts[q.Sort]
+
+	direction := 1
+	if q.Desc {
+		direction = -1
+	}
+	sortAuctions(matched, func(a, b joinedAuction) int {
+		aNum, aText := a.sortKey(q.Sort)
+		bNum, bText := b.sortKey(q.Sort)
+		return direction * compareKeys(aNum, aText, a.ID, bNum, bText, b.ID)
+	})
+
+	start := max(q.Page-1, 0) * q.Limit
+	if q.Cursor != nil {
+		var cursorNum int64
+		cursorText := q.Cursor.Value
+		if !by.text {
+			cursorNum, _ = strconv.ParseInt(q.Cursor.Value, 10, 64)
+			cursorText = ""
+		}
+		start = len(matched)
+		for i, a := range matched {
+			aNum, aText := a.sortKey(q.Sort)
+			if direction*compareKeys(aNum, aText, a.ID, cursorNum, strings.ToLower(cursorText), q.Cursor.ID) > 0 {
+				start = i
+				break
+			}
+		}
+	}
+
+	for i := start; i < len(matched); i++ {
+		if len(page.Auctions) == q.Limit {
+			last := matched[i-1]
+			num, text := last.sortKey(q.Sort)
+			value := text
+			if !by.text {
+				value = strconv.FormatInt(num, 10)
+			}
+			page.NextCursor = Cursor{Sort: q.Sort, Desc: q.Desc, Value: value, ID: last.ID}.Encode()
+			break
+		}
+		page.Auctions = append(page.Auctions, matched[i].AuctionItem)
+	}
+	return page
+}
+
+func sortAuctions(auctions []joinedAuction, compare func(a, b joinedAuction) int) {
+	sort.SliceStable(auctions, func(i, j int) bool { return compare(auctions[i], auctions[j]) < 0 })
+}
+
+// likeMatcher compiles a SQL LIKE pattern into a function reporting whether
+// a string matches it, ignoring case
+func likeMatcher(pattern string) func(string) bool {
+	var re strings.Builder
+	re.WriteString(`(?is)^`)
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			re.WriteString(`.*`)
+		case r == '_':
+			re.WriteString(`.`)
+		default:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	re.WriteString(`$`)
+	compiled, err := regexp.Compile(re.String())
+	if err != nil {
+		return func(string) bool { return false }
+	}
+	return compiled.MatchString
+}
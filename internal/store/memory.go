@@ -0,0 +1,1241 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Memory is an in-memory implementation of every repository, for tests and
+// local development. It follows MySQL's case-insensitive string comparison.
+// Set Err to make every method fail with it.
+type Memory struct {
+	// Now returns the current time; it defaults to time.Now
+	Now func() time.Time
+	Err error
+
+	mu         sync.Mutex
+	auctions   []AuctionItem
+	items      map[int]ItemTemplate
+	names      map[string]map[int]string
+	houses     []AuctionHouse
+	snapshots  []memorySnapshot
+	rules      map[int]AlertRule
+	nextRuleID int
+	deliveries map[int]map[int]time.Time
+	// itemOwners holds the items of ended auctions, as RemoveAuction left
+	// them
+	itemOwners map[int]int
+	market     map[int]MarketPrice
+	// enchantments holds item_instance.enchantments by item GUID
+	enchantments map[int]string
+	tooltips     map[int]ItemTooltip
+	// enchantmentNames stands in for spellitemenchantment_dbc
+	enchantmentNames map[int]string
+	properties       RandomProperties
+	professions      []Profession
+	// recipes holds the recipes of each profession, by skill line
+	recipes map[int][]Recipe
+	yields  Yields
+	sources map[int]ItemSources
+	// characters maps character GUIDs to their accounts
+	characters map[int]int
+	accounts   map[int]Account
+	gmLevels   map[[2]int]int
+	ahbot      map[int]AHBotConfig
+	disabled   map[int]bool
+	classes    map[[2]int]AuctionatorClass
+	// auctionatorDisabled holds mod_auctionator's disabled items, apart
+	// from mod_auctionhousebot's in disabled
+	auctionatorDisabled map[int]bool
+	audit               []AuditEntry
+}
+
+var (
+	_ AuctionRepository     = (*Memory)(nil)
+	_ HistoryRepository     = (*Memory)(nil)
+	_ AlertRepository       = (*Memory)(nil)
+	_ AHBotRepository       = (*Memory)(nil)
+	_ AuctionatorRepository = (*Memory)(nil)
+	_ TooltipRepository     = (*Memory)(nil)
+	_ CraftingRepository    = (*Memory)(nil)
+	_ YieldRepository       = (*Memory)(nil)
+	_ SourceRepository      = (*Memory)(nil)
+	_ AuditRepository       = (*Memory)(nil)
+	_ ItemSource            = (*Memory)(nil)
+)
+
+type memorySnapshot struct {
+	takenAt time.Time
+	prices  map[int]PricePoint
+}
+
+// NewMemory returns an empty store
+func NewMemory() *Memory {
+	return &Memory{
+		Now:        time.Now,
+		items:      make(map[int]ItemTemplate),
+		names:      make(map[string]map[int]string),
+		rules:      make(map[int]AlertRule),
+		deliveries: make(map[int]map[int]time.Time),
+		itemOwners: make(map[int]int),
+		market:     make(map[int]MarketPrice),
+		characters: make(map[int]int),
+		accounts:   make(map[int]Account),
+		gmLevels:   make(map[[2]int]int),
+		ahbot:      make(map[int]AHBotConfig),
+		disabled:   make(map[int]bool),
+		classes:    make(map[[2]int]AuctionatorClass),
+
+		auctionatorDisabled: make(map[int]bool),
+		enchantments:        make(map[int]string),
+		tooltips:            make(map[int]ItemTooltip),
+		enchantmentNames:    make(map[int]string),
+		properties:          RandomProperties{Properties: make(map[int]RandomProperty), Points: make(map[int]PropertyPoints)},
+		recipes:             make(map[int][]Recipe),
+		yields:              NewYields(),
+		sources:             make(map[int]ItemSources),
+	}
+}
+
+// AddItem adds an item template, which auctions with its entry join against
+func (m *Memory) AddItem(item ItemTemplate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[item.Entry] = item
+}
+
+// AddItemName adds the translation of an item's name into locale
+func (m *Memory) AddItemName(entry int, locale, name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.names[locale] == nil {
+		m.names[locale] = make(map[int]string)
+	}
+	m.names[locale][entry] = name
+}
+
+// AddAuction lists an auction. Only the fields stored by the game server are
+// read; item details come from AddItem and derived fields are recomputed.
+// OwnerName stands in for the characters table.
+func (m *Memory) AddAuction(auction AuctionItem) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.auctions = append(m.auctions, auction)
+}
+
+// UpdateAuction replaces the auction with the same ID, as when a bid is
+// placed on it
+func (m *Memory) UpdateAuction(auction AuctionItem) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, a := range m.auctions {
+		if a.ID == auction.ID {
+			m.auctions[i] = auction
+		}
+	}
+}
+
+// RemoveAuction ends an auction and leaves its item with owner: the seller
+// when it was cancelled or expired, the buyer when it sold. An owner of 0
+// deletes the item.
+func (m *Memory) RemoveAuction(id, owner int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, a := range m.auctions {
+		if a.ID != id {
+			continue
+		}
+		if owner != 0 {
+			m.itemOwners[a.ItemGUID] = owner
+		}
+		m.auctions = append(m.auctions[:i], m.auctions[i+1:]...)
+		return
+	}
+}
+
+// SetEnchantments sets the enchantments of an item instance, in the format of
+// item_instance.enchantments
+func (m *Memory) SetEnchantments(itemGUID int, enchantments string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enchantments[itemGUID] = enchantments
+}
+
+// AddItemTooltip adds the tooltip of an item, with its spells and socket
+// bonus described as they would be in the DBC tables. Its DPS is computed.
+func (m *Memory) AddItemTooltip(tooltip ItemTooltip) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tooltip.DPS = weaponDPS(tooltip.Damage, tooltip.Delay)
+	m.tooltips[tooltip.Entry] = tooltip
+}
+
+// AddEnchantment names an enchantment of spellitemenchantment_dbc
+func (m *Memory) AddEnchantment(id int, name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enchantmentNames[id] = name
+}
+
+// AddRandomProperty adds a random property, or a random suffix when its ID
+// is negative, as item_instance.randomPropertyId refers to them. Suffix
+// names are the same in every locale.
+func (m *Memory) AddRandomProperty(property RandomProperty) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.properties.Properties[property.ID] = property
+}
+
+// AddPropertyPoints sets the property points of an item level, as in
+// randproppoints_dbc
+func (m *Memory) AddPropertyPoints(itemLevel int, points PropertyPoints) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.properties.Points[itemLevel] = points
+}
+
+// AddProfession adds a profession of skillline_dbc. Its name is the same in
+// every locale.
+func (m *Memory) AddProfession(profession Profession) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.professions = append(m.professions, profession)
+}
+
+// AddRecipe adds a recipe to the profession with the skill line skill. Only
+// the entries and counts of its items are read; names and qualities come
+// from AddItem and AddItemName.
+func (m *Memory) AddRecipe(skill int, recipe Recipe) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recipes[skill] = append(m.recipes[skill], recipe)
+}
+
+// AddDisenchant makes an item disenchantable, into the entry d.ID of the
+// disenchant loot added by AddLoot
+func (m *Memory) AddDisenchant(entry int, d Disenchant) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.yields.Disenchants[entry] = d
+}
+
+// AddLoot adds a row to an entry of the loot template of a yield method, or
+// of reference_loot_template when method is "reference"
+func (m *Memory) AddLoot(method string, entry int, row LootRow) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	loot := map[string]map[int][]LootRow{
+		YieldDisenchant:  m.yields.Disenchant,
+		YieldMilling:     m.yields.Milling,
+		YieldProspecting: m.yields.Prospecting,
+		"reference":      m.yields.Reference,
+	}[method]
+	loot[entry] = append(loot[entry], row)
+}
+
+// AddItemSources sets the sources of the item sources.Entry, as the world
+// database's templates would name them
+func (m *Memory) AddItemSources(sources ItemSources) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sources.Drops = append([]DropSource{}, sources.Drops...)
+	sources.Vendors = append([]VendorSource{}, sources.Vendors...)
+	sources.Quests = append([]QuestSource{}, sources.Quests...)
+	sortSources(&sources)
+	m.sources[sources.Entry] = sources
+}
+
+// AddMarketPrice sets an item's mod_auctionator market price
+func (m *Memory) AddMarketPrice(price MarketPrice) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.market[price.Entry] = price
+}
+
+// AddAHBotConfig sets the mod_auctionhousebot configuration of an auction
+// house
+func (m *Memory) AddAHBotConfig(config AHBotConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ahbot[config.AuctionHouse] = config
+}
+
+// AddAuctionatorClass sets mod_auctionator's configuration of an item class
+// and subclass
+func (m *Memory) AddAuctionatorClass(config AuctionatorClass) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.classes[[2]int{config.Class, config.Subclass}] = config
+}
+
+// AddCharacter puts the character with guid on a game account, which the
+// Account filter matches sellers by
+func (m *Memory) AddCharacter(guid, account int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.characters[guid] = account
+}
+
+// AddAccount adds a game account of the auth database. Its GMLevel applies
+// on every realm.
+func (m *Memory) AddAccount(account Account) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accounts[account.ID] = account
+}
+
+// AddGMLevel gives an account a gmlevel on one realm only
+func (m *Memory) AddGMLevel(account, realm, level int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gmLevels[[2]int{account, realm}] = level
+}
+
+// AddHouse adds an auction house
+func (m *Memory) AddHouse(house AuctionHouse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.houses = append(m.houses, house)
+}
+
+// localItems returns the item templates, named in locale
+func (m *Memory) localItems(locale string) map[int]ItemTemplate {
+	if !translated(locale) {
+		return m.items
+	}
+	items := make(map[int]ItemTemplate, len(m.items))
+	for entry, item := range m.items {
+		items[entry] = item
+	}
+	localize(items, m.names[locale])
+	return items
+}
+
+// liveAuctions returns the unexpired auctions joined with their items named
+// in locale and their market prices, the way MySQL joins them
+func (m *Memory) liveAuctions(locale string) []joinedAuction {
+	now := m.Now()
+	items := m.localItems(locale)
+	var live []joinedAuction
+	for _, a := range m.auctions {
+		if int64(a.Time) <= now.Unix() {
+			continue
+		}
+		if a.OwnerName == "" {
+			a.OwnerName = "Unknown"
+		}
+		fillDerived(&a, now)
+		joinMarketPrice(&a, m.market)
+		joined := joinItem(a, items, m.properties)
+		joined.account = m.characters[a.ItemOwner]
+		live = append(live, joined)
+	}
+	return live
+}
+
+func (m *Memory) ListAuctions(ctx context.Context, q AuctionQuery) (AuctionPage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return AuctionPage{Auctions: []AuctionItem{}}, m.Err
+	}
+	if _, ok := auctionSorts[q.Sort]; !ok {
+		return AuctionPage{Auctions: []AuctionItem{}}, fmt.Errorf("unknown sort %q", q.Sort)
+	}
+
+	now := m.Now()
+	match := q.Filter.matcher()
+	var matched []joinedAuction
+	for _, a := range m.liveAuctions(q.Locale) {
+		if a.matches(match, now) {
+			matched = append(matched, a)
+		}
+	}
+	return pageAuctions(matched, q), nil
+}
+
+func (m *Memory) Stats(ctx context.Context, house int) (AuctionHouseStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var stats AuctionHouseStats
+	if m.Err != nil {
+		return stats, m.Err
+	}
+	owners := make(map[int]bool)
+	items := make(map[int]bool)
+	for _, a := range m.liveAuctions("") {
+		if house != 0 && a.HouseID != house {
+			continue
+		}
+		stats.TotalItems++
+		stats.TotalValue += a.BuyoutPrice
+		if a.LastBid > 0 {
+			stats.ActiveBids++
+		}
+		owners[a.ItemOwner] = true
+		items[a.ItemEntry] = true
+	}
+	stats.UniqueOwners = len(owners)
+	stats.UniqueItems = len(items)
+	return stats, nil
+}
+
+func (m *Memory) Sellers(ctx context.Context, house int, name string) ([]Seller, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	byOwner := make(map[int]*Seller)
+	items := make(map[int]map[int]bool)
+	var order []int
+	for _, a := range m.liveAuctions("") {
+		if a.OwnerName == "Unknown" || (house != 0 && a.HouseID != house) {
+			continue
+		}
+		if name != "" && !strings.EqualFold(a.OwnerName, name) {
+			continue
+		}
+		seller, ok := byOwner[a.ItemOwner]
+		if !ok {
+			seller = &Seller{Name: a.OwnerName}
+			byOwner[a.ItemOwner] = seller
+			items[a.ItemOwner] = make(map[int]bool)
+			order = append(order, a.ItemOwner)
+		}
+		seller.TotalAuctions++
+		seller.TotalValue += a.BuyoutPrice
+		items[a.ItemOwner][a.ItemEntry] = true
+	}
+
+	sellers := []Seller{}
+	for _, owner := range order {
+		seller := *byOwner[owner]
+		seller.UniqueItems = len(items[owner])
+		sellers = append(sellers, seller)
+	}
+	sort.SliceStable(sellers, func(i, j int) bool {
+		if sellers[i].TotalAuctions != sellers[j].TotalAuctions {
+			return sellers[i].TotalAuctions > sellers[j].TotalAuctions
+		}
+		return sellers[i].TotalValue > sellers[j].TotalValue
+	})
+	return sellers, nil
+}
+
+func (m *Memory) Houses(ctx context.Context) ([]AuctionHouse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	houses := append([]AuctionHouse{}, m.houses...)
+	sort.Slice(houses, func(i, j int) bool { return houses[i].ID < houses[j].ID })
+	return houses, nil
+}
+
+func (m *Memory) Item(ctx context.Context, entry int, locale string) (ItemTemplate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return ItemTemplate{}, m.Err
+	}
+	item, ok := m.localItems(locale)[entry]
+	if !ok {
+		return item, ErrNotFound
+	}
+	return item, nil
+}
+
+func (m *Memory) Items(ctx context.Context, entries []int) (map[int]ItemTemplate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	items := make(map[int]ItemTemplate, len(entries))
+	for _, entry := range entries {
+		if item, ok := m.items[entry]; ok {
+			items[entry] = item
+		}
+	}
+	return items, nil
+}
+
+func (m *Memory) ItemNames(ctx context.Context, locale string, entries []int) (map[int]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	names := make(map[int]string)
+	for _, entry := range entries {
+		if name, ok := m.names[locale][entry]; ok {
+			names[entry] = name
+		}
+	}
+	return names, nil
+}
+
+func (m *Memory) RandomProperties(ctx context.Context, locale string) (RandomProperties, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return RandomProperties{}, m.Err
+	}
+	return m.properties, nil
+}
+
+// AllItemNames returns every translated item name, the way
+// World.AllItemNames does
+func (m *Memory) AllItemNames(ctx context.Context) (map[string]map[int]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	names := make(map[string]map[int]string, len(m.names))
+	for locale, byEntry := range m.names {
+		names[locale] = make(map[int]string, len(byEntry))
+		for entry, name := range byEntry {
+			names[locale][entry] = name
+		}
+	}
+	return names, nil
+}
+
+// AllItems returns every item template, the way World.AllItems does
+func (m *Memory) AllItems(ctx context.Context) ([]ItemTemplate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	items := make([]ItemTemplate, 0, len(m.items))
+	for _, item := range m.items {
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (m *Memory) ItemAuctions(ctx context.Context, entry, house int, locale string) ([]AuctionItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var matched []joinedAuction
+	for _, a := range m.liveAuctions(locale) {
+		if a.ItemEntry == entry && (house == 0 || a.HouseID == house) {
+			matched = append(matched, a)
+		}
+	}
+	sortAuctions(matched, func(a, b joinedAuction) int {
+		return compareKeys(int64(a.Time), "", a.ID, int64(b.Time), "", b.ID)
+	})
+
+	auctions := []AuctionItem{}
+	for _, a := range matched {
+		auctions = append(auctions, a.AuctionItem)
+	}
+	return auctions, nil
+}
+
+func (m *Memory) Economy(ctx context.Context) ([]EconomyStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	type key struct{ house, quality int }
+	groups := make(map[key]*EconomyStats)
+	sellers := make(map[key]map[int]bool)
+	for _, a := range m.liveAuctions("") {
+		k := key{a.HouseID, a.Quality}
+		e, ok := groups[k]
+		if !ok {
+			e = &EconomyStats{House: a.HouseID, Quality: a.Quality}
+			groups[k] = e
+			sellers[k] = make(map[int]bool)
+		}
+		e.Listings++
+		e.BuyoutValue += a.BuyoutPrice
+		if a.LastBid > 0 {
+			e.ActiveBids++
+		}
+		sellers[k][a.ItemOwner] = true
+	}
+
+	var result []EconomyStats
+	for k, e := range groups {
+		e.UniqueSellers = len(sellers[k])
+		result = append(result, *e)
+	}
+	sortEconomy(result)
+	return result, nil
+}
+
+func (m *Memory) BuyoutListings(ctx context.Context) (map[int][]UnitListing, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	byEntry := make(map[int][]UnitListing)
+	for _, a := range m.liveAuctions("") {
+		if a.BuyoutPrice > 0 && a.Count > 0 {
+			byEntry[a.ItemEntry] = append(byEntry[a.ItemEntry], UnitListing{
+				Count:   a.Count,
+				Buyout:  a.BuyoutPrice,
+				PerUnit: a.UnitBuyout,
+			})
+		}
+	}
+	return byEntry, nil
+}
+
+func (m *Memory) Distribution(ctx context.Context) ([]HouseDistribution, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var counts []listingCount
+	for _, a := range m.liveAuctions("") {
+		counts = append(counts, listingCount{house: a.HouseID, entry: a.ItemEntry, listings: 1})
+	}
+	return distribute(counts, m.items), nil
+}
+
+func (m *Memory) LiveAuctions(ctx context.Context, locale string) ([]AuctionItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	auctions := []AuctionItem{}
+	for _, a := range m.liveAuctions(locale) {
+		auctions = append(auctions, a.AuctionItem)
+	}
+	return auctions, nil
+}
+
+func (m *Memory) LocalizeAuctions(ctx context.Context, auctions []AuctionItem, locale string) ([]AuctionItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	items := m.localItems(locale)
+	localized := make([]AuctionItem, len(auctions))
+	for i, a := range auctions {
+		localized[i] = joinItem(a, items, m.properties).AuctionItem
+	}
+	return localized, nil
+}
+
+// ItemOwners finds listed items with their sellers and the items of ended
+// auctions where RemoveAuction left them
+func (m *Memory) ItemOwners(ctx context.Context, itemGUIDs []int) (map[int]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	all := make(map[int]int, len(m.itemOwners)+len(m.auctions))
+	for guid, owner := range m.itemOwners {
+		all[guid] = owner
+	}
+	for _, a := range m.auctions {
+		all[a.ItemGUID] = a.ItemOwner
+	}
+	owners := make(map[int]int)
+	for _, guid := range itemGUIDs {
+		if owner, ok := all[guid]; ok {
+			owners[guid] = owner
+		}
+	}
+	return owners, nil
+}
+
+func (m *Memory) AuctionInstance(ctx context.Context, id int, locale string) (ItemInstance, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return ItemInstance{}, m.Err
+	}
+	for _, a := range m.liveAuctions(locale) {
+		if a.ID == id {
+			instance := ItemInstance{
+				AuctionID:        a.ID,
+				ItemGUID:         a.ItemGUID,
+				ItemEntry:        a.ItemEntry,
+				Count:            a.Count,
+				Enchantments:     parseEnchantments(m.enchantments[a.ItemGUID]),
+				RandomPropertyID: a.RandomPropertyID,
+			}
+			if bonus, ok := m.properties.resolve(a.RandomPropertyID, a.item); ok {
+				instance.Random = &bonus
+			}
+			return instance, nil
+		}
+	}
+	return ItemInstance{}, ErrNotFound
+}
+
+func (m *Memory) MarketPrices(ctx context.Context) (map[int]MarketPrice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	prices := make(map[int]MarketPrice, len(m.market))
+	for entry, p := range m.market {
+		prices[entry] = p
+	}
+	return prices, nil
+}
+
+func (m *Memory) SaveSnapshot(ctx context.Context, takenAt time.Time, auctions int, prices map[int]PricePoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+	copied := make(map[int]PricePoint, len(prices))
+	for entry, p := range prices {
+		copied[entry] = p
+	}
+	m.snapshots = append(m.snapshots, memorySnapshot{takenAt: takenAt.UTC(), prices: copied})
+	return nil
+}
+
+func (m *Memory) PruneSnapshots(ctx context.Context, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+	kept := m.snapshots[:0]
+	for _, s := range m.snapshots {
+		if !s.takenAt.Before(before) {
+			kept = append(kept, s)
+		}
+	}
+	m.snapshots = kept
+	return nil
+}
+
+func (m *Memory) ItemHistory(ctx context.Context, entry int, since time.Time) ([]PricePoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	history := []PricePoint{}
+	for _, s := range m.snapshots {
+		p, ok := s.prices[entry]
+		if !ok || s.takenAt.Before(since) {
+			continue
+		}
+		p.Time = s.takenAt
+		history = append(history, p)
+	}
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Time.Before(history[j].Time) })
+	return history, nil
+}
+
+func (m *Memory) MedianPrices(ctx context.Context, since time.Time) (map[int]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	byEntry := make(map[int][]int)
+	for _, s := range m.snapshots {
+		if s.takenAt.Before(since) {
+			continue
+		}
+		for entry, p := range s.prices {
+			if p.MedianBuyout > 0 {
+				byEntry[entry] = append(byEntry[entry], p.MedianBuyout)
+			}
+		}
+	}
+	medians := make(map[int]int, len(byEntry))
+	for entry, prices := range byEntry {
+		medians[entry] = median(prices)
+	}
+	return medians, nil
+}
+
+func (m *Memory) AlertRules(ctx context.Context, enabledOnly bool) ([]AlertRule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	rules := []AlertRule{}
+	for _, rule := range m.rules {
+		if !enabledOnly || rule.Enabled {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules, nil
+}
+
+func (m *Memory) AlertRule(ctx context.Context, id int) (AlertRule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return AlertRule{}, m.Err
+	}
+	rule, ok := m.rules[id]
+	if !ok {
+		return rule, ErrNotFound
+	}
+	return rule, nil
+}
+
+func (m *Memory) CreateAlertRule(ctx context.Context, rule AlertRule) (AlertRule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return rule, m.Err
+	}
+	m.nextRuleID++
+	rule.ID = m.nextRuleID
+	rule.CreatedAt = m.Now()
+	rule.UpdatedAt = rule.CreatedAt
+	m.rules[rule.ID] = rule
+	return rule, nil
+}
+
+func (m *Memory) UpdateAlertRule(ctx context.Context, rule AlertRule) (AlertRule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return rule, m.Err
+	}
+	existing, ok := m.rules[rule.ID]
+	if !ok {
+		return rule, ErrNotFound
+	}
+	rule.AccountID = existing.AccountID
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = m.Now()
+	m.rules[rule.ID] = rule
+	return rule, nil
+}
+
+func (m *Memory) DeleteAlertRule(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+	if _, ok := m.rules[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.rules, id)
+	delete(m.deliveries, id)
+	return nil
+}
+
+func (m *Memory) DeliveredAuctions(ctx context.Context, ruleID int) (map[int]bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	delivered := make(map[int]bool)
+	for id := range m.deliveries[ruleID] {
+		delivered[id] = true
+	}
+	return delivered, nil
+}
+
+func (m *Memory) RecordDeliveries(ctx context.Context, ruleID int, auctionIDs []int, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.deliveries[ruleID] == nil {
+		m.deliveries[ruleID] = make(map[int]time.Time)
+	}
+	for _, id := range auctionIDs {
+		if _, ok := m.deliveries[ruleID][id]; !ok {
+			m.deliveries[ruleID][id] = at
+		}
+	}
+	return nil
+}
+
+func (m *Memory) PruneDeliveries(ctx context.Context, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+	for _, delivered := range m.deliveries {
+		for id, at := range delivered {
+			if at.Before(before) {
+				delete(delivered, id)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Memory) AHBotConfigs(ctx context.Context) ([]AHBotConfig, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	configs := []AHBotConfig{}
+	for _, config := range m.ahbot {
+		configs = append(configs, config)
+	}
+	sort.Slice(configs, func(i, j int) bool { return configs[i].AuctionHouse < configs[j].AuctionHouse })
+	return configs, nil
+}
+
+func (m *Memory) AHBotConfig(ctx context.Context, house int) (AHBotConfig, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return AHBotConfig{}, m.Err
+	}
+	config, ok := m.ahbot[house]
+	if !ok {
+		return config, ErrNotFound
+	}
+	return config, nil
+}
+
+func (m *Memory) UpdateAHBotConfig(ctx context.Context, config AHBotConfig) (AHBotConfig, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return config, m.Err
+	}
+	existing, ok := m.ahbot[config.AuctionHouse]
+	if !ok {
+		return config, ErrNotFound
+	}
+	config.Name = existing.Name
+	m.ahbot[config.AuctionHouse] = config
+	return config, nil
+}
+
+func (m *Memory) AHBotDisabledItems(ctx context.Context) ([]DisabledItem, error) {
+	return m.disabledItems(m.disabled)
+}
+
+func (m *Memory) DisableAHBotItem(ctx context.Context, entry int) error {
+	return m.disableItem(m.disabled, entry)
+}
+
+func (m *Memory) EnableAHBotItem(ctx context.Context, entry int) error {
+	return m.enableItem(m.disabled, entry)
+}
+
+// disabledItems names the disabled items in set from the item templates,
+// the way World does
+func (m *Memory) disabledItems(set map[int]bool) ([]DisabledItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	items := []DisabledItem{}
+	for entry := range set {
+		items = append(items, DisabledItem{Entry: entry, Name: m.items[entry].Name})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Entry < items[j].Entry })
+	return items, nil
+}
+
+func (m *Memory) disableItem(set map[int]bool, entry int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+	set[entry] = true
+	return nil
+}
+
+func (m *Memory) enableItem(set map[int]bool, entry int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+	if !set[entry] {
+		return ErrNotFound
+	}
+	delete(set, entry)
+	return nil
+}
+
+func (m *Memory) AuctionatorClasses(ctx context.Context) ([]AuctionatorClass, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	classes := []AuctionatorClass{}
+	for _, config := range m.classes {
+		classes = append(classes, config)
+	}
+	sort.Slice(classes, func(i, j int) bool {
+		if classes[i].Class != classes[j].Class {
+			return classes[i].Class < classes[j].Class
+		}
+		return classes[i].Subclass < classes[j].Subclass
+	})
+	return classes, nil
+}
+
+func (m *Memory) AuctionatorClass(ctx context.Context, class, subclass int) (AuctionatorClass, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return AuctionatorClass{}, m.Err
+	}
+	config, ok := m.classes[[2]int{class, subclass}]
+	if !ok {
+		return config, ErrNotFound
+	}
+	return config, nil
+}
+
+func (m *Memory) UpdateAuctionatorClass(ctx context.Context, config AuctionatorClass) (AuctionatorClass, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return config, m.Err
+	}
+	key := [2]int{config.Class, config.Subclass}
+	existing, ok := m.classes[key]
+	if !ok {
+		return config, ErrNotFound
+	}
+	existing.MaxCount, existing.StackCount = config.MaxCount, config.StackCount
+	m.classes[key] = existing
+	return existing, nil
+}
+
+func (m *Memory) AuctionatorDisabledItems(ctx context.Context) ([]DisabledItem, error) {
+	return m.disabledItems(m.auctionatorDisabled)
+}
+
+func (m *Memory) DisableAuctionatorItem(ctx context.Context, entry int) error {
+	return m.disableItem(m.auctionatorDisabled, entry)
+}
+
+func (m *Memory) EnableAuctionatorItem(ctx context.Context, entry int) error {
+	return m.enableItem(m.auctionatorDisabled, entry)
+}
+
+// ItemTooltip returns the tooltip added for an entry, named in locale where
+// AddItemName translated it
+func (m *Memory) ItemTooltip(ctx context.Context, entry int, locale string) (ItemTooltip, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return ItemTooltip{}, m.Err
+	}
+	tooltip, ok := m.tooltips[entry]
+	if !ok {
+		return tooltip, ErrNotFound
+	}
+	if name, ok := m.names[locale][entry]; ok {
+		tooltip.Name = name
+	}
+	if tooltip.SocketBonus != nil {
+		bonus := *tooltip.SocketBonus
+		bonus.Name = m.enchantmentNames[bonus.ID]
+		tooltip.SocketBonus = &bonus
+	}
+	return tooltip, nil
+}
+
+func (m *Memory) EnchantmentNames(ctx context.Context, locale string, ids []int) (map[int]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	names := make(map[int]string)
+	for _, id := range ids {
+		if name, ok := m.enchantmentNames[id]; ok {
+			names[id] = name
+		}
+	}
+	return names, nil
+}
+
+func (m *Memory) Account(ctx context.Context, username string, realm int) (Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return Account{}, m.Err
+	}
+	for _, account := range m.accounts {
+		if strings.EqualFold(account.Username, username) {
+			account.GMLevel = max(account.GMLevel, m.gmLevels[[2]int{account.ID, realm}])
+			return account, nil
+		}
+	}
+	return Account{}, ErrNotFound
+}
+
+func (m *Memory) RecordAudit(ctx context.Context, entry AuditEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+	entry.ID = len(m.audit) + 1
+	m.audit = append(m.audit, entry)
+	return nil
+}
+
+func (m *Memory) AuditLog(ctx context.Context, limit int) ([]AuditEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	entries := []AuditEntry{}
+	for i := len(m.audit) - 1; i >= 0 && len(entries) < limit; i-- {
+		entries = append(entries, m.audit[i])
+	}
+	return entries, nil
+}
+
+func (m *Memory) Professions(ctx context.Context, locale string) ([]Profession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	professions := []Profession{}
+	for _, p := range m.professions {
+		if len(m.recipes[p.ID]) > 0 {
+			professions = append(professions, p)
+		}
+	}
+	sort.Slice(professions, func(i, j int) bool {
+		return strings.ToLower(professions[i].Name) < strings.ToLower(professions[j].Name)
+	})
+	return professions, nil
+}
+
+func (m *Memory) Recipes(ctx context.Context, skill int, locale string) ([]Recipe, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	known := false
+	for _, p := range m.professions {
+		known = known || p.ID == skill
+	}
+	if !known {
+		return nil, ErrNotFound
+	}
+	items := m.localItems(locale)
+	recipes := []Recipe{}
+	for _, r := range m.recipes[skill] {
+		r.Reagents = append([]CraftItem(nil), r.Reagents...)
+		nameCraftItem(&r.Product, items)
+		for i := range r.Reagents {
+			nameCraftItem(&r.Reagents[i], items)
+		}
+		recipes = append(recipes, r)
+	}
+	return recipes, nil
+}
+
+func (m *Memory) Yields(ctx context.Context) (Yields, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return Yields{}, m.Err
+	}
+	return m.yields, nil
+}
+
+// ItemSources returns the sources AddItemSources set for an entry, which are
+// not translated, or none
+func (m *Memory) ItemSources(ctx context.Context, entry int, locale string) (ItemSources, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return ItemSources{}, m.Err
+	}
+	sources, ok := m.sources[entry]
+	if !ok {
+		return ItemSources{Entry: entry, Drops: []DropSource{}, Vendors: []VendorSource{}, Quests: []QuestSource{}}, nil
+	}
+	return sources, nil
+}
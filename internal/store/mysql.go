@@ -0,0 +1,713 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// auctionColumns are the columns scanned by scanAuction. Item fields are
+// filled in from the world database afterwards.
+const auctionColumns = `
+		ah.id, ah.houseid, ah.itemguid, ah.itemowner, ah.buyoutprice,
+		ah.time, ah.buyguid, ah.lastbid, ah.startbid, ah.deposit,
+		COALESCE(ii.itemEntry, 0), COALESCE(ii.count, 0), COALESCE(ii.randomPropertyId, 0),
+		COALESCE(c.name, 'Unknown') as owner_name`
+
+// auctionFrom selects live auctions. Append conditions starting with AND.
+const auctionFrom = `
+	FROM auctionhouse ah
+	LEFT JOIN item_instance ii ON ah.itemguid = ii.guid
+	LEFT JOIN characters c ON ah.itemowner = c.guid
+	WHERE ah.time > UNIX_TIMESTAMP()
+`
+
+// MySQL reads the auction house from the AzerothCore characters database.
+// Item templates come from the world database, which may be on another
+// server, usually through an ItemCache, and are joined in Go.
+type MySQL struct {
+	db    *sql.DB
+	items ItemSource
+}
+
+var _ AuctionRepository = (*MySQL)(nil)
+
+// NewMySQL returns a repository reading auctions from the characters
+// database db and item templates from items
+func NewMySQL(db *sql.DB, items ItemSource) *MySQL {
+	return &MySQL{db: db, items: items}
+}
+
+// houseCondition returns an SQL condition restricting ah.houseid, and its
+// arguments, for use after a WHERE clause. It is empty when house is 0.
+func houseCondition(house int) (string, []interface{}) {
+	if house == 0 {
+		return "", nil
+	}
+	return " AND ah.houseid = ?", []interface{}{house}
+}
+
+// ListAuctions orders rows by the sort expression with the auction ID as a
+// tie-breaker, so cursors always resume at a unique position. Sorts on item
+// fields cannot be done in SQL; they load every matching auction and page
+// through them in Go.
+func (s *MySQL) ListAuctions(ctx context.Context, q AuctionQuery) (AuctionPage, error) {
+	page := AuctionPage{Auctions: []AuctionItem{}}
+	by, ok := auctionSorts[q.Sort]
+	if !ok {
+		return page, fmt.Errorf("unknown sort %q", q.Sort)
+	}
+
+	var items map[int]ItemTemplate
+	if by.item || q.Filter.Search != "" || q.Filter.hasItemConditions() {
+		var err error
+		if items, err = s.liveItems(ctx, q.Locale); err != nil {
+			return page, err
+		}
+	}
+	var props RandomProperties
+	if q.Filter.Search != "" || q.Filter.Suffix != "" {
+		var err error
+		if props, err = s.items.RandomProperties(ctx, q.Locale); err != nil {
+			return page, err
+		}
+	}
+	where, args := q.Filter.where(items, props)
+	if by.item {
+		return s.listSortedInGo(ctx, q, where, args, items)
+	}
+
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) `+auctionFrom+where, args...).Scan(&page.Total); err != nil {
+		return page, err
+	}
+
+	direction, compare := "ASC", ">"
+	if q.Desc {
+		direction, compare = "DESC", "<"
+	}
+
+	query := `SELECT` + auctionColumns + `, ` + by.expr + ` AS sort_key` + auctionFrom + where
+	if q.Cursor != nil {
+		var value interface{} = q.Cursor.Value
+		if !by.text {
+			// Validated by DecodeCursor
+			value, _ = strconv.ParseInt(q.Cursor.Value, 10, 64)
+		}
+		query += fmt.Sprintf(" AND (%[1]s %[2]s ? OR (%[1]s = ? AND ah.id %[2]s ?))", by.expr, compare)
+		args = append(args, value, value, q.Cursor.ID)
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, ah.id %s LIMIT ?", by.expr, direction, direction)
+	// Fetch one extra row to learn whether there is a next page
+	args = append(args, q.Limit+1)
+	if q.Cursor == nil {
+		query += " OFFSET ?"
+		args = append(args, max(q.Page-1, 0)*q.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return page, err
+	}
+	defer rows.Close()
+
+	var lastKey string
+	for rows.Next() {
+		var key string
+		auction, err := scanAuction(rows, &key)
+		if err != nil {
+			return page, err
+		}
+		if len(page.Auctions) == q.Limit {
+			last := page.Auctions[len(page.Auctions)-1]
+			page.NextCursor = Cursor{Sort: q.Sort, Desc: q.Desc, Value: lastKey, ID: last.ID}.Encode()
+			break
+		}
+		page.Auctions = append(page.Auctions, auction)
+		lastKey = key
+	}
+	if err := rows.Err(); err != nil {
+		return page, err
+	}
+
+	page.Auctions, err = s.joinItems(ctx, page.Auctions, items, q.Locale)
+	return page, err
+}
+
+// listSortedInGo pages through every auction matching where, sorted on an
+// item field
+func (s *MySQL) listSortedInGo(ctx context.Context, q AuctionQuery, where string, args []interface{}, items map[int]ItemTemplate) (AuctionPage, error) {
+	page := AuctionPage{Auctions: []AuctionItem{}}
+	auctions, err := s.queryAuctions(ctx, `SELECT`+auctionColumns+auctionFrom+where, args...)
+	if err != nil {
+		return page, err
+	}
+	props, err := s.items.RandomProperties(ctx, q.Locale)
+	if err != nil {
+		return page, err
+	}
+
+	matched := make([]joinedAuction, len(auctions))
+	for i, auction := range auctions {
+		matched[i] = joinItem(auction, items, props)
+	}
+	page = pageAuctions(matched, q)
+	page.Auctions, err = s.joinMarketPrices(ctx, page.Auctions)
+	return page, err
+}
+
+// liveItems returns the templates of every item currently listed, named in
+// locale
+func (s *MySQL) liveItems(ctx context.Context, locale string) (map[int]ItemTemplate, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT ii.itemEntry
+		FROM auctionhouse ah
+		JOIN item_instance ii ON ah.itemguid = ii.guid
+		WHERE ah.time > UNIX_TIMESTAMP()
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []int
+	for rows.Next() {
+		var entry int
+		if err := rows.Scan(&entry); err != nil {
+			return nil, fmt.Errorf("scanning item entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return s.localItems(ctx, entries, locale)
+}
+
+// localItems returns the templates of the given entries, named in locale
+func (s *MySQL) localItems(ctx context.Context, entries []int, locale string) (map[int]ItemTemplate, error) {
+	items, err := s.items.Items(ctx, entries)
+	if err != nil || !translated(locale) {
+		return items, err
+	}
+	names, err := s.items.ItemNames(ctx, locale, entries)
+	if err != nil {
+		return nil, err
+	}
+	localize(items, names)
+	return items, nil
+}
+
+// joinItems fills in the item fields, random properties and market prices of
+// auctions. Items missing from items, which may be nil, are looked up in the
+// item source and named in locale.
+func (s *MySQL) joinItems(ctx context.Context, auctions []AuctionItem, items map[int]ItemTemplate, locale string) ([]AuctionItem, error) {
+	var missing []int
+	seen := make(map[int]bool)
+	for _, auction := range auctions {
+		if _, ok := items[auction.ItemEntry]; !ok && !seen[auction.ItemEntry] {
+			seen[auction.ItemEntry] = true
+			missing = append(missing, auction.ItemEntry)
+		}
+	}
+	if len(missing) > 0 {
+		found, err := s.localItems(ctx, missing, locale)
+		if err != nil {
+			return nil, err
+		}
+		for entry, item := range items {
+			found[entry] = item
+		}
+		items = found
+	}
+
+	props, err := s.items.RandomProperties(ctx, locale)
+	if err != nil {
+		return nil, err
+	}
+	for i, auction := range auctions {
+		auctions[i] = joinItem(auction, items, props).AuctionItem
+	}
+	return s.joinMarketPrices(ctx, auctions)
+}
+
+// queryAuctions runs a query selecting auctionColumns
+func (s *MySQL) queryAuctions(ctx context.Context, query string, args ...interface{}) ([]AuctionItem, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	auctions := []AuctionItem{}
+	for rows.Next() {
+		auction, err := scanAuction(rows)
+		if err != nil {
+			return nil, err
+		}
+		auctions = append(auctions, auction)
+	}
+	return auctions, rows.Err()
+}
+
+// scanAuction reads one row of auctionColumns and fills in the derived
+// fields. Any extra selected columns are scanned into extra.
+func scanAuction(rows *sql.Rows, extra ...interface{}) (AuctionItem, error) {
+	var auction AuctionItem
+	dest := []interface{}{
+		&auction.ID, &auction.HouseID, &auction.ItemGUID, &auction.ItemOwner,
+		&auction.BuyoutPrice, &auction.Time, &auction.BuyGUID, &auction.LastBid,
+		&auction.StartBid, &auction.Deposit, &auction.ItemEntry, &auction.Count,
+		&auction.RandomPropertyID, &auction.OwnerName,
+	}
+	err := rows.Scan(append(dest, extra...)...)
+	if err != nil {
+		return auction, fmt.Errorf("scanning auction: %w", err)
+	}
+	fillDerived(&auction, time.Now())
+	return auction, nil
+}
+
+// fillDerived sets the fields computed from the stored ones
+func fillDerived(auction *AuctionItem, now time.Time) {
+	auction.TimeLeft = wow.FormatTimeLeft(auction.Time - int(now.Unix()))
+	auction.UnitBuyout = 0
+	if auction.Count > 0 {
+		auction.UnitBuyout = auction.BuyoutPrice / auction.Count
+	}
+}
+
+func (s *MySQL) Stats(ctx context.Context, house int) (AuctionHouseStats, error) {
+	houseSQL, args := houseCondition(house)
+
+	query := `
+		SELECT
+			COUNT(*) as total_items,
+			COALESCE(SUM(ah.buyoutprice), 0) as total_value,
+			COALESCE(SUM(ah.lastbid > 0), 0) as active_bids,
+			COUNT(DISTINCT ah.itemowner) as unique_owners,
+			COUNT(DISTINCT ii.itemEntry) as unique_items
+		FROM auctionhouse ah
+		LEFT JOIN item_instance ii ON ah.itemguid = ii.guid
+		WHERE ah.time > UNIX_TIMESTAMP()` + houseSQL + `
+	`
+
+	var stats AuctionHouseStats
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(
+		&stats.TotalItems,
+		&stats.TotalValue,
+		&stats.ActiveBids,
+		&stats.UniqueOwners,
+		&stats.UniqueItems,
+	)
+	return stats, err
+}
+
+func (s *MySQL) Sellers(ctx context.Context, house int, name string) ([]Seller, error) {
+	houseSQL, args := houseCondition(house)
+	nameSQL := ""
+	if name != "" {
+		nameSQL = " AND c.name = ?"
+		args = append(args, name)
+	}
+
+	query := `
+		SELECT
+			c.name as seller_name,
+			COUNT(ah.id) as total_auctions,
+			SUM(ah.buyoutprice) as total_value,
+			COUNT(DISTINCT ii.itemEntry) as unique_items
+		FROM auctionhouse ah
+		LEFT JOIN characters c ON ah.itemowner = c.guid
+		LEFT JOIN item_instance ii ON ah.itemguid = ii.guid
+		WHERE ah.time > UNIX_TIMESTAMP()
+		AND c.name IS NOT NULL` + houseSQL + nameSQL + `
+		GROUP BY ah.itemowner, c.name
+		ORDER BY total_auctions DESC, total_value DESC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sellers := []Seller{}
+	for rows.Next() {
+		var seller Seller
+		err := rows.Scan(
+			&seller.Name,
+			&seller.TotalAuctions,
+			&seller.TotalValue,
+			&seller.UniqueItems,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning seller: %w", err)
+		}
+		sellers = append(sellers, seller)
+	}
+	return sellers, rows.Err()
+}
+
+func (s *MySQL) Houses(ctx context.Context) ([]AuctionHouse, error) {
+	return s.items.Houses(ctx)
+}
+
+func (s *MySQL) Item(ctx context.Context, entry int, locale string) (ItemTemplate, error) {
+	items, err := s.localItems(ctx, []int{entry}, locale)
+	if err != nil {
+		return ItemTemplate{}, err
+	}
+	item, ok := items[entry]
+	if !ok {
+		return item, ErrNotFound
+	}
+	return item, nil
+}
+
+func (s *MySQL) ItemAuctions(ctx context.Context, entry, house int, locale string) ([]AuctionItem, error) {
+	houseSQL, houseArgs := houseCondition(house)
+	query := `SELECT` + auctionColumns + auctionFrom + ` AND ii.itemEntry = ?` + houseSQL + ` ORDER BY ah.time ASC, ah.id ASC`
+	args := append([]interface{}{entry}, houseArgs...)
+
+	auctions, err := s.queryAuctions(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return s.joinItems(ctx, auctions, nil, locale)
+}
+
+// Economy groups auctions by item and seller in SQL, so the groups can be
+// combined by item quality once items are looked up
+func (s *MySQL) Economy(ctx context.Context) ([]EconomyStats, error) {
+	query := `
+		SELECT
+			ah.houseid,
+			COALESCE(ii.itemEntry, 0),
+			ah.itemowner,
+			COUNT(*) as listings,
+			COALESCE(SUM(ah.buyoutprice), 0) as buyout_value,
+			SUM(ah.lastbid > 0) as active_bids
+		FROM auctionhouse ah
+		LEFT JOIN item_instance ii ON ah.itemguid = ii.guid
+		WHERE ah.time > UNIX_TIMESTAMP()
+		GROUP BY ah.houseid, ii.itemEntry, ah.itemowner
+	`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type group struct {
+		house, entry, owner int
+		stats               EconomyStats
+	}
+	var groups []group
+	var entries []int
+	seen := make(map[int]bool)
+	for rows.Next() {
+		var g group
+		if err := rows.Scan(&g.house, &g.entry, &g.owner, &g.stats.Listings, &g.stats.BuyoutValue, &g.stats.ActiveBids); err != nil {
+			return nil, fmt.Errorf("scanning economy stats: %w", err)
+		}
+		groups = append(groups, g)
+		if !seen[g.entry] {
+			seen[g.entry] = true
+			entries = append(entries, g.entry)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err := s.items.Items(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct{ house, quality int }
+	totals := make(map[key]*EconomyStats)
+	sellers := make(map[key]map[int]bool)
+	for _, g := range groups {
+		k := key{g.house, items[g.entry].Quality}
+		e, ok := totals[k]
+		if !ok {
+			e = &EconomyStats{House: k.house, Quality: k.quality}
+			totals[k] = e
+			sellers[k] = make(map[int]bool)
+		}
+		e.Listings += g.stats.Listings
+		e.BuyoutValue += g.stats.BuyoutValue
+		e.ActiveBids += g.stats.ActiveBids
+		sellers[k][g.owner] = true
+	}
+
+	result := make([]EconomyStats, 0, len(totals))
+	for k, e := range totals {
+		e.UniqueSellers = len(sellers[k])
+		result = append(result, *e)
+	}
+	sortEconomy(result)
+	return result, nil
+}
+
+func (s *MySQL) BuyoutListings(ctx context.Context) (map[int][]UnitListing, error) {
+	query := `
+		SELECT ii.itemEntry, ii.count, ah.buyoutprice
+		FROM auctionhouse ah
+		JOIN item_instance ii ON ah.itemguid = ii.guid
+		WHERE ah.time > UNIX_TIMESTAMP()
+		AND ah.buyoutprice > 0
+		AND ii.count > 0
+	`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byEntry := make(map[int][]UnitListing)
+	for rows.Next() {
+		var entry int
+		var l UnitListing
+		if err := rows.Scan(&entry, &l.Count, &l.Buyout); err != nil {
+			return nil, fmt.Errorf("scanning buyout listing: %w", err)
+		}
+		l.PerUnit = l.Buyout / l.Count
+		byEntry[entry] = append(byEntry[entry], l)
+	}
+	return byEntry, rows.Err()
+}
+
+// Distribution counts auctions by item in SQL, then splits them by the
+// class and quality of the items
+func (s *MySQL) Distribution(ctx context.Context) ([]HouseDistribution, error) {
+	query := `
+		SELECT ah.houseid, COALESCE(ii.itemEntry, 0), COUNT(*)
+		FROM auctionhouse ah
+		LEFT JOIN item_instance ii ON ah.itemguid = ii.guid
+		WHERE ah.time > UNIX_TIMESTAMP()
+		GROUP BY ah.houseid, ii.itemEntry
+	`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []listingCount
+	var entries []int
+	seen := make(map[int]bool)
+	for rows.Next() {
+		var c listingCount
+		if err := rows.Scan(&c.house, &c.entry, &c.listings); err != nil {
+			return nil, fmt.Errorf("scanning listing count: %w", err)
+		}
+		counts = append(counts, c)
+		if !seen[c.entry] {
+			seen[c.entry] = true
+			entries = append(entries, c.entry)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err := s.items.Items(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+	return distribute(counts, items), nil
+}
+
+func (s *MySQL) LiveAuctions(ctx context.Context, locale string) ([]AuctionItem, error) {
+	auctions, err := s.queryAuctions(ctx, `SELECT`+auctionColumns+auctionFrom)
+	if err != nil {
+		return nil, err
+	}
+	return s.joinItems(ctx, auctions, nil, locale)
+}
+
+func (s *MySQL) LocalizeAuctions(ctx context.Context, auctions []AuctionItem, locale string) ([]AuctionItem, error) {
+	var entries []int
+	seen := make(map[int]bool)
+	for _, auction := range auctions {
+		if !seen[auction.ItemEntry] {
+			seen[auction.ItemEntry] = true
+			entries = append(entries, auction.ItemEntry)
+		}
+	}
+	items, err := s.localItems(ctx, entries, locale)
+	if err != nil {
+		return nil, err
+	}
+	props, err := s.items.RandomProperties(ctx, locale)
+	if err != nil {
+		return nil, err
+	}
+	localized := make([]AuctionItem, len(auctions))
+	for i, auction := range auctions {
+		localized[i] = joinItem(auction, items, props).AuctionItem
+	}
+	return localized, nil
+}
+
+// ItemOwners reads item_instance, where the game server keeps an auctioned
+// item after the auction ends until it is taken from the mail
+func (s *MySQL) ItemOwners(ctx context.Context, itemGUIDs []int) (map[int]int, error) {
+	owners := make(map[int]int, len(itemGUIDs))
+	for start := 0; start < len(itemGUIDs); start += itemBatchSize {
+		batch := itemGUIDs[start:min(start+itemBatchSize, len(itemGUIDs))]
+		args := make([]interface{}, len(batch))
+		for i, guid := range batch {
+			args[i] = guid
+		}
+		query := `SELECT guid, owner_guid FROM item_instance WHERE guid IN (?` + strings.Repeat(", ?", len(batch)-1) + `)`
+
+		rows, err := s.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var guid, owner int
+			if err := rows.Scan(&guid, &owner); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scanning item owner: %w", err)
+			}
+			owners[guid] = owner
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return owners, nil
+}
+
+// AuctionInstance reads the item_instance of a live auction, with the
+// enchantments the item carries and its random property or suffix
+func (s *MySQL) AuctionInstance(ctx context.Context, id int, locale string) (ItemInstance, error) {
+	query := `
+		SELECT ah.id, ah.itemguid, COALESCE(ii.itemEntry, 0), COALESCE(ii.count, 0),
+			COALESCE(ii.enchantments, ''), COALESCE(ii.randomPropertyId, 0)
+		FROM auctionhouse ah
+		LEFT JOIN item_instance ii ON ah.itemguid = ii.guid
+		WHERE ah.id = ? AND ah.time > UNIX_TIMESTAMP()
+	`
+
+	var (
+		instance     ItemInstance
+		enchantments string
+	)
+	err := s.db.QueryRowContext(ctx, query, id).Scan(
+		&instance.AuctionID, &instance.ItemGUID, &instance.ItemEntry, &instance.Count, &enchantments,
+		&instance.RandomPropertyID,
+	)
+	if err == sql.ErrNoRows {
+		return instance, ErrNotFound
+	}
+	if err != nil {
+		return instance, err
+	}
+	instance.Enchantments = parseEnchantments(enchantments)
+	if instance.RandomPropertyID == 0 {
+		return instance, nil
+	}
+
+	items, err := s.items.Items(ctx, []int{instance.ItemEntry})
+	if err != nil {
+		return instance, err
+	}
+	props, err := s.items.RandomProperties(ctx, locale)
+	if err != nil {
+		return instance, err
+	}
+	if bonus, ok := props.resolve(instance.RandomPropertyID, items[instance.ItemEntry]); ok {
+		instance.Random = &bonus
+	}
+	return instance, nil
+}
+
+// MarketPrices reads mod_auctionator_market_price, which mod_auctionator
+// keeps in the characters database
+func (s *MySQL) MarketPrices(ctx context.Context) (map[int]MarketPrice, error) {
+	prices := make(map[int]MarketPrice)
+	if err := s.queryMarketPrices(ctx, prices, ""); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// queryMarketPrices adds the market prices matching condition, which starts
+// with AND, to prices. Without mod_auctionator there are none.
+func (s *MySQL) queryMarketPrices(ctx context.Context, prices map[int]MarketPrice, condition string, args ...interface{}) error {
+	query := `
+		SELECT entry, average_price, scan_datetime
+		FROM mod_auctionator_market_price
+		WHERE average_price > 0
+	` + condition
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if isMissingTable(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p MarketPrice
+		var scanned sql.NullTime
+		if err := rows.Scan(&p.Entry, &p.AveragePrice, &scanned); err != nil {
+			return fmt.Errorf("scanning market price: %w", err)
+		}
+		p.ScannedAt = scanned.Time
+		prices[p.Entry] = p
+	}
+	return rows.Err()
+}
+
+// joinMarketPrices fills in the mod_auctionator market prices of auctions,
+// looking up only the items they hold
+func (s *MySQL) joinMarketPrices(ctx context.Context, auctions []AuctionItem) ([]AuctionItem, error) {
+	var entries []interface{}
+	seen := make(map[int]bool)
+	for _, auction := range auctions {
+		if !seen[auction.ItemEntry] {
+			seen[auction.ItemEntry] = true
+			entries = append(entries, auction.ItemEntry)
+		}
+	}
+
+	prices := make(map[int]MarketPrice)
+	for start := 0; start < len(entries); start += itemBatchSize {
+		batch := entries[start:min(start+itemBatchSize, len(entries))]
+		condition := ` AND entry IN (?` + strings.Repeat(", ?", len(batch)-1) + `)`
+		if err := s.queryMarketPrices(ctx, prices, condition, batch...); err != nil {
+			return nil, err
+		}
+	}
+	for i := range auctions {
+		joinMarketPrice(&auctions[i], prices)
+	}
+	return auctions, nil
+}
+
+// isMissingTable reports whether err is MySQL's error for a table that does
+// not exist, as when an optional module is not installed
+func isMissingTable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1146
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"sort"
+	"time"
+)
+
+// PricePoint is the per-unit buyout summary of one item at one snapshot
+type PricePoint struct {
+	Time         time.Time `json:"time"`
+	Listings     int       `json:"listings"`
+	Quantity     int       `json:"quantity"`
+	MinBuyout    int       `json:"min_buyout"`
+	MedianBuyout int       `json:"median_buyout"`
+	MeanBuyout   int       `json:"mean_buyout"`
+}
+
+// UnitListing is a single buyout listing reduced to what price statistics
+// need
+type UnitListing struct {
+	Count   int
+	Buyout  int
+	PerUnit int
+}
+
+// SummarizeListings computes per-unit buyout statistics. The median is
+// weighted by stack size so that one large cheap stack counts for every unit
+// in it rather than as a single listing.
+func SummarizeListings(listings []UnitListing) PricePoint {
+	var p PricePoint
+	if len(listings) == 0 {
+		return p
+	}
+
+	sorted := make([]UnitListing, len(listings))
+	copy(sorted, listings)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PerUnit < sorted[j].PerUnit })
+
+	total := 0
+	for _, l := range sorted {
+		p.Quantity += l.Count
+		total += l.Buyout
+	}
+	p.Listings = len(sorted)
+	p.MinBuyout = sorted[0].PerUnit
+	p.MeanBuyout = total / p.Quantity
+
+	half := (p.Quantity + 1) / 2
+	seen := 0
+	for _, l := range sorted {
+		seen += l.Count
+		if seen >= half {
+			p.MedianBuyout = l.PerUnit
+			break
+		}
+	}
+	return p
+}
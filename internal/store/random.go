@@ -0,0 +1,145 @@
+package store
+
+import "sort"
+
+// Enchantment effect types of spellitemenchantment_dbc that random
+// properties and suffixes grant
+const (
+	enchantmentResistance = 4
+	enchantmentStat       = 5
+)
+
+// RandomProperties resolves item_instance.randomPropertyId, which names an
+// itemrandomproperties_dbc row when positive and an itemrandomsuffix_dbc row
+// when negative. Both tables are imported from the client's DBC files into
+// the world database.
+type RandomProperties struct {
+	// Properties are keyed by randomPropertyId, so suffixes have negative
+	// keys
+	Properties map[int]RandomProperty
+	// Points are the rows of randproppoints_dbc, by item level
+	Points map[int]PropertyPoints
+}
+
+// RandomProperty is a random property or suffix, such as "of the Monkey"
+type RandomProperty struct {
+	ID     int
+	Suffix string
+	// Enchantments are those the property applies, in order
+	Enchantments []RandomEnchantment
+}
+
+// RandomEnchantment is one enchantment of a random property or suffix
+type RandomEnchantment struct {
+	ID int
+	// Allocation is the share, in hundredths of a percent, of the item's
+	// property points a suffix gives the enchantment
+	Allocation int
+	Effects    []EnchantmentEffect
+}
+
+// EnchantmentEffect is one effect of spellitemenchantment_dbc: a stat type
+// or resistance school as Arg, and Points as its amount, which is 0 for the
+// enchantments of suffixes
+type EnchantmentEffect struct {
+	Type   int
+	Points int
+	Arg    int
+}
+
+// PropertyPoints are the property points suffixes share out on items of
+// one item level, by quality and by randomPropertySlot
+type PropertyPoints struct {
+	Epic     [5]int
+	Superior [5]int
+	Good     [5]int
+}
+
+// RandomBonus is what a random property or suffix adds to an item
+type RandomBonus struct {
+	Suffix      string           `json:"suffix"`
+	Stats       []ItemStat       `json:"stats,omitempty"`
+	Resistances []ItemResistance `json:"resistances,omitempty"`
+}
+
+// randomPropertySlot picks the column of randproppoints_dbc for an
+// inventory type the way the worldserver does, or -1 for items that cannot
+// have a suffix
+func randomPropertySlot(inventoryType int) int {
+	switch inventoryType {
+	case 1, 4, 5, 7, 17, 20: // head, shirt, chest, legs, two-hand, robe
+		return 0
+	case 3, 6, 8, 10, 12: // shoulders, waist, feet, hands, trinket
+		return 1
+	case 2, 9, 11, 14, 16, 23: // neck, wrists, finger, shield, back, held in off-hand
+		return 2
+	case 13, 21, 22: // one-hand, main hand, off hand
+		return 3
+	case 15, 25, 26: // ranged, thrown, ranged right
+		return 4
+	}
+	return -1
+}
+
+// suffixFactor is the number of property points a suffix shares out on an
+// item
+func (p RandomProperties) suffixFactor(item ItemTemplate) int {
+	slot := randomPropertySlot(item.InventoryType)
+	points, ok := p.Points[item.ItemLevel]
+	if slot < 0 || !ok {
+		return 0
+	}
+	switch item.Quality {
+	case 2:
+		return points.Good[slot]
+	case 3:
+		return points.Superior[slot]
+	case 4:
+		return points.Epic[slot]
+	}
+	return 0
+}
+
+// resolve returns what the random property or suffix id adds to item, and
+// false when it is unknown
+func (p RandomProperties) resolve(id int, item ItemTemplate) (RandomBonus, bool) {
+	property, ok := p.Properties[id]
+	if id == 0 || !ok {
+		return RandomBonus{}, false
+	}
+	bonus := RandomBonus{Suffix: property.Suffix}
+	factor := p.suffixFactor(item)
+	for _, e := range property.Enchantments {
+		for _, effect := range e.Effects {
+			amount := effect.Points
+			if amount == 0 && id < 0 {
+				amount = e.Allocation * factor / 10000
+			}
+			if amount == 0 {
+				continue
+			}
+			switch effect.Type {
+			case enchantmentStat:
+				bonus.Stats = append(bonus.Stats, ItemStat{Type: effect.Arg, Value: amount})
+			case enchantmentResistance:
+				if effect.Arg > 0 {
+					bonus.Resistances = append(bonus.Resistances, ItemResistance{School: effect.Arg, Value: amount})
+				}
+			}
+		}
+	}
+	return bonus, true
+}
+
+// matching returns the IDs of the properties and suffixes whose names match,
+// sorted. match is a LIKE pattern compiled by likeMatcher.
+func (p RandomProperties) matching(match func(string) bool) []int {
+	var ids []int
+	for id, property := range p.Properties {
+		if property.Suffix != "" && match(property.Suffix) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,181 @@
+package store
+
+import "sort"
+
+// Kinds of things that drop items
+const (
+	SourceCreature   = "creature"
+	SourceGameObject = "gameobject"
+)
+
+// maxDropSources caps the creatures and gameobjects listed as dropping an
+// item, keeping those most likely to drop it
+const maxDropSources = 50
+
+// ItemSources lists where an item comes from besides the auction house
+type ItemSources struct {
+	Entry   int            `json:"entry"`
+	Drops   []DropSource   `json:"drops"`
+	Vendors []VendorSource `json:"vendors"`
+	Quests  []QuestSource  `json:"quests"`
+}
+
+// DropSource is a creature, or a gameobject such as a chest or fishing hole,
+// that drops an item
+type DropSource struct {
+	Kind  string `json:"kind"`
+	Entry int    `json:"entry"`
+	Name  string `json:"name"`
+	// Chance is the percentage chance one kill or opening drops the item,
+	// through reference_loot_template too
+	Chance float64 `json:"chance"`
+	// Expected is how many of the item one kill or opening drops on average
+	Expected float64 `json:"expected"`
+}
+
+// VendorSource is a creature that sells an item
+type VendorSource struct {
+	Entry int    `json:"entry"`
+	Name  string `json:"name"`
+	// Price is item_template.BuyPrice, what BuyCount of the item cost
+	Price    int `json:"price"`
+	BuyCount int `json:"buy_count"`
+	// MaxCount is the limited stock of the vendor, or 0 when unlimited
+	MaxCount int `json:"max_count,omitempty"`
+	// ExtendedCost is what the item costs besides Price, such as honor or
+	// tokens
+	ExtendedCost *ExtendedCost `json:"extended_cost,omitempty"`
+}
+
+// ExtendedCost is a row of itemextendedcost_dbc
+type ExtendedCost struct {
+	ID          int        `json:"id"`
+	HonorPoints int        `json:"honor_points,omitempty"`
+	ArenaPoints int        `json:"arena_points,omitempty"`
+	Items       []CostItem `json:"items,omitempty"`
+}
+
+// CostItem is an item an extended cost takes, such as a badge or token
+type CostItem struct {
+	Entry int    `json:"entry"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// QuestSource is a quest that rewards an item
+type QuestSource struct {
+	ID       int    `json:"id"`
+	Title    string `json:"title"`
+	Level    int    `json:"level"`
+	MinLevel int    `json:"min_level"`
+	Count    int    `json:"count"`
+	// Choice is set when the item is one of the rewards to choose from
+	Choice bool `json:"choice"`
+}
+
+// VendorUnitPrice returns what one of the item costs from a vendor asking for
+// nothing but gold, or 0 when no vendor sells it for gold alone
+func (s ItemSources) VendorUnitPrice() int {
+	for _, v := range s.Vendors {
+		if v.ExtendedCost == nil && v.Price > 0 {
+			return v.Price / max(v.BuyCount, 1)
+		}
+	}
+	return 0
+}
+
+// dropRate returns the percentage chance rows drop item and how many of it
+// they drop on average, following the references in refs
+func dropRate(rows []LootRow, refs map[int][]LootRow, item int) (chance, expected float64) {
+	// The chance is the expected count with every row dropping one at a
+	// time, capped where several rolls could each drop the item
+	once := func(rows []LootRow) []LootRow {
+		single := make([]LootRow, len(rows))
+		for i, row := range rows {
+			row.MinCount, row.MaxCount = 1, 1
+			single[i] = row
+		}
+		return single
+	}
+	singleRefs := make(map[int][]LootRow, len(refs))
+	for entry, rows := range refs {
+		singleRefs[entry] = once(rows)
+	}
+
+	expected = Yields{Reference: refs}.expected(rows, maxReferenceDepth)[item]
+	chance = Yields{Reference: singleRefs}.expected(once(rows), maxReferenceDepth)[item]
+	return min(chance*100, 100), expected
+}
+
+// sortSources orders drops most likely first, then vendors and quests by
+// name, and keeps the first maxDropSources drops
+func sortSources(s *ItemSources) {
+	sort.Slice(s.Drops, func(i, j int) bool {
+		a, b := s.Drops[i], s.Drops[j]
+		if a.Chance != b.Chance {
+			return a.Chance > b.Chance
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Entry < b.Entry
+	})
+	s.Drops = s.Drops[:min(len(s.Drops), maxDropSources)]
+	sort.Slice(s.Vendors, func(i, j int) bool {
+		a, b := s.Vendors[i], s.Vendors[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Entry < b.Entry
+	})
+	sort.Slice(s.Quests, func(i, j int) bool {
+		a, b := s.Quests[i], s.Quests[j]
+		if a.Level != b.Level {
+			return a.Level < b.Level
+		}
+		if a.Title != b.Title {
+			return a.Title < b.Title
+		}
+		return a.ID < b.ID
+	})
+}
+
+// OverpricedListing is a buyout of an item vendors sell for gold that costs
+// well above the vendor's price
+type OverpricedListing struct {
+	AuctionItem
+	// PercentOfVendor is the unit buyout as a percentage of the vendor's
+	// unit price
+	PercentOfVendor int `json:"percent_of_vendor"`
+}
+
+// FindOverpriced returns the buyouts whose unit price is at least markup
+// percent of vendorPrice, the vendor's unit price, dearest first. Without a
+// vendor price there are none.
+func FindOverpriced(auctions []AuctionItem, vendorPrice, markup int) []OverpricedListing {
+	listings := []OverpricedListing{}
+	if vendorPrice <= 0 {
+		return listings
+	}
+	for _, a := range auctions {
+		if a.BuyoutPrice <= 0 || a.Count <= 0 {
+			continue
+		}
+		percent := a.UnitBuyout * 100 / vendorPrice
+		if percent >= markup {
+			listings = append(listings, OverpricedListing{AuctionItem: a, PercentOfVendor: percent})
+		}
+	}
+
+	sort.Slice(listings, func(i, j int) bool {
+		a, b := listings[i], listings[j]
+		if a.UnitBuyout != b.UnitBuyout {
+			return a.UnitBuyout > b.UnitBuyout
+		}
+		return a.ID < b.ID
+	})
+	return listings
+}
@@ -0,0 +1,331 @@
+// Package store reads the AzerothCore auction house and keeps the data this
+// application owns. Handlers and background jobs depend on the repository
+// interfaces; MySQL and AppDB implement them against the game and
+// application databases, and Memory implements them in memory for tests.
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+)
+
+// ErrNotFound is returned when a requested item or record does not exist
+var ErrNotFound = errors.New("not found")
+
+// AuctionRepository reads live auctions and static item data
+type AuctionRepository interface {
+	// ListAuctions returns one page of live auctions matching q
+	ListAuctions(ctx context.Context, q AuctionQuery) (AuctionPage, error)
+	// Stats summarizes the live auctions of one house, or of all houses
+	// when house is 0
+	Stats(ctx context.Context, house int) (AuctionHouseStats, error)
+	// Sellers lists characters with live auctions, busiest first. When name
+	// is set only that character is returned.
+	Sellers(ctx context.Context, house int, name string) ([]Seller, error)
+	// Houses lists the Alliance, Horde and neutral auction houses
+	Houses(ctx context.Context) ([]AuctionHouse, error)
+	// Item returns an item template with its name translated into locale
+	// where there is a translation, or ErrNotFound
+	Item(ctx context.Context, entry int, locale string) (ItemTemplate, error)
+	// ItemAuctions returns every live auction of one item, ending soonest
+	// first, named in locale
+	ItemAuctions(ctx context.Context, entry, house int, locale string) ([]AuctionItem, error)
+	// Economy aggregates live auctions by house and quality
+	Economy(ctx context.Context) ([]EconomyStats, error)
+	// BuyoutListings returns every live auction with a buyout, by item entry
+	BuyoutListings(ctx context.Context) (map[int][]UnitListing, error)
+	// Distribution counts live auctions by house, item class and quality,
+	// ordered by house
+	Distribution(ctx context.Context) ([]HouseDistribution, error)
+	// LiveAuctions returns every live auction in no particular order, named
+	// in locale
+	LiveAuctions(ctx context.Context, locale string) ([]AuctionItem, error)
+	// LocalizeAuctions names the items of auctions read in another locale
+	// in locale, with their random property or suffix, as the listings do
+	LocalizeAuctions(ctx context.Context, auctions []AuctionItem, locale string) ([]AuctionItem, error)
+	// ItemOwners returns the characters owning the given item instances,
+	// keyed by item GUID. Deleted items are left out.
+	ItemOwners(ctx context.Context, itemGUIDs []int) (map[int]int, error)
+	// MarketPrices returns mod_auctionator's market prices by item entry,
+	// or none when the module is not installed
+	MarketPrices(ctx context.Context) (map[int]MarketPrice, error)
+	// AuctionInstance returns the item instance of a live auction, or
+	// ErrNotFound
+	AuctionInstance(ctx context.Context, id int, locale string) (ItemInstance, error)
+}
+
+// HistoryRepository stores periodic price snapshots
+type HistoryRepository interface {
+	// SaveSnapshot records the per-item prices of one scan of the auction
+	// house, which held auctions listings in total
+	SaveSnapshot(ctx context.Context, takenAt time.Time, auctions int, prices map[int]PricePoint) error
+	// PruneSnapshots deletes snapshots taken before the given time
+	PruneSnapshots(ctx context.Context, before time.Time) error
+	// ItemHistory returns an item's price points since the given time,
+	// oldest first
+	ItemHistory(ctx context.Context, entry int, since time.Time) ([]PricePoint, error)
+	// MedianPrices returns, by item entry, the median of the per-unit
+	// median buyouts recorded since the given time
+	MedianPrices(ctx context.Context, since time.Time) (map[int]int, error)
+}
+
+// AlertRepository stores alert rules and the auctions they have fired for
+type AlertRepository interface {
+	AlertRules(ctx context.Context, enabledOnly bool) ([]AlertRule, error)
+	// AlertRule returns one rule, or ErrNotFound
+	AlertRule(ctx context.Context, id int) (AlertRule, error)
+	// CreateAlertRule stores a new rule and returns it as saved
+	CreateAlertRule(ctx context.Context, rule AlertRule) (AlertRule, error)
+	// UpdateAlertRule replaces the rule with rule.ID, keeping its owner, and
+	// returns it as saved, or ErrNotFound
+	UpdateAlertRule(ctx context.Context, rule AlertRule) (AlertRule, error)
+	// DeleteAlertRule deletes a rule and its deliveries, or returns
+	// ErrNotFound
+	DeleteAlertRule(ctx context.Context, id int) error
+	// DeliveredAuctions returns the IDs of auctions a rule has fired for
+	DeliveredAuctions(ctx context.Context, ruleID int) (map[int]bool, error)
+	RecordDeliveries(ctx context.Context, ruleID int, auctionIDs []int, at time.Time) error
+	// PruneDeliveries forgets deliveries made before the given time
+	PruneDeliveries(ctx context.Context, before time.Time) error
+}
+
+// AHBotRepository reads and edits the configuration of mod_auctionhousebot,
+// which the worldserver loads when it starts
+type AHBotRepository interface {
+	// AHBotConfigs returns the configuration of every auction house, or
+	// none when the module is not installed
+	AHBotConfigs(ctx context.Context) ([]AHBotConfig, error)
+	// AHBotConfig returns the configuration of one auction house, or
+	// ErrNotFound
+	AHBotConfig(ctx context.Context, house int) (AHBotConfig, error)
+	// UpdateAHBotConfig replaces the settings of config.AuctionHouse,
+	// keeping its name, and returns it as saved, or ErrNotFound
+	UpdateAHBotConfig(ctx context.Context, config AHBotConfig) (AHBotConfig, error)
+	// AHBotDisabledItems lists the items the bot never lists, by entry
+	AHBotDisabledItems(ctx context.Context) ([]DisabledItem, error)
+	// DisableAHBotItem adds an item to the disabled items. Disabling an
+	// item twice is not an error.
+	DisableAHBotItem(ctx context.Context, entry int) error
+	// EnableAHBotItem removes an item from the disabled items, or returns
+	// ErrNotFound
+	EnableAHBotItem(ctx context.Context, entry int) error
+}
+
+// AuctionatorRepository reads and edits the configuration mod_auctionator
+// keeps in the world database, which its seller reads as it lists items
+type AuctionatorRepository interface {
+	// AuctionatorClasses returns the configuration of every item class and
+	// subclass, or none when the module is not installed
+	AuctionatorClasses(ctx context.Context) ([]AuctionatorClass, error)
+	// AuctionatorClass returns the configuration of one item class and
+	// subclass, or ErrNotFound
+	AuctionatorClass(ctx context.Context, class, subclass int) (AuctionatorClass, error)
+	// UpdateAuctionatorClass replaces the max count and stack count of
+	// config's class and subclass and returns it as saved, or ErrNotFound
+	UpdateAuctionatorClass(ctx context.Context, config AuctionatorClass) (AuctionatorClass, error)
+	// AuctionatorDisabledItems lists the items the seller never lists, by
+	// entry
+	AuctionatorDisabledItems(ctx context.Context) ([]DisabledItem, error)
+	// DisableAuctionatorItem adds an item to the disabled items. Disabling
+	// an item twice is not an error.
+	DisableAuctionatorItem(ctx context.Context, entry int) error
+	// EnableAuctionatorItem removes an item from the disabled items, or
+	// returns ErrNotFound
+	EnableAuctionatorItem(ctx context.Context, entry int) error
+}
+
+// TooltipRepository reads what item tooltips show from the world database
+type TooltipRepository interface {
+	// ItemTooltip returns the tooltip of an item template, with its texts
+	// translated into locale where there are translations, or ErrNotFound
+	ItemTooltip(ctx context.Context, entry int, locale string) (ItemTooltip, error)
+	// EnchantmentNames returns the names of the given enchantments in
+	// locale, keyed by ID. Enchantments missing from
+	// spellitemenchantment_dbc are left out.
+	EnchantmentNames(ctx context.Context, locale string, ids []int) (map[int]string, error)
+}
+
+// CraftingRepository reads professions and their recipes from the DBC tables
+// of the world database
+type CraftingRepository interface {
+	// Professions lists the professions and secondary skills, named in
+	// locale
+	Professions(ctx context.Context, locale string) ([]Profession, error)
+	// Recipes returns the recipes of a profession that create an item, with
+	// their items named in locale, or ErrNotFound for unknown professions
+	Recipes(ctx context.Context, skill int, locale string) ([]Recipe, error)
+}
+
+// YieldRepository reads what items break down into when disenchanted,
+// milled or prospected
+type YieldRepository interface {
+	// Yields returns the disenchantable items and the loot templates of
+	// disenchanting, milling and prospecting with the references they
+	// follow. Missing tables are left empty.
+	Yields(ctx context.Context) (Yields, error)
+}
+
+// SourceRepository reads where items come from: the loot of creatures and
+// gameobjects, vendors and quest rewards
+type SourceRepository interface {
+	// ItemSources returns the sources of an item with their names
+	// translated into locale where there are translations. Missing tables
+	// are left out.
+	ItemSources(ctx context.Context, entry int, locale string) (ItemSources, error)
+}
+
+// AccountRepository reads the game accounts of the auth database
+type AccountRepository interface {
+	// Account returns the account with a username, ignoring case, with its
+	// gmlevel on realm, or ErrNotFound
+	Account(ctx context.Context, username string, realm int) (Account, error)
+}
+
+// AuditRepository records the changes made through the admin API
+type AuditRepository interface {
+	RecordAudit(ctx context.Context, entry AuditEntry) error
+	// AuditLog returns the latest entries, newest first
+	AuditLog(ctx context.Context, limit int) ([]AuditEntry, error)
+}
+
+// AuditEntry records one change made through the admin API. Before and After
+// hold the changed record as JSON, and are empty when it did not exist.
+type AuditEntry struct {
+	ID        int             `json:"id"`
+	Time      time.Time       `json:"time"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Target    string          `json:"target"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	RequestID string          `json:"request_id"`
+}
+
+// ItemSource reads static item and auction house data from the world
+// database, directly or through a cache
+type ItemSource interface {
+	// Items returns the templates of the given entries, keyed by entry.
+	// Entries without a template are left out.
+	Items(ctx context.Context, entries []int) (map[int]ItemTemplate, error)
+	// ItemNames returns the names of the given entries translated into
+	// locale, keyed by entry. Untranslated entries are left out.
+	ItemNames(ctx context.Context, locale string, entries []int) (map[int]string, error)
+	// Houses lists the Alliance, Horde and neutral auction houses
+	Houses(ctx context.Context) ([]AuctionHouse, error)
+	// RandomProperties returns every random property and suffix, named in
+	// locale
+	RandomProperties(ctx context.Context, locale string) (RandomProperties, error)
+}
+
+// ItemCatalog is an in-memory copy of the item templates that can be
+// reloaded while serving
+type ItemCatalog interface {
+	// Refresh reloads every item template and translated name
+	Refresh(ctx context.Context) (ItemCacheStatus, error)
+	// Status describes the templates currently loaded
+	Status() ItemCacheStatus
+}
+
+// AuctionItem represents an auction house item
+type AuctionItem struct {
+	ID          int    `json:"id"`
+	HouseID     int    `json:"house_id"`
+	ItemGUID    int    `json:"item_guid"`
+	ItemOwner   int    `json:"item_owner"`
+	BuyoutPrice int    `json:"buyout_price"`
+	Time        int    `json:"time"`
+	BuyGUID     int    `json:"buy_guid"`
+	LastBid     int    `json:"last_bid"`
+	StartBid    int    `json:"start_bid"`
+	Deposit     int    `json:"deposit"`
+	ItemEntry   int    `json:"item_entry"`
+	ItemName    string `json:"item_name"`
+	OwnerName   string `json:"owner_name"`
+	Count       int    `json:"count"`
+	Quality     int    `json:"quality"`
+	ItemLevel   int    `json:"item_level"`
+	TimeLeft    string `json:"time_left"`
+	UnitBuyout  int    `json:"unit_buyout"`
+	// SellPrice is what a vendor pays for one unit of the item
+	SellPrice int `json:"sell_price"`
+	// RandomPropertyID is item_instance.randomPropertyId: a random property
+	// when positive, a random suffix when negative
+	RandomPropertyID int `json:"random_property_id"`
+	// Suffix names the random property or suffix, and ends ItemName
+	Suffix string `json:"suffix,omitempty"`
+	// RandomStats and RandomResistances are what the random property or
+	// suffix grants
+	RandomStats       []ItemStat       `json:"random_stats,omitempty"`
+	RandomResistances []ItemResistance `json:"random_resistances,omitempty"`
+	// AuctionatorPrice is mod_auctionator's per-unit market price of the
+	// item as scanned at AuctionatorScannedAt, or 0 when it has none
+	AuctionatorPrice     int       `json:"auctionator_price"`
+	AuctionatorScannedAt time.Time `json:"auctionator_scanned_at,omitzero"`
+}
+
+// AuctionHouseStats represents auction house statistics
+type AuctionHouseStats struct {
+	TotalItems   int `json:"total_items"`
+	TotalValue   int `json:"total_value"`
+	ActiveBids   int `json:"active_bids"`
+	UniqueOwners int `json:"unique_owners"`
+	UniqueItems  int `json:"unique_items"`
+}
+
+// Seller summarizes one character's live auctions
+type Seller struct {
+	Name          string `json:"name"`
+	TotalAuctions int    `json:"total_auctions"`
+	TotalValue    int    `json:"total_value"`
+	UniqueItems   int    `json:"unique_items"`
+}
+
+// AuctionHouse describes one auction house from acore_world.auctionhouse_dbc.
+// Rates are percentages: DepositRate of the vendor sell price per 12 hours,
+// ConsignmentRate of the final sale price.
+type AuctionHouse struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	FactionID       int    `json:"faction_id"`
+	DepositRate     int    `json:"deposit_rate"`
+	ConsignmentRate int    `json:"consignment_rate"`
+}
+
+// ItemTemplate holds the static item data from acore_world.item_template
+type ItemTemplate struct {
+	Entry         int    `json:"entry"`
+	Name          string `json:"name"`
+	Quality       int    `json:"quality"`
+	ItemLevel     int    `json:"item_level"`
+	RequiredLevel int    `json:"required_level"`
+	Class         int    `json:"class"`
+	Subclass      int    `json:"subclass"`
+	InventoryType int    `json:"inventory_type"`
+	Stackable     int    `json:"stackable"`
+	BuyCount      int    `json:"buy_count"`
+	BuyPrice      int    `json:"buy_price"`
+	SellPrice     int    `json:"sell_price"`
+}
+
+// EconomyStats aggregates the live auctions of one house and item quality
+type EconomyStats struct {
+	House         int
+	Quality       int
+	Listings      int
+	BuyoutValue   int
+	ActiveBids    int
+	UniqueSellers int
+}
+
+// sortEconomy orders stats by house, then quality
+func sortEconomy(stats []EconomyStats) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].House != stats[j].House {
+			return stats[i].House < stats[j].House
+		}
+		return stats[i].Quality < stats[j].Quality
+	})
+}
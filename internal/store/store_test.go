@@ -0,0 +1,743 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestFilterWhere(t *testing.T) {
+	items := map[int]ItemTemplate{
+		2589:  {Entry: 2589, Name: "Linen Cloth", Class: 7, Quality: 1},
+		14047: {Entry: 14047, Name: "Runecloth 50%", Class: 7, Quality: 2},
+		14048: {Entry: 14048, Name: "Bolt of Runecloth", Class: 7, Quality: 2},
+		19019: {Entry: 19019, Name: "Thunderfury", Class: 2, Quality: 5},
+	}
+	f := AuctionFilter{
+		Search:     "50%",
+		House:      2,
+		Account:    5,
+		Class:      intPtr(7),
+		Quality:    IntRange{Min: intPtr(2)},
+		UnitBuyout: IntRange{Max: intPtr(100)},
+		TimeLeft:   []string{"short", "bogus"},
+	}
+	where, args := f.where(items, RandomProperties{})
+
+	for _, cond := range []string{
+		" AND (ii.itemEntry IN (14047) OR c.name LIKE ?)",
+		" AND ii.itemEntry IN (14047, 14048)",
+		" AND ah.houseid = ?",
+		" AND c.account = ?",
+		" AND ah.buyoutprice > 0 AND " + unitBuyoutSQL + " <= ?",
+		" AND (" + timeLeftSQL + " BETWEEN 0 AND 1799)",
+	} {
+		if !strings.Contains(where, cond) {
+			t.Errorf("where = %q, missing %q", where, cond)
+		}
+	}
+	want := []interface{}{"%50%%", 2, 5, 100}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+
+	where, _ = AuctionFilter{Search: "Alice", Class: intPtr(4)}.where(items, RandomProperties{})
+	if !strings.Contains(where, " AND c.name LIKE ?") || !strings.Contains(where, " AND FALSE") {
+		t.Errorf("where = %q, want a seller-only search and no matching items", where)
+	}
+
+	if where, args := (AuctionFilter{}).where(nil, RandomProperties{}); where != "" || args != nil {
+		t.Errorf("empty filter where = %q %v, want nothing", where, args)
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	c := Cursor{Sort: "unit_buyout", Desc: true, Value: "150", ID: 42}
+	got, err := DecodeCursor(c.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != c {
+		t.Errorf("DecodeCursor() = %+v, want %+v", *got, c)
+	}
+
+	for _, value := range []string{
+		"not base64!",
+		Cursor{Sort: "nope", Value: "1"}.Encode(),
+		Cursor{Sort: "buyout", Value: "abc"}.Encode(),
+	} {
+		if _, err := DecodeCursor(value); err != ErrInvalidCursor {
+			t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", value, err)
+		}
+	}
+}
+
+func TestSummarizeListings(t *testing.T) {
+	p := SummarizeListings([]UnitListing{
+		{Count: 1, Buyout: 500, PerUnit: 500},
+		{Count: 20, Buyout: 2000, PerUnit: 100},
+		{Count: 2, Buyout: 400, PerUnit: 200},
+	})
+	want := PricePoint{Listings: 3, Quantity: 23, MinBuyout: 100, MedianBuyout: 100, MeanBuyout: 126}
+	if p != want {
+		t.Errorf("SummarizeListings() = %+v, want %+v", p, want)
+	}
+
+	if p := SummarizeListings(nil); p != (PricePoint{}) {
+		t.Errorf("SummarizeListings(nil) = %+v, want zero", p)
+	}
+}
+
+func TestFindDeals(t *testing.T) {
+	refs := ReferencePrices(
+		map[int]MarketPrice{2589: {Entry: 2589, AveragePrice: 120}},
+		map[int]int{2589: 999, 2592: 50},
+	)
+	if refs[2589] != (ReferencePrice{120, PriceSourceAuctionator}) || refs[2592] != (ReferencePrice{50, PriceSourceMedian}) {
+		t.Fatalf("ReferencePrices() = %+v, want auctionator's price over the median", refs)
+	}
+
+	houses := []AuctionHouse{{ID: 2, ConsignmentRate: 5}, {ID: 6, ConsignmentRate: 5}, {ID: 7, ConsignmentRate: 15}}
+	auctions := []AuctionItem{
+		{ID: 1, HouseID: 2, ItemEntry: 2589, Count: 20, BuyoutPrice: 2000, Quality: 1},
+		{ID: 2, HouseID: 2, ItemEntry: 2589, Count: 10, BuyoutPrice: 1500, Quality: 1},
+		{ID: 3, HouseID: 6, ItemEntry: 2592, Count: 5, BuyoutPrice: 100, Quality: 1},
+		{ID: 4, HouseID: 6, ItemEntry: 2592, Count: 1, StartBid: 10, Quality: 1},
+		{ID: 5, HouseID: 7, ItemEntry: 19019, Count: 1, BuyoutPrice: 1, Quality: 5},
+		{ID: 6, HouseID: 2, ItemEntry: 2589, Count: 1, BuyoutPrice: 110, Quality: 1},
+		// Under market, but the neutral house's cut makes reselling a loss
+		{ID: 7, HouseID: 7, ItemEntry: 2589, Count: 1, BuyoutPrice: 114, Quality: 1},
+	}
+
+	deals := FindDeals(auctions, refs, houses, DealQuery{MaxPercent: 90})
+	if len(deals) != 2 || deals[0].ID != 1 || deals[1].ID != 3 {
+		t.Fatalf("FindDeals() = %+v, want auctions 1 and 3", deals)
+	}
+	if d := deals[0]; d.MarketPrice != 120 || d.PriceSource != PriceSourceAuctionator || d.PercentOfMarket != 83 || d.Profit != 280 {
+		t.Errorf("deal = %+v, want 83%% of 120 per unit and 280 profit", d)
+	}
+	if d := deals[1]; d.PriceSource != PriceSourceMedian || d.PercentOfMarket != 40 || d.Profit != 138 {
+		t.Errorf("deal = %+v, want 40%% of the median and 138 profit", d)
+	}
+
+	if deals := FindDeals(auctions, refs, houses, DealQuery{MaxPercent: 100}); len(deals) != 3 {
+		t.Errorf("at 100%%: %d deals, want 3 without the loss", len(deals))
+	}
+	if deals := FindDeals(auctions, refs, houses, DealQuery{MaxPercent: 90, House: 6}); len(deals) != 1 || deals[0].ID != 3 {
+		t.Errorf("horde deals = %+v, want auction 3", deals)
+	}
+	if deals := FindDeals(auctions, refs, houses, DealQuery{MaxPercent: 90, MinProfit: 200}); len(deals) != 1 || deals[0].ID != 1 {
+		t.Errorf("deals over 200 profit = %+v, want auction 1", deals)
+	}
+}
+
+func TestFindVendorFlips(t *testing.T) {
+	auctions := []AuctionItem{
+		{ID: 1, HouseID: 2, Count: 20, SellPrice: 13, BuyoutPrice: 200, StartBid: 100, Quality: 1},
+		{ID: 2, HouseID: 2, Count: 20, SellPrice: 13, BuyoutPrice: 300, StartBid: 100, Quality: 1},
+		{ID: 3, HouseID: 6, Count: 1, SellPrice: 500, StartBid: 200, LastBid: 400, Quality: 2},
+		{ID: 4, HouseID: 6, Count: 1, SellPrice: 500, BuyoutPrice: 499, StartBid: 400, Quality: 2},
+		{ID: 5, HouseID: 6, Count: 1, SellPrice: 0, StartBid: 1, Quality: 0},
+		{ID: 6, HouseID: 7, Count: 1, SellPrice: 100, BuyoutPrice: 150, StartBid: 100, Quality: 1},
+	}
+
+	flips := FindVendorFlips(auctions, FlipQuery{})
+	want := []struct {
+		id, price, profit int
+		buy               string
+	}{
+		{1, 200, 60, BuyBuyout},
+		{4, 499, 1, BuyBuyout},
+		{2, 100, 160, BuyBid},
+		{3, 420, 80, BuyBid},
+	}
+	if len(flips) != len(want) {
+		t.Fatalf("FindVendorFlips() = %+v, want %d flips", flips, len(want))
+	}
+	for i, w := range want {
+		f := flips[i]
+		if f.ID != w.id || f.Price != w.price || f.Profit != w.profit || f.Buy != w.buy {
+			t.Errorf("flip %d = %+v, want auction %d by %s for %d, %d profit", i, f, w.id, w.buy, w.price, w.profit)
+		}
+	}
+
+	flips = FindVendorFlips(auctions, FlipQuery{House: 6, Quality: IntRange{Min: intPtr(2)}})
+	if len(flips) != 2 || flips[0].ID != 4 || flips[1].ID != 3 {
+		t.Errorf("filtered flips = %+v, want auctions 4 and 3", flips)
+	}
+}
+
+func TestMinBid(t *testing.T) {
+	for _, tt := range []struct {
+		startBid, lastBid, want int
+	}{
+		{100, 0, 100},
+		{100, 10, 11},
+		{100, 199, 208},
+		{100, 400, 420},
+		{100, 12345, 12962},
+	} {
+		a := AuctionItem{StartBid: tt.startBid, LastBid: tt.lastBid}
+		if got := a.MinBid(); got != tt.want {
+			t.Errorf("MinBid() with a bid of %d = %d, want %d", tt.lastBid, got, tt.want)
+		}
+	}
+}
+
+func TestPriceCrafts(t *testing.T) {
+	// Linen Bandage takes one Linen Cloth; Heavy Linen Bandage two, and
+	// Wool Bandage a Wool Cloth nobody lists
+	recipes := []Recipe{
+		{SpellID: 3275, Name: "Linen Bandage", Product: CraftItem{Entry: 1251}, MinCount: 1, MaxCount: 1, Reagents: []CraftItem{{Entry: 2589, Count: 1}}},
+		{SpellID: 3276, Name: "Heavy Linen Bandage", Product: CraftItem{Entry: 2581}, MinCount: 1, MaxCount: 1, Reagents: []CraftItem{{Entry: 2589, Count: 2}}},
+		{SpellID: 3277, Name: "Wool Bandage", Product: CraftItem{Entry: 3530}, MinCount: 1, MaxCount: 1, Reagents: []CraftItem{{Entry: 2592, Count: 1}}},
+		{SpellID: 2330, Name: "Minor Healing Potion", Product: CraftItem{Entry: 118}, MinCount: 1, MaxCount: 3, Reagents: []CraftItem{{Entry: 2589, Count: 1}}},
+	}
+	auctions := []AuctionItem{
+		{ID: 1, HouseID: 2, ItemEntry: 2589, Count: 20, BuyoutPrice: 2000, UnitBuyout: 100},
+		{ID: 2, HouseID: 6, ItemEntry: 2589, Count: 10, BuyoutPrice: 800, UnitBuyout: 80},
+		{ID: 3, HouseID: 2, ItemEntry: 1251, Count: 5, BuyoutPrice: 1000, UnitBuyout: 200},
+		{ID: 4, HouseID: 7, ItemEntry: 2581, Count: 1, BuyoutPrice: 150, UnitBuyout: 150},
+		{ID: 5, HouseID: 2, ItemEntry: 3530, Count: 1, BuyoutPrice: 500, UnitBuyout: 500},
+		{ID: 6, HouseID: 2, ItemEntry: 118, Count: 1, BuyoutPrice: 100, UnitBuyout: 100},
+	}
+	houses := []AuctionHouse{{ID: 2, ConsignmentRate: 5}, {ID: 6, ConsignmentRate: 5}, {ID: 7, ConsignmentRate: 15}}
+
+	crafts := PriceCrafts(recipes, auctions, houses, 0)
+	want := []struct {
+		spell, cost, revenue, margin int
+		priced                       bool
+	}{
+		{3275, 80, 190, 110, true},
+		{2330, 80, 190, 110, true},
+		{3276, 160, 128, -32, true},
+		{3277, 0, 0, 0, false},
+	}
+	if len(crafts) != len(want) {
+		t.Fatalf("PriceCrafts() = %+v, want %d crafts", crafts, len(want))
+	}
+	for i, w := range want {
+		c := crafts[i]
+		if c.SpellID != w.spell || c.Cost != w.cost || c.Revenue != w.revenue || c.Margin != w.margin || c.Priced != w.priced {
+			t.Errorf("craft %d = %+v, want spell %d costing %d for %d revenue, %d margin", i, c, w.spell, w.cost, w.revenue, w.margin)
+		}
+	}
+	if got := crafts[0].Reagents[0].UnitPrice; got != 80 {
+		t.Errorf("reagent unit price = %d, want 80", got)
+	}
+	if recipes[0].Reagents[0].UnitPrice != 0 {
+		t.Error("PriceCrafts() modified the recipes")
+	}
+
+	// Within the Alliance house, Linen Cloth costs 100
+	crafts = PriceCrafts(recipes, auctions, houses, 2)
+	if crafts[0].SpellID != 3275 || crafts[0].Cost != 100 || crafts[0].Margin != 90 {
+		t.Errorf("alliance craft = %+v, want Linen Bandage with 90 margin", crafts[0])
+	}
+
+	for _, tt := range []struct{ basePoints, dieSides, min, max int }{
+		{0, 1, 1, 1},
+		{0, 0, 1, 1},
+		{4, 1, 5, 5},
+		{0, 3, 1, 3},
+	} {
+		if min, max := createdCount(tt.basePoints, tt.dieSides); min != tt.min || max != tt.max {
+			t.Errorf("createdCount(%d, %d) = %d, %d, want %d, %d", tt.basePoints, tt.dieSides, min, max, tt.min, tt.max)
+		}
+	}
+}
+
+func TestFindYieldListings(t *testing.T) {
+	y := NewYields()
+	// Disenchanting entry 1 gives 1-2 Strange Dust at 75%, or one Lesser
+	// Magic Essence from a group splitting the rest equally with nothing
+	y.Disenchants[9776] = Disenchant{ID: 1, RequiredSkill: 1}
+	y.Disenchant[1] = []LootRow{
+		{Item: 10940, Chance: 75, MinCount: 1, MaxCount: 2},
+		{Item: 10938, GroupID: 1, Chance: 20, MinCount: 1, MaxCount: 1},
+		{Reference: 10, GroupID: 1, MaxCount: 1},
+		{Reference: 11, GroupID: 1, MaxCount: 1},
+	}
+	// Copper Ore rolls a reference of gems twice
+	y.Prospecting[2770] = []LootRow{{Reference: 10, Chance: 100, MaxCount: 2}}
+	y.Reference[10] = []LootRow{{Item: 774, Chance: 50, MinCount: 1, MaxCount: 1}}
+	y.Reference[11] = []LootRow{{Reference: 11, Chance: 100, MaxCount: 1}}
+
+	loot := y.expected(y.Disenchant[1], maxReferenceDepth)
+	// 0.75 * 1.5 dust; 0.2 essences; 0.4 * 0.5 of the gem reference
+	want := map[int]float64{10940: 1.125, 10938: 0.2, 774: 0.2}
+	if len(loot) != len(want) {
+		t.Fatalf("expected() = %v, want %v", loot, want)
+	}
+	for entry, count := range want {
+		if diff := loot[entry] - count; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("expected %d = %v, want %v", entry, loot[entry], count)
+		}
+	}
+
+	auctions := []AuctionItem{
+		{ID: 1, HouseID: 2, ItemEntry: 9776, Count: 1, Quality: 2, BuyoutPrice: 100, UnitBuyout: 100},
+		{ID: 2, HouseID: 2, ItemEntry: 9776, Count: 1, Quality: 2, BuyoutPrice: 500, UnitBuyout: 500},
+		{ID: 3, HouseID: 2, ItemEntry: 10940, Count: 20, BuyoutPrice: 2000, UnitBuyout: 100},
+		{ID: 4, HouseID: 2, ItemEntry: 10938, Count: 1, BuyoutPrice: 300, UnitBuyout: 300},
+		{ID: 5, HouseID: 2, ItemEntry: 2770, Count: 10, BuyoutPrice: 300, UnitBuyout: 30},
+		{ID: 6, HouseID: 2, ItemEntry: 774, Count: 1, BuyoutPrice: 500, UnitBuyout: 500},
+		{ID: 7, HouseID: 6, ItemEntry: 9776, Count: 1, Quality: 2, BuyoutPrice: 10, UnitBuyout: 10},
+		{ID: 8, HouseID: 2, ItemEntry: 2770, Count: 5, StartBid: 10},
+	}
+	houses := []AuctionHouse{{ID: 2, ConsignmentRate: 5}, {ID: 6, ConsignmentRate: 5}}
+
+	listings := FindYieldListings(auctions, y, houses, YieldQuery{House: 2})
+	// A disenchant yields 112.5 + 60 + 100 = 272, 259 after the cut. Ten
+	// ore are prospected twice into a gem each, 1000 or 950 after the cut.
+	wantListings := []struct {
+		id, value, profit int
+		method            string
+		below             bool
+	}{
+		{5, 950, 650, YieldProspecting, true},
+		{1, 259, 159, YieldDisenchant, true},
+		{2, 259, -241, YieldDisenchant, false},
+	}
+	if len(listings) != len(wantListings) {
+		t.Fatalf("FindYieldListings() = %+v, want %d listings", listings, len(wantListings))
+	}
+	for i, w := range wantListings {
+		l := listings[i]
+		if l.ID != w.id || l.YieldValue != w.value || l.Profit != w.profit || l.Method != w.method || l.BelowYield != w.below {
+			t.Errorf("listing %d = %+v, want auction %d by %s worth %d for %d profit", i, l, w.id, w.method, w.value, w.profit)
+		}
+	}
+	if got := listings[1].RequiredSkill; got != 1 {
+		t.Errorf("required skill = %d, want 1", got)
+	}
+
+	// Materials are priced in the house of the listing, where nothing is
+	listings = FindYieldListings(auctions, y, houses, YieldQuery{House: 6})
+	if len(listings) != 1 || listings[0].YieldValue != 0 || listings[0].BelowYield {
+		t.Errorf("horde listings = %+v, want auction 7 worth nothing", listings)
+	}
+
+	listings = FindYieldListings(auctions, y, houses, YieldQuery{Method: YieldDisenchant, BelowYield: true})
+	if len(listings) != 1 || listings[0].ID != 1 {
+		t.Errorf("disenchant flips = %+v, want auction 1", listings)
+	}
+}
+
+func TestDropRate(t *testing.T) {
+	refs := map[int][]LootRow{
+		// Either of two items, each half the time
+		10: {
+			{Item: 1, GroupID: 1, MinCount: 1, MaxCount: 1},
+			{Item: 2, GroupID: 1, MinCount: 1, MaxCount: 1},
+		},
+	}
+	tests := []struct {
+		name             string
+		rows             []LootRow
+		chance, expected float64
+	}{
+		{"ungrouped", []LootRow{{Item: 1, Chance: 25, MinCount: 1, MaxCount: 3}}, 25, 0.5},
+		{"shared group chance", []LootRow{
+			{Item: 3, Chance: 50, GroupID: 1, MinCount: 1, MaxCount: 1},
+			{Item: 1, GroupID: 1, MinCount: 1, MaxCount: 1},
+			{Item: 2, GroupID: 1, MinCount: 1, MaxCount: 1},
+		}, 25, 0.25},
+		// Rolled twice half the time, dropping the item on half the rolls
+		{"reference", []LootRow{{Reference: 10, Chance: 50, MaxCount: 2}}, 25, 0.5},
+		{"other items", []LootRow{{Item: 3, Chance: 100, MinCount: 1, MaxCount: 1}}, 0, 0},
+	}
+	for _, tt := range tests {
+		chance, expected := dropRate(tt.rows, refs, 1)
+		if chance != tt.chance || expected != tt.expected {
+			t.Errorf("%s: dropRate = %v%%, %v; want %v%%, %v", tt.name, chance, expected, tt.chance, tt.expected)
+		}
+	}
+}
+
+func TestFindOverpriced(t *testing.T) {
+	sources := ItemSources{Vendors: []VendorSource{
+		{Entry: 1, Price: 500, BuyCount: 5, ExtendedCost: &ExtendedCost{ID: 2, HonorPoints: 100}},
+		{Entry: 2, Price: 500, BuyCount: 5},
+	}}
+	price := sources.VendorUnitPrice()
+	if price != 100 {
+		t.Fatalf("VendorUnitPrice = %d, want 100", price)
+	}
+
+	auctions := []AuctionItem{
+		{ID: 1, Count: 1, BuyoutPrice: 150, UnitBuyout: 150},
+		{ID: 2, Count: 2, BuyoutPrice: 400, UnitBuyout: 200},
+		{ID: 3, Count: 1, BuyoutPrice: 500, UnitBuyout: 500},
+		{ID: 4, Count: 1, StartBid: 1000},
+	}
+	listings := FindOverpriced(auctions, price, 200)
+	if len(listings) != 2 || listings[0].ID != 3 || listings[0].PercentOfVendor != 500 || listings[1].ID != 2 || listings[1].PercentOfVendor != 200 {
+		t.Errorf("overpriced = %+v, want auctions 3 and 2", listings)
+	}
+
+	// Items only sold for honor have no gold price to compare with
+	honor := ItemSources{Vendors: sources.Vendors[:1]}
+	if listings := FindOverpriced(auctions, honor.VendorUnitPrice(), 200); len(listings) != 0 {
+		t.Errorf("overpriced without a vendor price = %+v, want none", listings)
+	}
+}
+
+// validAHBotConfig returns mod_auctionhousebot's default configuration
+func validAHBotConfig() AHBotConfig {
+	return AHBotConfig{
+		AuctionHouse:         2,
+		MaxItems:             1000,
+		TradeGoodsPercent:    [AHBotQualities]int{0, 27, 12, 10, 1, 0, 0},
+		ItemsPercent:         [AHBotQualities]int{0, 10, 30, 8, 2, 0, 0},
+		MinPrice:             [AHBotQualities]int{100, 150, 800, 1250, 2250, 3250, 5250},
+		MaxPrice:             [AHBotQualities]int{150, 250, 1400, 1750, 4550, 5550, 6550},
+		MinBidPrice:          [AHBotQualities]int{70, 70, 80, 75, 80, 80, 80},
+		MaxBidPrice:          [AHBotQualities]int{100, 100, 100, 100, 100, 100, 100},
+		BuyerPrice:           [AHBotQualities]int{1, 3, 5, 12, 15, 20, 22},
+		BuyerBiddingInterval: 1,
+		BuyerBidsPerInterval: 1,
+	}
+}
+
+func TestAHBotConfigValidate(t *testing.T) {
+	if err := validAHBotConfig().Validate(); err != nil {
+		t.Fatalf("default config: %v", err)
+	}
+
+	for name, change := range map[string]func(c *AHBotConfig){
+		"percentages over 100":  func(c *AHBotConfig) { c.ItemsPercent[2]++ },
+		"percentages under 100": func(c *AHBotConfig) { c.TradeGoodsPercent[1]-- },
+		"negative percentage":   func(c *AHBotConfig) { c.ItemsPercent[0], c.ItemsPercent[1] = -5, 15 },
+		"min over max items":    func(c *AHBotConfig) { c.MinItems = 1001 },
+		"min over max price":    func(c *AHBotConfig) { c.MinPrice[3] = 2000 },
+		"bid over buyout":       func(c *AHBotConfig) { c.MaxBidPrice[4] = 120 },
+		"negative stack":        func(c *AHBotConfig) { c.MaxStack[0] = -1 },
+		"no bidding interval":   func(c *AHBotConfig) { c.BuyerBiddingInterval = 0 },
+	} {
+		c := validAHBotConfig()
+		change(&c)
+		if err := c.Validate(); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+
+	var c AHBotConfig
+	if columns, fields := ahbotColumns(), c.fields(); len(columns) != len(fields) {
+		t.Errorf("%d columns for %d fields", len(columns), len(fields))
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	m := NewMemory()
+	m.AddItem(ItemTemplate{Entry: 2589, Class: 7, Quality: 1})
+	m.AddItem(ItemTemplate{Entry: 2592, Class: 7, Quality: 1})
+	m.AddItem(ItemTemplate{Entry: 6948, Class: 15, Quality: 1})
+	m.AddItem(ItemTemplate{Entry: 19019, Class: 2, Quality: 5})
+	later := int(time.Now().Add(time.Hour).Unix())
+	for i, a := range []AuctionItem{
+		{HouseID: 2, ItemEntry: 2589, Time: later},
+		{HouseID: 2, ItemEntry: 2592, Time: later},
+		{HouseID: 2, ItemEntry: 6948, Time: later},
+		{HouseID: 7, ItemEntry: 19019, Time: later},
+		{HouseID: 7, ItemEntry: 19019, Time: int(time.Now().Add(-time.Hour).Unix())},
+	} {
+		a.ID = i + 1
+		m.AddAuction(a)
+	}
+
+	got, err := m.Distribution(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []HouseDistribution{
+		{House: 2, Total: 3, TradeGoods: [AHBotQualities]int{1: 2}, Items: [AHBotQualities]int{1: 1}},
+		{House: 7, Total: 1, Items: [AHBotQualities]int{5: 1}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Distribution() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCompareMarket(t *testing.T) {
+	scanned := time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC)
+	linen := func(house, count, buyout int) AuctionItem {
+		a := AuctionItem{HouseID: house, ItemEntry: 2589, ItemName: "Linen Cloth", Quality: 1, Count: count, BuyoutPrice: buyout}
+		fillDerived(&a, time.Now())
+		joinMarketPrice(&a, map[int]MarketPrice{2589: {Entry: 2589, AveragePrice: 100, ScannedAt: scanned}})
+		return a
+	}
+	runecloth := AuctionItem{HouseID: 2, ItemEntry: 14047, Quality: 1, Count: 1, BuyoutPrice: 900, UnitBuyout: 900,
+		AuctionatorPrice: 600}
+	auctions := []AuctionItem{
+		linen(2, 10, 800),
+		linen(2, 5, 1000),
+		linen(2, 1, 0),
+		linen(6, 1, 50),
+		runecloth,
+		// Without a market price
+		{HouseID: 2, ItemEntry: 19019, Quality: 5, Count: 1, BuyoutPrice: 1000, UnitBuyout: 1000},
+	}
+
+	// The median is weighted by stack size: ten of the fifteen linen cost 80
+	got := CompareMarket(auctions, CompareQuery{House: 2})
+	want := []MarketComparison{
+		{Entry: 2589, ItemName: "Linen Cloth", Quality: 1, Listings: 2, Units: 15, MinUnitBuyout: 80,
+			MedianUnitBuyout: 80, MarketPrice: 100, ScannedAt: scanned, PercentOfMarket: 80},
+		{Entry: 14047, Quality: 1, Listings: 1, Units: 1, MinUnitBuyout: 900, MedianUnitBuyout: 900,
+			MarketPrice: 600, PercentOfMarket: 150},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CompareMarket() = %+v, want %+v", got, want)
+	}
+
+	got = CompareMarket(auctions, CompareQuery{Desc: true})
+	if len(got) != 2 || got[0].Entry != 14047 || got[1].MinUnitBuyout != 50 || got[1].Listings != 3 {
+		t.Errorf("CompareMarket() of every house, descending = %+v", got)
+	}
+
+	a := linen(2, 1, 100)
+	joinMarketPrice(&a, map[int]MarketPrice{})
+	if a.AuctionatorPrice != 0 || !a.AuctionatorScannedAt.IsZero() {
+		t.Errorf("joinMarketPrice() without a price = %+v", a)
+	}
+}
+
+func TestAuctionatorClassValidate(t *testing.T) {
+	for _, tt := range []struct {
+		config AuctionatorClass
+		valid  bool
+	}{
+		{AuctionatorClass{MaxCount: 0, StackCount: 1}, true},
+		{AuctionatorClass{MaxCount: 20, StackCount: 20}, true},
+		{AuctionatorClass{MaxCount: -1, StackCount: 1}, false},
+		{AuctionatorClass{MaxCount: 1, StackCount: 0}, false},
+	} {
+		if err := tt.config.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate(%+v) = %v, want valid %v", tt.config, err, tt.valid)
+		}
+	}
+}
+
+func TestParseEnchantments(t *testing.T) {
+	// Crusader, a gem in the first socket and the matched socket bonus
+	value := "1900 0 0 0 0 0 3520 0 0 0 0 0 0 0 0 2877 0 0 " + strings.Repeat("0 ", 18)
+	want := []ItemEnchantment{
+		{Slot: 0, Kind: "permanent", ID: 1900},
+		{Slot: 2, Kind: "socket", ID: 3520},
+		{Slot: 5, Kind: "bonus", ID: 2877},
+	}
+	if got := parseEnchantments(value); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseEnchantments() = %+v, want %+v", got, want)
+	}
+	if got := parseEnchantments(""); got != nil {
+		t.Errorf("parseEnchantments(\"\") = %+v, want none", got)
+	}
+}
+
+func TestWeaponDPS(t *testing.T) {
+	// Thunderfury's physical and nature damage
+	damage := []ItemDamage{{Min: 44, Max: 115}, {Min: 16, Max: 30, School: 3}}
+	if dps := weaponDPS(damage, 1900); dps != 53.9 {
+		t.Errorf("weaponDPS() = %v, want 53.9", dps)
+	}
+	if dps := weaponDPS(nil, 0); dps != 0 {
+		t.Errorf("weaponDPS() without a delay = %v, want 0", dps)
+	}
+}
+
+func TestLikeMatcher(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"%linen%", "Bolt of Linen Cloth", true},
+		{"linen", "LINEN", true},
+		{"lin_n", "linen", true},
+		{`50\%`, "50%", true},
+		{`50\%`, "500", false},
+		{"a.c", "abc", false},
+	}
+	for _, tt := range tests {
+		if got := likeMatcher(tt.pattern)(tt.s); got != tt.want {
+			t.Errorf("likeMatcher(%q)(%q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+// BenchmarkFilterWhere searches about as many item templates as a world
+// database holds
+func BenchmarkFilterWhere(b *testing.B) {
+	items := make(map[int]ItemTemplate, 40000)
+	for entry := 1; entry <= 40000; entry++ {
+		items[entry] = ItemTemplate{Entry: entry, Name: fmt.Sprintf("Bolt of Runecloth %d", entry), Class: entry % 16}
+	}
+	f := AuctionFilter{Search: "linen", ItemNameLike: "%cloth 1%", Class: intPtr(7)}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.where(items, RandomProperties{})
+	}
+}
+
+func TestJoinItem(t *testing.T) {
+	items := map[int]ItemTemplate{2589: {Entry: 2589, Name: "Linen Cloth", Quality: 1, ItemLevel: 5, SellPrice: 13}}
+
+	a := joinItem(AuctionItem{ItemEntry: 2589, ItemName: "stale"}, items, RandomProperties{})
+	if !a.hasItem || a.ItemName != "Linen Cloth" || a.Quality != 1 || a.ItemLevel != 5 || a.SellPrice != 13 {
+		t.Errorf("joinItem() = %+v", a)
+	}
+	a = joinItem(AuctionItem{ItemEntry: 1, Quality: 3}, items, RandomProperties{})
+	if a.hasItem || a.ItemName != "Unknown Item" || a.Quality != 0 {
+		t.Errorf("joinItem() of a missing item = %+v", a)
+	}
+}
+
+func TestRandomProperties(t *testing.T) {
+	cinch := ItemTemplate{Entry: 9776, Name: "Bandit Cinch", Quality: 2, ItemLevel: 20, InventoryType: 6}
+	items := map[int]ItemTemplate{cinch.Entry: cinch}
+	props := RandomProperties{
+		Properties: map[int]RandomProperty{
+			-5: {ID: -5, Suffix: "of the Monkey", Enchantments: []RandomEnchantment{
+				{ID: 2802, Allocation: 5263, Effects: []EnchantmentEffect{{Type: enchantmentStat, Arg: 3}}},
+				{ID: 2803, Allocation: 5263, Effects: []EnchantmentEffect{{Type: enchantmentStat, Arg: 7}}},
+			}},
+			23: {ID: 23, Suffix: "of Fire Resistance", Enchantments: []RandomEnchantment{
+				{ID: 1437, Effects: []EnchantmentEffect{{Type: enchantmentResistance, Points: 5, Arg: 2}}},
+			}},
+		},
+		Points: map[int]PropertyPoints{20: {Good: [5]int{17, 13, 10, 7, 5}}},
+	}
+
+	// The suffix shares out the 13 points of an uncommon level 20 waist
+	a := joinItem(AuctionItem{ItemEntry: 9776, RandomPropertyID: -5}, items, props)
+	wantStats := []ItemStat{{Type: 3, Value: 6}, {Type: 7, Value: 6}}
+	if a.ItemName != "Bandit Cinch of the Monkey" || a.Suffix != "of the Monkey" || !reflect.DeepEqual(a.RandomStats, wantStats) {
+		t.Errorf("joinItem() with a suffix = %+v", a.AuctionItem)
+	}
+	a = joinItem(AuctionItem{ItemEntry: 9776, RandomPropertyID: 23}, items, props)
+	if a.ItemName != "Bandit Cinch of Fire Resistance" || !reflect.DeepEqual(a.RandomResistances, []ItemResistance{{School: 2, Value: 5}}) {
+		t.Errorf("joinItem() with a random property = %+v", a.AuctionItem)
+	}
+	a = joinItem(AuctionItem{ItemEntry: 9776, RandomPropertyID: -6}, items, props)
+	if a.ItemName != "Bandit Cinch" || a.Suffix != "" {
+		t.Errorf("joinItem() with an unknown suffix = %+v", a.AuctionItem)
+	}
+
+	where, _ := AuctionFilter{Search: "monkey"}.where(items, props)
+	if !strings.Contains(where, " AND (c.name LIKE ? OR ii.randomPropertyId IN (-5))") {
+		t.Errorf("where = %q, want the suffix searched", where)
+	}
+	where, _ = AuctionFilter{Suffix: "of the"}.where(items, props)
+	if !strings.Contains(where, " AND ii.randomPropertyId IN (-5)") {
+		t.Errorf("where = %q, want the suffix filtered", where)
+	}
+	where, _ = AuctionFilter{Suffix: "of the Eagle"}.where(items, props)
+	if !strings.Contains(where, " AND FALSE") {
+		t.Errorf("where = %q, want no suffix to match", where)
+	}
+}
+
+func TestItemCache(t *testing.T) {
+	ctx := context.Background()
+	world := NewMemory()
+	world.AddItem(ItemTemplate{Entry: 2589, Name: "Linen Cloth"})
+	world.AddItemName(2589, "deDE", "Leinenstoff")
+	c := &ItemCache{world: world, load: world.AllItems, loadNames: world.AllItemNames}
+
+	// Lookups pass through until the first refresh
+	if items, err := c.Items(ctx, []int{2589}); err != nil || items[2589].Name != "Linen Cloth" {
+		t.Fatalf("Items() before refresh = %+v, %v", items, err)
+	}
+
+	status, err := c.Refresh(ctx)
+	if err != nil || status.Items != 1 || status.Names != 1 || status.LoadedAt.IsZero() {
+		t.Fatalf("Refresh() = %+v, %v", status, err)
+	}
+
+	if names, err := c.ItemNames(ctx, "deDE", []int{2589, 4306}); err != nil || len(names) != 1 || names[2589] != "Leinenstoff" {
+		t.Errorf("ItemNames() = %+v, %v", names, err)
+	}
+
+	// Later changes are only seen after the next refresh
+	world.AddItem(ItemTemplate{Entry: 4306, Name: "Silk Cloth"})
+	items, _ := c.Items(ctx, []int{2589, 4306})
+	if len(items) != 1 || items[2589].Name != "Linen Cloth" {
+		t.Errorf("Items() = %+v", items)
+	}
+
+	// A failed refresh keeps the loaded copy
+	world.Err = errors.New("connection refused")
+	if status, err := c.Refresh(ctx); err == nil || status.Items != 1 {
+		t.Errorf("failed Refresh() = %+v, %v", status, err)
+	}
+	if items, err := c.Items(ctx, []int{2589}); err != nil || items[2589].Name != "Linen Cloth" {
+		t.Errorf("Items() after failed refresh = %+v, %v", items, err)
+	}
+}
+
+// TestAuth reads accounts from a scratch MySQL database, which it fills from
+// testdata/auth.sql. Point TEST_MYSQL_DSN at one to run it, such as
+// root:secret@tcp(localhost:3306)/ah_test.
+func TestAuth(t *testing.T) {
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("TEST_MYSQL_DSN is not set")
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	fixture, err := os.ReadFile("testdata/auth.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, statement := range strings.Split(string(fixture), ";\n") {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("loading fixture: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	auth := NewAuth(db)
+	player, err := auth.Account(ctx, "player", 1)
+	if err != nil || player.ID != 1 || player.Username != "PLAYER" || player.GMLevel != 0 || player.Banned {
+		t.Fatalf("Account(player) = %+v, %v", player, err)
+	}
+	if !wow.CheckSRP6Password("player", "hunter2", player.Salt, player.Verifier) {
+		t.Error("the fixture password of PLAYER was rejected")
+	}
+
+	// Levels on the realm and on every realm count, others do not
+	if gm, err := auth.Account(ctx, "GM", 1); err != nil || gm.GMLevel != 3 {
+		t.Errorf("Account(GM, 1) = %+v, %v", gm, err)
+	}
+	if gm, err := auth.Account(ctx, "GM", 2); err != nil || gm.GMLevel != 1 {
+		t.Errorf("Account(GM, 2) = %+v, %v", gm, err)
+	}
+	if banned, err := auth.Account(ctx, "banned", 1); err != nil || !banned.Banned {
+		t.Errorf("Account(banned) = %+v, %v", banned, err)
+	}
+	// A temporary ban ends at its unbandate
+	if paroled, err := auth.Account(ctx, "paroled", 1); err != nil || paroled.Banned {
+		t.Errorf("Account(paroled) = %+v, %v", paroled, err)
+	}
+	if _, err := auth.Account(ctx, "nobody", 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Account(nobody) error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,47 @@
+-- Accounts of acore_auth for TestAuth, with the columns the app reads.
+-- Passwords: PLAYER/hunter2, GM/gmpass, BANNED/banned and PAROLED/paroled.
+DROP TABLE IF EXISTS account;
+CREATE TABLE account (
+  id int unsigned NOT NULL AUTO_INCREMENT,
+  username varchar(32) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  salt binary(32) NOT NULL,
+  verifier binary(32) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY idx_username (username)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+DROP TABLE IF EXISTS account_access;
+CREATE TABLE account_access (
+  id int unsigned NOT NULL,
+  gmlevel tinyint unsigned NOT NULL,
+  RealmID int NOT NULL DEFAULT '-1',
+  PRIMARY KEY (id, RealmID)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+DROP TABLE IF EXISTS account_banned;
+CREATE TABLE account_banned (
+  id int unsigned NOT NULL DEFAULT '0',
+  bandate int unsigned NOT NULL DEFAULT '0',
+  unbandate int unsigned NOT NULL DEFAULT '0',
+  active tinyint unsigned NOT NULL DEFAULT '1',
+  PRIMARY KEY (id, bandate)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO account (id, username, salt, verifier) VALUES
+  (1, 'PLAYER', UNHEX('0102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F20'),
+    UNHEX('F98660F1B3718DBE569E0A0DDA7FE59BD8B3C656CA1383DF4A166DA916781956')),
+  (2, 'GM', UNHEX('AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA'),
+    UNHEX('89FDF6C9F4A83C1433E94E31743B92CFAD52F04E8531D6FD272E7C100F3BE151')),
+  (3, 'BANNED', UNHEX('5555555555555555555555555555555555555555555555555555555555555555'),
+    UNHEX('7AC2B07DAE3108A1CB6AC3B30A1A47D458DC397662E2B543C5BB210C5AFC3C4E')),
+  (4, 'PAROLED', UNHEX('6666666666666666666666666666666666666666666666666666666666666666'),
+    UNHEX('C1F543176202164880F3245297CEEFD74206BF4FD0B650EEF1513A8B50307362'));
+
+INSERT INTO account_access (id, gmlevel, RealmID) VALUES (2, 1, -1), (2, 3, 1);
+
+-- BANNED is banned for good, PAROLED served a week in 2023 and PLAYER's ban
+-- was lifted by hand
+INSERT INTO account_banned (id, bandate, unbandate, active) VALUES
+  (3, 1700000000, 1700000000, 1),
+  (4, 1690000000, 1690604800, 1),
+  (1, 1600000000, 1600000000, 0);
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ItemTooltip is what the game's tooltip shows of an item template. Spells,
+// enchantments and set pieces are resolved in the world database's DBC
+// tables, which only hold the rows imported into them; spells and
+// enchantments missing there keep their IDs and no text.
+type ItemTooltip struct {
+	Entry         int    `json:"entry"`
+	Name          string `json:"name"`
+	Quality       int    `json:"quality"`
+	ItemLevel     int    `json:"item_level"`
+	RequiredLevel int    `json:"required_level"`
+	Class         int    `json:"class"`
+	Subclass      int    `json:"subclass"`
+	InventoryType int    `json:"inventory_type"`
+	// Bonding is item_template.bonding: 1 binds when picked up, 2 when
+	// equipped, 3 when used and 4 is a quest item
+	Bonding int `json:"bonding"`
+	// MaxCount is how many of the item a character may carry, 0 for any
+	MaxCount       int              `json:"max_count"`
+	Armor          int              `json:"armor"`
+	Block          int              `json:"block"`
+	Damage         []ItemDamage     `json:"damage,omitempty"`
+	Delay          int              `json:"delay"`
+	DPS            float64          `json:"dps"`
+	Stats          []ItemStat       `json:"stats,omitempty"`
+	Resistances    []ItemResistance `json:"resistances,omitempty"`
+	Sockets        []int            `json:"sockets,omitempty"`
+	SocketBonus    *ItemEnchantment `json:"socket_bonus,omitempty"`
+	Spells         []ItemSpell      `json:"spells,omitempty"`
+	Set            *ItemSet         `json:"set,omitempty"`
+	MaxDurability  int              `json:"max_durability"`
+	ContainerSlots int              `json:"container_slots"`
+	Description    string           `json:"description,omitempty"`
+	SellPrice      int              `json:"sell_price"`
+	// Enchantments are those of one item instance, on auction tooltips
+	Enchantments []ItemEnchantment `json:"enchantments,omitempty"`
+}
+
+// ItemDamage is one damage range of a weapon
+type ItemDamage struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+	// School is 0 for physical damage, then holy, fire, nature, frost,
+	// shadow and arcane
+	School int `json:"school"`
+}
+
+// ItemStat is one stat_type/stat_value pair of item_template
+type ItemStat struct {
+	Type  int `json:"type"`
+	Value int `json:"value"`
+}
+
+// ItemResistance is a resistance an item grants, by school as in ItemDamage
+type ItemResistance struct {
+	School int `json:"school"`
+	Value  int `json:"value"`
+}
+
+// ItemSpell is a spell an item casts, with its text from spell_dbc
+type ItemSpell struct {
+	ID int `json:"id"`
+	// Trigger is item_template.spelltrigger: 0 on use, 1 on equip, 2 on a
+	// chance on hit, 5 when learned and 6 as a recipe
+	Trigger     int    `json:"trigger"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ItemSet lists the pieces of the item set an item belongs to. Set names and
+// bonuses are in ItemSet.dbc, which the world database does not hold.
+type ItemSet struct {
+	ID     int            `json:"id"`
+	Pieces []ItemSetPiece `json:"pieces"`
+}
+
+// ItemSetPiece is one item of a set
+type ItemSetPiece struct {
+	Entry int    `json:"entry"`
+	Name  string `json:"name"`
+}
+
+// ItemEnchantment is an enchantment from spellitemenchantment_dbc, in one of
+// an item instance's enchantment slots
+type ItemEnchantment struct {
+	Slot int `json:"slot"`
+	// Kind names the slot: permanent, temporary, socket, bonus, prismatic
+	// or property
+	Kind string `json:"kind"`
+	ID   int    `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// ItemInstance is the item_instance of an auction
+type ItemInstance struct {
+	AuctionID int `json:"auction_id"`
+	ItemGUID  int `json:"item_guid"`
+	ItemEntry int `json:"item_entry"`
+	Count     int `json:"count"`
+	// Enchantments hold the enchantment IDs of the occupied slots, with
+	// their names left empty
+	Enchantments     []ItemEnchantment `json:"enchantments"`
+	RandomPropertyID int               `json:"random_property_id"`
+	// Random is what the item's random property or suffix adds to it, when
+	// it has a known one
+	Random *RandomBonus `json:"random,omitempty"`
+}
+
+// enchantmentSlotKinds names the enchantment slots of item_instance, as the
+// worldserver numbers them
+var enchantmentSlotKinds = []string{
+	"permanent", "temporary", "socket", "socket", "socket", "bonus", "prismatic",
+	"property", "property", "property", "property", "property",
+}
+
+// bonusEnchantmentSlot holds the socket bonus once every socket matches
+const bonusEnchantmentSlot = 5
+
+// parseEnchantments reads item_instance.enchantments, which holds an
+// enchantment ID, duration and charges for every slot, separated by spaces.
+// Empty slots are left out.
+func parseEnchantments(value string) []ItemEnchantment {
+	var enchantments []ItemEnchantment
+	fields := strings.Fields(value)
+	for slot := 0; slot < len(enchantmentSlotKinds) && slot*3 < len(fields); slot++ {
+		id, err := strconv.Atoi(fields[slot*3])
+		if err != nil || id <= 0 {
+			continue
+		}
+		enchantments = append(enchantments, ItemEnchantment{Slot: slot, Kind: enchantmentSlotKinds[slot], ID: id})
+	}
+	return enchantments
+}
+
+// weaponDPS is the damage per second of a weapon's damage ranges swung every
+// delay milliseconds, to one decimal as the game shows it
+func weaponDPS(damage []ItemDamage, delay int) float64 {
+	if delay <= 0 {
+		return 0
+	}
+	var average float64
+	for _, d := range damage {
+		average += (d.Min + d.Max) / 2
+	}
+	return math.Round(average/(float64(delay)/1000)*10) / 10
+}
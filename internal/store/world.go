@@ -0,0 +1,1391 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/wow"
+)
+
+// itemBatchSize caps the entries looked up by one item_template query
+const itemBatchSize = 500
+
+// World reads static game data from the AzerothCore world database. It may
+// live on a different server than the characters database, so it is never
+// joined in SQL.
+type World struct {
+	db *sql.DB
+}
+
+var _ ItemSource = (*World)(nil)
+
+// NewWorld returns a client for the world database db
+func NewWorld(db *sql.DB) *World {
+	return &World{db: db}
+}
+
+// itemColumns are the item_template columns scanned by scanItem
+const itemColumns = `
+	entry, name, Quality, ItemLevel, RequiredLevel, class, subclass,
+	InventoryType, COALESCE(stackable, 1), BuyCount, BuyPrice, SellPrice`
+
+func scanItem(row interface{ Scan(...interface{}) error }) (ItemTemplate, error) {
+	var item ItemTemplate
+	err := row.Scan(
+		&item.Entry, &item.Name, &item.Quality, &item.ItemLevel, &item.RequiredLevel,
+		&item.Class, &item.Subclass, &item.InventoryType, &item.Stackable, &item.BuyCount,
+		&item.BuyPrice, &item.SellPrice,
+	)
+	return item, err
+}
+
+// Items returns the templates of the given entries, keyed by entry. Entries
+// without a template are left out.
+func (w *World) Items(ctx context.Context, entries []int) (map[int]ItemTemplate, error) {
+	items := make(map[int]ItemTemplate, len(entries))
+	for start := 0; start < len(entries); start += itemBatchSize {
+		batch := entries[start:min(start+itemBatchSize, len(entries))]
+		args := make([]interface{}, len(batch))
+		for i, entry := range batch {
+			args[i] = entry
+		}
+		query := `SELECT` + itemColumns + ` FROM item_template WHERE entry IN (?` + strings.Repeat(", ?", len(batch)-1) + `)`
+
+		rows, err := w.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			item, err := scanItem(rows)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scanning item: %w", err)
+			}
+			items[item.Entry] = item
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+// ItemNames returns the names of the given entries translated into locale,
+// keyed by entry. Untranslated entries are left out.
+func (w *World) ItemNames(ctx context.Context, locale string, entries []int) (map[int]string, error) {
+	names := make(map[int]string)
+	for start := 0; start < len(entries); start += itemBatchSize {
+		batch := entries[start:min(start+itemBatchSize, len(entries))]
+		args := make([]interface{}, 0, len(batch)+1)
+		args = append(args, locale)
+		for _, entry := range batch {
+			args = append(args, entry)
+		}
+		query := `SELECT ID, Name FROM item_template_locale WHERE locale = ? AND Name <> '' AND ID IN (?` +
+			strings.Repeat(", ?", len(batch)-1) + `)`
+
+		if err := w.scanNames(ctx, names, query, args...); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// AllItemNames returns every translated item name, by locale and entry
+func (w *World) AllItemNames(ctx context.Context) (map[string]map[int]string, error) {
+	rows, err := w.db.QueryContext(ctx, `SELECT locale, ID, Name FROM item_template_locale WHERE Name <> ''`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[string]map[int]string)
+	for rows.Next() {
+		var (
+			locale, name string
+			entry        int
+		)
+		if err := rows.Scan(&locale, &entry, &name); err != nil {
+			return nil, fmt.Errorf("scanning item name: %w", err)
+		}
+		if names[locale] == nil {
+			names[locale] = make(map[int]string)
+		}
+		names[locale][entry] = name
+	}
+	return names, rows.Err()
+}
+
+// scanNames adds the entry and name rows of query to names
+func (w *World) scanNames(ctx context.Context, names map[int]string, query string, args ...interface{}) error {
+	rows, err := w.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			entry int
+			name  string
+		)
+		if err := rows.Scan(&entry, &name); err != nil {
+			return fmt.Errorf("scanning item name: %w", err)
+		}
+		names[entry] = name
+	}
+	return rows.Err()
+}
+
+// AllItems returns every item template
+func (w *World) AllItems(ctx context.Context) ([]ItemTemplate, error) {
+	rows, err := w.db.QueryContext(ctx, `SELECT`+itemColumns+` FROM item_template`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []ItemTemplate
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning item: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// Houses lists the Alliance, Horde and neutral auction houses
+func (w *World) Houses(ctx context.Context) ([]AuctionHouse, error) {
+	query := `
+		SELECT ID, COALESCE(Name_Lang_enUS, ''), FactionID, DepositRate, ConsignmentRate
+		FROM auctionhouse_dbc
+		WHERE ID IN (?, ?, ?)
+		ORDER BY ID
+	`
+
+	rows, err := w.db.QueryContext(ctx, query, wow.HouseAlliance, wow.HouseHorde, wow.HouseNeutral)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	houses := []AuctionHouse{}
+	for rows.Next() {
+		var house AuctionHouse
+		err := rows.Scan(&house.ID, &house.Name, &house.FactionID, &house.DepositRate, &house.ConsignmentRate)
+		if err != nil {
+			return nil, fmt.Errorf("scanning auction house: %w", err)
+		}
+		houses = append(houses, house)
+	}
+	return houses, rows.Err()
+}
+
+var _ AHBotRepository = (*World)(nil)
+
+// ahbotSelect selects the configurations of mod_auctionhousebot. The
+// settings columns are nullable, with the module's defaults filled in on
+// insert.
+func ahbotSelect() string {
+	columns := ahbotColumns()
+	for i, column := range columns {
+		columns[i] = "COALESCE(" + column + ", 0)"
+	}
+	return `SELECT auctionhouse, COALESCE(name, ''), ` + strings.Join(columns, ", ") + ` FROM mod_auctionhousebot`
+}
+
+func scanAHBotConfig(row interface{ Scan(...interface{}) error }) (AHBotConfig, error) {
+	var config AHBotConfig
+	err := row.Scan(append([]interface{}{&config.AuctionHouse, &config.Name}, config.fields()...)...)
+	if err == sql.ErrNoRows {
+		return config, ErrNotFound
+	}
+	return config, err
+}
+
+func (w *World) AHBotConfigs(ctx context.Context) ([]AHBotConfig, error) {
+	rows, err := w.db.QueryContext(ctx, ahbotSelect()+` ORDER BY auctionhouse`)
+	if isMissingTable(err) {
+		return []AHBotConfig{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	configs := []AHBotConfig{}
+	for rows.Next() {
+		config, err := scanAHBotConfig(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning auction house bot config: %w", err)
+		}
+		configs = append(configs, config)
+	}
+	return configs, rows.Err()
+}
+
+func (w *World) AHBotConfig(ctx context.Context, house int) (AHBotConfig, error) {
+	config, err := scanAHBotConfig(w.db.QueryRowContext(ctx, ahbotSelect()+` WHERE auctionhouse = ?`, house))
+	if isMissingTable(err) {
+		return config, ErrNotFound
+	}
+	return config, err
+}
+
+func (w *World) UpdateAHBotConfig(ctx context.Context, config AHBotConfig) (AHBotConfig, error) {
+	columns := ahbotColumns()
+	for i, column := range columns {
+		columns[i] = column + " = ?"
+	}
+	// The driver reads the values through the pointers fields returns
+	args := append(config.fields(), config.AuctionHouse)
+
+	query := `UPDATE mod_auctionhousebot SET ` + strings.Join(columns, ", ") + ` WHERE auctionhouse = ?`
+	if _, err := w.db.ExecContext(ctx, query, args...); err != nil {
+		return config, err
+	}
+	// Updates that change nothing affect no rows, so a missing house is
+	// detected when reading it back
+	return w.AHBotConfig(ctx, config.AuctionHouse)
+}
+
+func (w *World) AHBotDisabledItems(ctx context.Context) ([]DisabledItem, error) {
+	return w.disabledItems(ctx, "mod_auctionhousebot_disabled_items")
+}
+
+func (w *World) DisableAHBotItem(ctx context.Context, entry int) error {
+	return w.disableItem(ctx, "mod_auctionhousebot_disabled_items", entry)
+}
+
+func (w *World) EnableAHBotItem(ctx context.Context, entry int) error {
+	return w.enableItem(ctx, "mod_auctionhousebot_disabled_items", entry)
+}
+
+// disabledItems lists the items in one of the modules' disabled items
+// tables, which have an item column only
+func (w *World) disabledItems(ctx context.Context, table string) ([]DisabledItem, error) {
+	query := `
+		SELECT d.item, COALESCE(it.name, '')
+		FROM ` + table + ` d
+		LEFT JOIN item_template it ON it.entry = d.item
+		ORDER BY d.item
+	`
+
+	rows, err := w.db.QueryContext(ctx, query)
+	if isMissingTable(err) {
+		return []DisabledItem{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []DisabledItem{}
+	for rows.Next() {
+		var item DisabledItem
+		if err := rows.Scan(&item.Entry, &item.Name); err != nil {
+			return nil, fmt.Errorf("scanning disabled item: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (w *World) disableItem(ctx context.Context, table string, entry int) error {
+	_, err := w.db.ExecContext(ctx, `INSERT IGNORE INTO `+table+` (item) VALUES (?)`, entry)
+	return err
+}
+
+func (w *World) enableItem(ctx context.Context, table string, entry int) error {
+	res, err := w.db.ExecContext(ctx, `DELETE FROM `+table+` WHERE item = ?`, entry)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+var _ AuctionatorRepository = (*World)(nil)
+
+// auctionatorClassSelect selects mod_auctionator's item class configuration.
+// Classes are named by their subclass row in mod_auctionator_item_class,
+// falling back to the class row, whose subclass is NULL.
+const auctionatorClassSelect = `
+	SELECT cc.class, cc.subclass, COALESCE(sc.name, c.name, ''), cc.bonding, cc.max_count, cc.stack_count
+	FROM mod_auctionator_itemclass_config cc
+	LEFT JOIN mod_auctionator_item_class sc ON sc.class = cc.class AND sc.subclass = cc.subclass
+	LEFT JOIN mod_auctionator_item_class c ON c.class = cc.class AND c.subclass IS NULL
+`
+
+func scanAuctionatorClass(row interface{ Scan(...interface{}) error }) (AuctionatorClass, error) {
+	var config AuctionatorClass
+	err := row.Scan(&config.Class, &config.Subclass, &config.Name, &config.Bonding, &config.MaxCount, &config.StackCount)
+	if err == sql.ErrNoRows {
+		return config, ErrNotFound
+	}
+	return config, err
+}
+
+func (w *World) AuctionatorClasses(ctx context.Context) ([]AuctionatorClass, error) {
+	rows, err := w.db.QueryContext(ctx, auctionatorClassSelect+` ORDER BY cc.class, cc.subclass`)
+	if isMissingTable(err) {
+		return []AuctionatorClass{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	classes := []AuctionatorClass{}
+	for rows.Next() {
+		config, err := scanAuctionatorClass(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning item class config: %w", err)
+		}
+		classes = append(classes, config)
+	}
+	return classes, rows.Err()
+}
+
+func (w *World) AuctionatorClass(ctx context.Context, class, subclass int) (AuctionatorClass, error) {
+	row := w.db.QueryRowContext(ctx, auctionatorClassSelect+` WHERE cc.class = ? AND cc.subclass = ?`, class, subclass)
+	config, err := scanAuctionatorClass(row)
+	if isMissingTable(err) {
+		return config, ErrNotFound
+	}
+	return config, err
+}
+
+func (w *World) UpdateAuctionatorClass(ctx context.Context, config AuctionatorClass) (AuctionatorClass, error) {
+	query := `UPDATE mod_auctionator_itemclass_config SET max_count = ?, stack_count = ? WHERE class = ? AND subclass = ?`
+	_, err := w.db.ExecContext(ctx, query, config.MaxCount, config.StackCount, config.Class, config.Subclass)
+	if err != nil {
+		return config, err
+	}
+	// As with UpdateAHBotConfig, a missing class is detected when reading
+	// it back
+	return w.AuctionatorClass(ctx, config.Class, config.Subclass)
+}
+
+func (w *World) AuctionatorDisabledItems(ctx context.Context) ([]DisabledItem, error) {
+	return w.disabledItems(ctx, "mod_auctionator_disabled_items")
+}
+
+func (w *World) DisableAuctionatorItem(ctx context.Context, entry int) error {
+	return w.disableItem(ctx, "mod_auctionator_disabled_items", entry)
+}
+
+func (w *World) EnableAuctionatorItem(ctx context.Context, entry int) error {
+	return w.enableItem(ctx, "mod_auctionator_disabled_items", entry)
+}
+
+var _ TooltipRepository = (*World)(nil)
+
+// resistanceSchools are the schools of item_template's resistance columns,
+// numbered as ItemDamage.School
+var resistanceSchools = []string{"holy", "fire", "nature", "frost", "shadow", "arcane"}
+
+// dbcLocale returns the locale suffix of the _Lang_ columns of the DBC
+// tables for locale, which becomes part of the query
+func dbcLocale(locale string) string {
+	for _, l := range wow.Locales {
+		if l == locale {
+			return l
+		}
+	}
+	return wow.DefaultLocale
+}
+
+// ItemTooltip reads an item template with the spells it casts from
+// spell_dbc, its socket bonus from spellitemenchantment_dbc and the pieces
+// of its set
+func (w *World) ItemTooltip(ctx context.Context, entry int, locale string) (ItemTooltip, error) {
+	var (
+		t           ItemTooltip
+		itemSet     int
+		socketBonus int
+		damage      [2]ItemDamage
+		resistances [6]int
+		stats       [10]ItemStat
+		spells      [5]ItemSpell
+		sockets     [3]int
+	)
+	columns := []string{
+		"it.entry", "COALESCE(NULLIF(l.Name, ''), it.name)", "it.Quality", "it.ItemLevel", "it.RequiredLevel",
+		"it.class", "it.subclass", "it.InventoryType", "it.bonding", "it.maxcount", "it.armor", "it.block",
+		"it.delay", "it.MaxDurability", "it.ContainerSlots", "COALESCE(NULLIF(l.Description, ''), it.description)",
+		"it.SellPrice", "it.itemset", "it.socketBonus",
+	}
+	dest := []interface{}{
+		&t.Entry, &t.Name, &t.Quality, &t.ItemLevel, &t.RequiredLevel,
+		&t.Class, &t.Subclass, &t.InventoryType, &t.Bonding, &t.MaxCount, &t.Armor, &t.Block,
+		&t.Delay, &t.MaxDurability, &t.ContainerSlots, &t.Description,
+		&t.SellPrice, &itemSet, &socketBonus,
+	}
+	for i := range damage {
+		columns = append(columns, fmt.Sprintf("it.dmg_min%[1]d, it.dmg_max%[1]d, it.dmg_type%[1]d", i+1))
+		dest = append(dest, &damage[i].Min, &damage[i].Max, &damage[i].School)
+	}
+	for i, school := range resistanceSchools {
+		columns = append(columns, "it."+school+"_res")
+		dest = append(dest, &resistances[i])
+	}
+	for i := range stats {
+		columns = append(columns, fmt.Sprintf("it.stat_type%[1]d, it.stat_value%[1]d", i+1))
+		dest = append(dest, &stats[i].Type, &stats[i].Value)
+	}
+	for i := range spells {
+		columns = append(columns, fmt.Sprintf("it.spellid_%[1]d, it.spelltrigger_%[1]d", i+1))
+		dest = append(dest, &spells[i].ID, &spells[i].Trigger)
+	}
+	for i := range sockets {
+		columns = append(columns, fmt.Sprintf("it.socketColor_%d", i+1))
+		dest = append(dest, &sockets[i])
+	}
+	query := `SELECT ` + strings.Join(columns, ", ") + `
+		FROM item_template it
+		LEFT JOIN item_template_locale l ON l.ID = it.entry AND l.locale = ?
+		WHERE it.entry = ?`
+
+	err := w.db.QueryRowContext(ctx, query, locale, entry).Scan(dest...)
+	if err == sql.ErrNoRows {
+		return t, ErrNotFound
+	}
+	if err != nil {
+		return t, err
+	}
+
+	for _, d := range damage {
+		if d.Max > 0 {
+			t.Damage = append(t.Damage, d)
+		}
+	}
+	t.DPS = weaponDPS(t.Damage, t.Delay)
+	for school, value := range resistances {
+		if value != 0 {
+			t.Resistances = append(t.Resistances, ItemResistance{School: school + 1, Value: value})
+		}
+	}
+	for _, stat := range stats {
+		if stat.Value != 0 {
+			t.Stats = append(t.Stats, stat)
+		}
+	}
+	for _, color := range sockets {
+		if color != 0 {
+			t.Sockets = append(t.Sockets, color)
+		}
+	}
+	for _, spell := range spells {
+		if spell.ID > 0 {
+			t.Spells = append(t.Spells, spell)
+		}
+	}
+
+	if err := w.describeSpells(ctx, locale, t.Spells); err != nil {
+		return t, err
+	}
+	if socketBonus > 0 {
+		names, err := w.EnchantmentNames(ctx, locale, []int{socketBonus})
+		if err != nil {
+			return t, err
+		}
+		t.SocketBonus = &ItemEnchantment{
+			Slot: bonusEnchantmentSlot, Kind: enchantmentSlotKinds[bonusEnchantmentSlot],
+			ID: socketBonus, Name: names[socketBonus],
+		}
+	}
+	if itemSet > 0 {
+		if t.Set, err = w.itemSet(ctx, itemSet, locale); err != nil {
+			return t, err
+		}
+	}
+	return t, nil
+}
+
+// describeSpells fills in the names and descriptions of spells found in
+// spell_dbc
+func (w *World) describeSpells(ctx context.Context, locale string, spells []ItemSpell) error {
+	if len(spells) == 0 {
+		return nil
+	}
+	args := make([]interface{}, len(spells))
+	for i, spell := range spells {
+		args[i] = spell.ID
+	}
+	lang := dbcLocale(locale)
+	query := `
+		SELECT ID,
+			COALESCE(NULLIF(Name_Lang_` + lang + `, ''), Name_Lang_enUS, ''),
+			COALESCE(NULLIF(Description_Lang_` + lang + `, ''), Description_Lang_enUS, '')
+		FROM spell_dbc
+		WHERE ID IN (?` + strings.Repeat(", ?", len(args)-1) + `)`
+
+	rows, err := w.db.QueryContext(ctx, query, args...)
+	if isMissingTable(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id                int
+			name, description string
+		)
+		if err := rows.Scan(&id, &name, &description); err != nil {
+			return fmt.Errorf("scanning spell: %w", err)
+		}
+		for i := range spells {
+			if spells[i].ID == id {
+				spells[i].Name, spells[i].Description = name, description
+			}
+		}
+	}
+	return rows.Err()
+}
+
+// itemSet lists the pieces of an item set, named as item_set_names names
+// them unless translated
+func (w *World) itemSet(ctx context.Context, id int, locale string) (*ItemSet, error) {
+	query := `
+		SELECT it.entry, COALESCE(NULLIF(l.Name, ''), sn.name, it.name)
+		FROM item_template it
+		LEFT JOIN item_set_names sn ON sn.entry = it.entry
+		LEFT JOIN item_template_locale l ON l.ID = it.entry AND l.locale = ?
+		WHERE it.itemset = ?
+		ORDER BY it.InventoryType, it.entry
+	`
+
+	rows, err := w.db.QueryContext(ctx, query, locale, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	set := &ItemSet{ID: id}
+	for rows.Next() {
+		var piece ItemSetPiece
+		if err := rows.Scan(&piece.Entry, &piece.Name); err != nil {
+			return nil, fmt.Errorf("scanning item set piece: %w", err)
+		}
+		set.Pieces = append(set.Pieces, piece)
+	}
+	return set, rows.Err()
+}
+
+// EnchantmentNames reads spellitemenchantment_dbc. Without the table, no
+// enchantment has a name.
+func (w *World) EnchantmentNames(ctx context.Context, locale string, ids []int) (map[int]string, error) {
+	names := make(map[int]string)
+	if len(ids) == 0 {
+		return names, nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	lang := dbcLocale(locale)
+	query := `
+		SELECT ID, COALESCE(NULLIF(Name_Lang_` + lang + `, ''), Name_Lang_enUS, '')
+		FROM spellitemenchantment_dbc
+		WHERE ID IN (?` + strings.Repeat(", ?", len(args)-1) + `)`
+
+	rows, err := w.db.QueryContext(ctx, query, args...)
+	if isMissingTable(err) {
+		return names, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id   int
+			name string
+		)
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("scanning enchantment: %w", err)
+		}
+		names[id] = name
+	}
+	return names, rows.Err()
+}
+
+// RandomProperties reads itemrandomproperties_dbc and itemrandomsuffix_dbc
+// with the effects of their enchantments from spellitemenchantment_dbc, and
+// randproppoints_dbc. Missing tables leave their part empty, so items keep
+// their plain names.
+func (w *World) RandomProperties(ctx context.Context, locale string) (RandomProperties, error) {
+	props := RandomProperties{Properties: make(map[int]RandomProperty), Points: make(map[int]PropertyPoints)}
+	lang := dbcLocale(locale)
+	name := `COALESCE(NULLIF(Name_Lang_` + lang + `, ''), Name_Lang_enUS, '')`
+
+	err := w.scanRandomProperties(ctx, props, 1, `
+		SELECT ID, `+name+`,
+			Enchantment_1, Enchantment_2, Enchantment_3, Enchantment_4, Enchantment_5,
+			0, 0, 0, 0, 0
+		FROM itemrandomproperties_dbc`)
+	if err != nil {
+		return props, err
+	}
+	err = w.scanRandomProperties(ctx, props, -1, `
+		SELECT ID, `+name+`,
+			Enchantment_1, Enchantment_2, Enchantment_3, Enchantment_4, Enchantment_5,
+			AllocationPct_1, AllocationPct_2, AllocationPct_3, AllocationPct_4, AllocationPct_5
+		FROM itemrandomsuffix_dbc`)
+	if err != nil {
+		return props, err
+	}
+	if err := w.enchantmentEffects(ctx, props); err != nil {
+		return props, err
+	}
+
+	rows, err := w.db.QueryContext(ctx, `
+		SELECT ID, Epic_1, Epic_2, Epic_3, Epic_4, Epic_5,
+			Superior_1, Superior_2, Superior_3, Superior_4, Superior_5,
+			Good_1, Good_2, Good_3, Good_4, Good_5
+		FROM randproppoints_dbc`)
+	if isMissingTable(err) {
+		return props, nil
+	}
+	if err != nil {
+		return props, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			level int
+			p     PropertyPoints
+		)
+		dest := []interface{}{&level}
+		for _, column := range []*[5]int{&p.Epic, &p.Superior, &p.Good} {
+			for i := range column {
+				dest = append(dest, &column[i])
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return props, fmt.Errorf("scanning property points: %w", err)
+		}
+		props.Points[level] = p
+	}
+	return props, rows.Err()
+}
+
+// scanRandomProperties adds the rows of a query selecting the ID, name, five
+// enchantments and five allocations of random properties to props, keyed by
+// the ID times sign
+func (w *World) scanRandomProperties(ctx context.Context, props RandomProperties, sign int, query string) error {
+	rows, err := w.db.QueryContext(ctx, query)
+	if isMissingTable(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			p           RandomProperty
+			enchants    [5]int
+			allocations [5]int
+		)
+		if err := rows.Scan(&p.ID, &p.Suffix,
+			&enchants[0], &enchants[1], &enchants[2], &enchants[3], &enchants[4],
+			&allocations[0], &allocations[1], &allocations[2], &allocations[3], &allocations[4],
+		); err != nil {
+			return fmt.Errorf("scanning random property: %w", err)
+		}
+		p.ID *= sign
+		for i, id := range enchants {
+			if id > 0 {
+				p.Enchantments = append(p.Enchantments, RandomEnchantment{ID: id, Allocation: allocations[i]})
+			}
+		}
+		props.Properties[p.ID] = p
+	}
+	return rows.Err()
+}
+
+// enchantmentEffects fills in the effects of the enchantments of props from
+// spellitemenchantment_dbc
+func (w *World) enchantmentEffects(ctx context.Context, props RandomProperties) error {
+	var ids []int
+	seen := make(map[int]bool)
+	for _, p := range props.Properties {
+		for _, e := range p.Enchantments {
+			if !seen[e.ID] {
+				seen[e.ID] = true
+				ids = append(ids, e.ID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := w.db.QueryContext(ctx, `
+		SELECT ID, Effect_1, EffectPointsMin_1, EffectArg_1,
+			Effect_2, EffectPointsMin_2, EffectArg_2,
+			Effect_3, EffectPointsMin_3, EffectArg_3
+		FROM spellitemenchantment_dbc
+		WHERE ID IN `+entryList(ids))
+	if isMissingTable(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	effects := make(map[int][]EnchantmentEffect)
+	for rows.Next() {
+		var (
+			id int
+			e  [3]EnchantmentEffect
+		)
+		if err := rows.Scan(&id, &e[0].Type, &e[0].Points, &e[0].Arg,
+			&e[1].Type, &e[1].Points, &e[1].Arg, &e[2].Type, &e[2].Points, &e[2].Arg,
+		); err != nil {
+			return fmt.Errorf("scanning enchantment effects: %w", err)
+		}
+		for _, effect := range e {
+			if effect.Type != 0 {
+				effects[id] = append(effects[id], effect)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, p := range props.Properties {
+		for i, e := range p.Enchantments {
+			p.Enchantments[i].Effects = effects[e.ID]
+		}
+		props.Properties[id] = p
+	}
+	return nil
+}
+
+var _ CraftingRepository = (*World)(nil)
+
+// professionCategories are the skillline_dbc categories of professions and
+// secondary skills
+const professionCategories = `(9, 11)`
+
+// Professions reads skillline_dbc, leaving out the skills without a recipe
+// that creates an item, such as fishing. Without the table there are none.
+func (w *World) Professions(ctx context.Context, locale string) ([]Profession, error) {
+	lang := dbcLocale(locale)
+	query := `
+		SELECT s.ID, COALESCE(NULLIF(s.DisplayName_Lang_` + lang + `, ''), s.DisplayName_Lang_enUS, '') AS name
+		FROM skillline_dbc s
+		WHERE s.CategoryID IN ` + professionCategories + `
+			AND EXISTS (
+				SELECT 1 FROM skilllineability_dbc sla
+				JOIN spell_dbc sp ON sp.ID = sla.Spell
+				WHERE sla.SkillLine = s.ID AND ` + fmt.Sprintf("%d IN (sp.Effect_1, sp.Effect_2, sp.Effect_3)", createItemEffect) + `
+			)
+		ORDER BY name`
+
+	rows, err := w.db.QueryContext(ctx, query)
+	if isMissingTable(err) {
+		return []Profession{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	professions := []Profession{}
+	for rows.Next() {
+		var p Profession
+		if err := rows.Scan(&p.ID, &p.Name); err != nil {
+			return nil, fmt.Errorf("scanning profession: %w", err)
+		}
+		professions = append(professions, p)
+	}
+	return professions, rows.Err()
+}
+
+// Recipes reads the abilities of a profession from skilllineability_dbc with
+// their reagents and created item from spell_dbc, which only holds the
+// spells imported into it. Items are named from item_template.
+func (w *World) Recipes(ctx context.Context, skill int, locale string) ([]Recipe, error) {
+	var found int
+	err := w.db.QueryRowContext(ctx, `SELECT ID FROM skillline_dbc WHERE ID = ? AND CategoryID IN `+professionCategories, skill).Scan(&found)
+	if err == sql.ErrNoRows || isMissingTable(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		reagents [8]CraftItem
+		effects  [3]struct{ effect, item, basePoints, dieSides int }
+	)
+	lang := dbcLocale(locale)
+	columns := []string{
+		"sp.ID", "COALESCE(NULLIF(sp.Name_Lang_" + lang + ", ''), sp.Name_Lang_enUS, '')",
+		"MIN(sla.MinSkillLineRank)", "MIN(sla.TrivialSkillLineRankLow)", "MIN(sla.TrivialSkillLineRankHigh)",
+	}
+	for i := range effects {
+		columns = append(columns, fmt.Sprintf("sp.Effect_%[1]d, sp.EffectItemType_%[1]d, sp.EffectBasePoints_%[1]d, sp.EffectDieSides_%[1]d", i+1))
+	}
+	for i := range reagents {
+		columns = append(columns, fmt.Sprintf("sp.Reagent_%[1]d, sp.ReagentCount_%[1]d", i+1))
+	}
+	// A spell is listed once for every race or class it is offered to
+	query := `SELECT ` + strings.Join(columns, ", ") + `
+		FROM skilllineability_dbc sla
+		JOIN spell_dbc sp ON sp.ID = sla.Spell
+		WHERE sla.SkillLine = ?
+		GROUP BY sp.ID`
+
+	rows, err := w.db.QueryContext(ctx, query, skill)
+	if isMissingTable(err) {
+		return []Recipe{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes := []Recipe{}
+	var entries []int
+	for rows.Next() {
+		var r Recipe
+		dest := []interface{}{&r.SpellID, &r.Name, &r.MinSkill, &r.TrivialLow, &r.TrivialHigh}
+		for i := range effects {
+			dest = append(dest, &effects[i].effect, &effects[i].item, &effects[i].basePoints, &effects[i].dieSides)
+		}
+		for i := range reagents {
+			dest = append(dest, &reagents[i].Entry, &reagents[i].Count)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scanning recipe: %w", err)
+		}
+		for _, e := range effects {
+			if e.effect == createItemEffect && e.item > 0 {
+				r.Product.Entry = e.item
+				r.MinCount, r.MaxCount = createdCount(e.basePoints, e.dieSides)
+				break
+			}
+		}
+		if r.Product.Entry == 0 {
+			continue
+		}
+		for _, reagent := range reagents {
+			if reagent.Entry > 0 && reagent.Count > 0 {
+				r.Reagents = append(r.Reagents, reagent)
+				entries = append(entries, reagent.Entry)
+			}
+		}
+		entries = append(entries, r.Product.Entry)
+		recipes = append(recipes, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err := w.Items(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+	var names map[int]string
+	if translated(locale) {
+		if names, err = w.ItemNames(ctx, locale, entries); err != nil {
+			return nil, err
+		}
+	}
+	localize(items, names)
+	for i := range recipes {
+		nameCraftItem(&recipes[i].Product, items)
+		for j := range recipes[i].Reagents {
+			nameCraftItem(&recipes[i].Reagents[j], items)
+		}
+	}
+	return recipes, nil
+}
+
+var _ YieldRepository = (*World)(nil)
+
+// Yields reads the items with a DisenchantID from item_template, then
+// disenchant_loot_template, milling_loot_template, prospecting_loot_template
+// and reference_loot_template. Rows that need a quest never drop from
+// breaking items down and are left out.
+func (w *World) Yields(ctx context.Context) (Yields, error) {
+	y := NewYields()
+	rows, err := w.db.QueryContext(ctx, `SELECT entry, DisenchantID, RequiredDisenchantSkill FROM item_template WHERE DisenchantID > 0`)
+	if err != nil {
+		return y, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			entry int
+			d     Disenchant
+		)
+		if err := rows.Scan(&entry, &d.ID, &d.RequiredSkill); err != nil {
+			return y, fmt.Errorf("scanning disenchant: %w", err)
+		}
+		y.Disenchants[entry] = d
+	}
+	if err := rows.Err(); err != nil {
+		return y, err
+	}
+
+	for table, loot := range map[string]map[int][]LootRow{
+		"disenchant_loot_template":  y.Disenchant,
+		"milling_loot_template":     y.Milling,
+		"prospecting_loot_template": y.Prospecting,
+		"reference_loot_template":   y.Reference,
+	} {
+		if err := w.lootRows(ctx, table, loot, ""); err != nil {
+			return y, err
+		}
+	}
+	return y, nil
+}
+
+// lootRows adds the rows of a loot template to loot, by entry, the ones
+// matching condition when it is set. A missing table adds none.
+func (w *World) lootRows(ctx context.Context, table string, loot map[int][]LootRow, condition string, args ...interface{}) error {
+	query := `
+		SELECT Entry, Item, Reference, Chance, GroupId, MinCount, MaxCount
+		FROM ` + table + `
+		WHERE QuestRequired = 0`
+	if condition != "" {
+		query += ` AND ` + condition
+	}
+	rows, err := w.db.QueryContext(ctx, query, args...)
+	if isMissingTable(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			entry int
+			row   LootRow
+		)
+		if err := rows.Scan(&entry, &row.Item, &row.Reference, &row.Chance, &row.GroupID, &row.MinCount, &row.MaxCount); err != nil {
+			return fmt.Errorf("scanning %s: %w", table, err)
+		}
+		loot[entry] = append(loot[entry], row)
+	}
+	return rows.Err()
+}
+
+var _ SourceRepository = (*World)(nil)
+
+// lootSource is a template whose rows drop the loot of an entry of a loot
+// template
+type lootSource struct {
+	kind, loot, template string
+	// lootID is the column of the template naming its loot entry, in the
+	// rows condition selects
+	lootID, condition string
+}
+
+var lootSources = []lootSource{
+	{SourceCreature, "creature_loot_template", "creature_template", "lootid", ""},
+	// Chests and fishing holes keep their loot entry in Data1
+	{SourceGameObject, "gameobject_loot_template", "gameobject_template", "Data1", "type IN (3, 25) AND "},
+}
+
+// ItemSources reads the creatures and gameobjects whose loot drops an item,
+// directly or through reference_loot_template, the vendors of npc_vendor
+// and the quests of quest_template rewarding it. Rows of the loot templates
+// that need a quest are left out.
+func (w *World) ItemSources(ctx context.Context, entry int, locale string) (ItemSources, error) {
+	sources := ItemSources{Entry: entry, Drops: []DropSource{}, Vendors: []VendorSource{}, Quests: []QuestSource{}}
+
+	refs, err := w.itemReferences(ctx, entry)
+	if err != nil {
+		return sources, err
+	}
+	for _, src := range lootSources {
+		drops, err := w.drops(ctx, src, entry, refs, locale)
+		if err != nil {
+			return sources, err
+		}
+		sources.Drops = append(sources.Drops, drops...)
+	}
+	vendors, err := w.vendors(ctx, entry, locale)
+	if err != nil {
+		return sources, err
+	}
+	sources.Vendors = append(sources.Vendors, vendors...)
+	quests, err := w.questRewards(ctx, entry, locale)
+	if err != nil {
+		return sources, err
+	}
+	sources.Quests = append(sources.Quests, quests...)
+
+	sortSources(&sources)
+	return sources, nil
+}
+
+// itemReferences returns the entries of reference_loot_template that can
+// drop an item, directly or through one another, with all their rows
+func (w *World) itemReferences(ctx context.Context, entry int) (map[int][]LootRow, error) {
+	refs := make(map[int][]LootRow)
+	var all []int
+	condition, args := `Item = ? AND Reference = 0`, []interface{}{entry}
+	for depth := 0; depth < maxReferenceDepth; depth++ {
+		found, err := w.lootEntries(ctx, "reference_loot_template", condition, args...)
+		if err != nil {
+			return nil, err
+		}
+		var added []int
+		for _, e := range found {
+			if _, ok := refs[e]; !ok {
+				refs[e] = nil
+				added = append(added, e)
+			}
+		}
+		if len(added) == 0 {
+			break
+		}
+		all = append(all, added...)
+		condition, args = `Reference IN `+entryList(added), nil
+	}
+	if len(all) == 0 {
+		return refs, nil
+	}
+	return refs, w.lootRows(ctx, "reference_loot_template", refs, `Entry IN `+entryList(all))
+}
+
+// lootEntries returns the entries of a loot template with rows matching
+// condition. A missing table has none.
+func (w *World) lootEntries(ctx context.Context, table, condition string, args ...interface{}) ([]int, error) {
+	rows, err := w.db.QueryContext(ctx, `SELECT DISTINCT Entry FROM `+table+` WHERE QuestRequired = 0 AND (`+condition+`)`, args...)
+	if isMissingTable(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []int
+	for rows.Next() {
+		var entry int
+		if err := rows.Scan(&entry); err != nil {
+			return nil, fmt.Errorf("scanning %s: %w", table, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// drops returns the creatures or gameobjects of src whose loot drops an item
+// directly or through one of refs, named in locale
+func (w *World) drops(ctx context.Context, src lootSource, entry int, refs map[int][]LootRow, locale string) ([]DropSource, error) {
+	condition, args := `Item = ? AND Reference = 0`, []interface{}{entry}
+	if len(refs) > 0 {
+		refEntries := make([]int, 0, len(refs))
+		for ref := range refs {
+			refEntries = append(refEntries, ref)
+		}
+		condition += ` OR Reference IN ` + entryList(refEntries)
+	}
+	lootIDs, err := w.lootEntries(ctx, src.loot, condition, args...)
+	if err != nil || len(lootIDs) == 0 {
+		return nil, err
+	}
+	loot := make(map[int][]LootRow)
+	if err := w.lootRows(ctx, src.loot, loot, `Entry IN `+entryList(lootIDs)); err != nil {
+		return nil, err
+	}
+
+	rows, err := w.db.QueryContext(ctx, `SELECT entry, name, `+src.lootID+` FROM `+src.template+`
+		WHERE `+src.condition+src.lootID+` IN `+entryList(lootIDs))
+	if isMissingTable(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		drops   []DropSource
+		entries []int
+	)
+	for rows.Next() {
+		var (
+			d      = DropSource{Kind: src.kind}
+			lootID int
+		)
+		if err := rows.Scan(&d.Entry, &d.Name, &lootID); err != nil {
+			return nil, fmt.Errorf("scanning %s: %w", src.template, err)
+		}
+		if d.Chance, d.Expected = dropRate(loot[lootID], refs, entry); d.Expected > 0 {
+			drops = append(drops, d)
+			entries = append(entries, d.Entry)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	names, err := w.translations(ctx, src.template+"_locale", "entry", "Name", locale, entries)
+	if err != nil {
+		return nil, err
+	}
+	for i := range drops {
+		if name, ok := names[drops[i].Entry]; ok {
+			drops[i].Name = name
+		}
+	}
+	return drops, nil
+}
+
+// vendors reads the creatures of npc_vendor selling an item, at its
+// item_template.BuyPrice and any extended cost
+func (w *World) vendors(ctx context.Context, entry int, locale string) ([]VendorSource, error) {
+	rows, err := w.db.QueryContext(ctx, `SELECT entry, maxcount, ExtendedCost FROM npc_vendor WHERE item = ?`, entry)
+	if isMissingTable(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		vendors          []VendorSource
+		entries, costIDs []int
+	)
+	for rows.Next() {
+		var (
+			v      VendorSource
+			costID int
+		)
+		if err := rows.Scan(&v.Entry, &v.MaxCount, &costID); err != nil {
+			return nil, fmt.Errorf("scanning vendor: %w", err)
+		}
+		if costID > 0 {
+			v.ExtendedCost = &ExtendedCost{ID: costID}
+			costIDs = append(costIDs, costID)
+		}
+		vendors = append(vendors, v)
+		entries = append(entries, v.Entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(vendors) == 0 {
+		return nil, nil
+	}
+
+	items, err := w.Items(ctx, []int{entry})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string)
+	if err := w.scanNames(ctx, names, `SELECT entry, name FROM creature_template WHERE entry IN `+entryList(entries)); err != nil {
+		return nil, err
+	}
+	translations, err := w.translations(ctx, "creature_template_locale", "entry", "Name", locale, entries)
+	if err != nil {
+		return nil, err
+	}
+	costs, err := w.extendedCosts(ctx, costIDs, locale)
+	if err != nil {
+		return nil, err
+	}
+	for i := range vendors {
+		v := &vendors[i]
+		v.Price, v.BuyCount = items[entry].BuyPrice, max(items[entry].BuyCount, 1)
+		v.Name = names[v.Entry]
+		if name, ok := translations[v.Entry]; ok {
+			v.Name = name
+		}
+		if v.ExtendedCost != nil {
+			if cost, ok := costs[v.ExtendedCost.ID]; ok {
+				v.ExtendedCost = &cost
+			}
+		}
+	}
+	return vendors, nil
+}
+
+// extendedCosts reads the rows of itemextendedcost_dbc with the given IDs,
+// with their items named in locale. Without the table there are none.
+func (w *World) extendedCosts(ctx context.Context, ids []int, locale string) (map[int]ExtendedCost, error) {
+	costs := make(map[int]ExtendedCost)
+	if len(ids) == 0 {
+		return costs, nil
+	}
+
+	var items [5]CostItem
+	columns := []string{"ID", "HonorPoints", "ArenaPoints"}
+	for i := range items {
+		columns = append(columns, fmt.Sprintf("ItemID_%[1]d, ItemCount_%[1]d", i+1))
+	}
+	rows, err := w.db.QueryContext(ctx, `SELECT `+strings.Join(columns, ", ")+` FROM itemextendedcost_dbc WHERE ID IN `+entryList(ids))
+	if isMissingTable(err) {
+		return costs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []int
+	for rows.Next() {
+		var c ExtendedCost
+		dest := []interface{}{&c.ID, &c.HonorPoints, &c.ArenaPoints}
+		for i := range items {
+			dest = append(dest, &items[i].Entry, &items[i].Count)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scanning extended cost: %w", err)
+		}
+		for _, item := range items {
+			if item.Entry > 0 && item.Count > 0 {
+				c.Items = append(c.Items, item)
+				entries = append(entries, item.Entry)
+			}
+		}
+		costs[c.ID] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	templates, err := w.Items(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+	var names map[int]string
+	if translated(locale) {
+		if names, err = w.ItemNames(ctx, locale, entries); err != nil {
+			return nil, err
+		}
+	}
+	localize(templates, names)
+	for _, c := range costs {
+		for i := range c.Items {
+			c.Items[i].Name = templates[c.Items[i].Entry].Name
+		}
+	}
+	return costs, nil
+}
+
+// questRewards reads the quests of quest_template rewarding an item, among
+// the rewards every completion gets or those to choose from
+func (w *World) questRewards(ctx context.Context, entry int, locale string) ([]QuestSource, error) {
+	var (
+		rewards [4]struct{ item, count int }
+		choices [6]struct{ item, count int }
+		matches []string
+	)
+	columns := []string{"ID", "LogTitle", "QuestLevel", "MinLevel"}
+	for i := range rewards {
+		columns = append(columns, fmt.Sprintf("RewardItem%[1]d, RewardAmount%[1]d", i+1))
+		matches = append(matches, fmt.Sprintf("RewardItem%d", i+1))
+	}
+	for i := range choices {
+		columns = append(columns, fmt.Sprintf("RewardChoiceItemID%[1]d, RewardChoiceItemQuantity%[1]d", i+1))
+		matches = append(matches, fmt.Sprintf("RewardChoiceItemID%d", i+1))
+	}
+	query := `SELECT ` + strings.Join(columns, ", ") + `
+		FROM quest_template
+		WHERE ? IN (` + strings.Join(matches, ", ") + `)`
+
+	rows, err := w.db.QueryContext(ctx, query, entry)
+	if isMissingTable(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		quests []QuestSource
+		ids    []int
+	)
+	for rows.Next() {
+		var q QuestSource
+		dest := []interface{}{&q.ID, &q.Title, &q.Level, &q.MinLevel}
+		for i := range rewards {
+			dest = append(dest, &rewards[i].item, &rewards[i].count)
+		}
+		for i := range choices {
+			dest = append(dest, &choices[i].item, &choices[i].count)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scanning quest: %w", err)
+		}
+		for _, r := range rewards {
+			if r.item == entry {
+				q.Count += r.count
+			}
+		}
+		if q.Count == 0 {
+			for _, c := range choices {
+				if c.item == entry {
+					q.Count, q.Choice = c.count, true
+					break
+				}
+			}
+		}
+		quests = append(quests, q)
+		ids = append(ids, q.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	titles, err := w.translations(ctx, "quest_template_locale", "ID", "Title", locale, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range quests {
+		if title, ok := titles[quests[i].ID]; ok {
+			quests[i].Title = title
+		}
+	}
+	return quests, nil
+}
+
+// translations returns the names of ids in a locale table, such as
+// creature_template_locale, translated into locale. Untranslated IDs are left
+// out, and so is everything without the table.
+func (w *World) translations(ctx context.Context, table, id, name, locale string, ids []int) (map[int]string, error) {
+	names := make(map[int]string)
+	if !translated(locale) || len(ids) == 0 {
+		return names, nil
+	}
+	query := `SELECT ` + id + `, ` + name + ` FROM ` + table + `
+		WHERE locale = ? AND ` + name + ` <> '' AND ` + id + ` IN ` + entryList(ids)
+	err := w.scanNames(ctx, names, query, locale)
+	if isMissingTable(err) {
+		return names, nil
+	}
+	return names, err
+}
@@ -0,0 +1,243 @@
+package store
+
+import "sort"
+
+// Ways of breaking items down into materials
+const (
+	YieldDisenchant  = "disenchant"
+	YieldMilling     = "milling"
+	YieldProspecting = "prospecting"
+)
+
+// ValidYieldMethod reports whether method is one of disenchant, milling and
+// prospecting
+func ValidYieldMethod(method string) bool {
+	switch method {
+	case YieldDisenchant, YieldMilling, YieldProspecting:
+		return true
+	}
+	return false
+}
+
+// yieldBatch is how many items milling and prospecting take at a time
+const yieldBatch = 5
+
+// maxReferenceDepth bounds how deep loot references are followed, in case
+// reference_loot_template refers to itself
+const maxReferenceDepth = 5
+
+// LootRow is one row of a loot template, such as disenchant_loot_template.
+// A row with a Reference rolls that entry of reference_loot_template
+// MaxCount times instead of dropping Item.
+type LootRow struct {
+	Item      int
+	Reference int
+	// Chance is the percentage chance the row drops. Within a group, rows
+	// with a chance of 0 share what the others leave equally.
+	Chance   float64
+	GroupID  int
+	MinCount int
+	MaxCount int
+}
+
+// Disenchant is what item_template says about disenchanting an item
+type Disenchant struct {
+	// ID is the entry of disenchant_loot_template the item yields
+	ID int
+	// RequiredSkill is the enchanting skill disenchanting takes
+	RequiredSkill int
+}
+
+// Yields are the loot templates of disenchanting, milling and prospecting,
+// keyed by loot entry: the DisenchantID of an item for disenchanting, the
+// item's own entry for milling and prospecting
+type Yields struct {
+	// Disenchants holds the items with a DisenchantID, by entry
+	Disenchants map[int]Disenchant
+	Disenchant  map[int][]LootRow
+	Milling     map[int][]LootRow
+	Prospecting map[int][]LootRow
+	Reference   map[int][]LootRow
+}
+
+// NewYields returns empty yields
+func NewYields() Yields {
+	return Yields{
+		Disenchants: make(map[int]Disenchant),
+		Disenchant:  make(map[int][]LootRow),
+		Milling:     make(map[int][]LootRow),
+		Prospecting: make(map[int][]LootRow),
+		Reference:   make(map[int][]LootRow),
+	}
+}
+
+// expected returns how many of each item the loot rows yield on average, by
+// item entry, following references depth levels deep at most. Rows outside
+// groups drop on their own chance; a group drops one of its rows, the ones
+// with a chance first, the ones without sharing the rest.
+func (y Yields) expected(rows []LootRow, depth int) map[int]float64 {
+	loot := make(map[int]float64)
+	add := func(row LootRow, probability float64) {
+		if probability <= 0 {
+			return
+		}
+		if row.Reference > 0 {
+			if depth <= 0 {
+				return
+			}
+			for item, count := range y.expected(y.Reference[row.Reference], depth-1) {
+				loot[item] += probability * float64(max(row.MaxCount, 1)) * count
+			}
+			return
+		}
+		loot[row.Item] += probability * float64(row.MinCount+row.MaxCount) / 2
+	}
+
+	groups := make(map[int][]LootRow)
+	for _, row := range rows {
+		if row.GroupID == 0 {
+			add(row, min(row.Chance, 100)/100)
+		} else {
+			groups[row.GroupID] = append(groups[row.GroupID], row)
+		}
+	}
+	for _, group := range groups {
+		left, equal := 100.0, 0
+		for _, row := range group {
+			if row.Chance > 0 {
+				add(row, min(row.Chance, left)/100)
+				left -= min(row.Chance, left)
+			} else {
+				equal++
+			}
+		}
+		for _, row := range group {
+			if row.Chance == 0 {
+				add(row, left/100/float64(equal))
+			}
+		}
+	}
+	return loot
+}
+
+// yield returns how an item breaks down: the method, how many items one
+// operation takes, the enchanting skill it needs and the expected outputs of
+// one operation. It returns false for items that do not break down.
+func (y Yields) yield(entry int) (method string, batch, skill int, loot map[int]float64, ok bool) {
+	if d, found := y.Disenchants[entry]; found && len(y.Disenchant[d.ID]) > 0 {
+		return YieldDisenchant, 1, d.RequiredSkill, y.expected(y.Disenchant[d.ID], maxReferenceDepth), true
+	}
+	if rows := y.Milling[entry]; len(rows) > 0 {
+		return YieldMilling, yieldBatch, 0, y.expected(rows, maxReferenceDepth), true
+	}
+	if rows := y.Prospecting[entry]; len(rows) > 0 {
+		return YieldProspecting, yieldBatch, 0, y.expected(rows, maxReferenceDepth), true
+	}
+	return "", 0, 0, nil, false
+}
+
+// YieldOutput is a material a listing is expected to break down into
+type YieldOutput struct {
+	Entry   int    `json:"entry"`
+	Name    string `json:"name"`
+	Quality int    `json:"quality"`
+	// Expected is how many the whole listing yields on average
+	Expected float64 `json:"expected"`
+	// UnitPrice is the cheapest per-unit buyout of the material, or 0 when
+	// none is listed
+	UnitPrice int `json:"unit_price"`
+}
+
+// YieldListing is a live auction of an item that can be disenchanted, milled
+// or prospected, valued by what it breaks down into
+type YieldListing struct {
+	AuctionItem
+	Method string `json:"method"`
+	// RequiredSkill is the enchanting skill disenchanting the item takes
+	RequiredSkill int           `json:"required_skill,omitempty"`
+	Outputs       []YieldOutput `json:"outputs"`
+	// YieldValue is what selling the outputs at their cheapest buyouts
+	// would earn after the auction house's consignment cut. Materials
+	// nobody lists count for nothing.
+	YieldValue int `json:"yield_value"`
+	Profit     int `json:"profit"`
+	// BelowYield flags buyouts lower than the yield value
+	BelowYield     bool `json:"below_yield"`
+	PercentOfYield int  `json:"percent_of_yield"`
+}
+
+// YieldQuery selects yield listings. Zero values match everything.
+type YieldQuery struct {
+	House   int
+	Method  string
+	Quality IntRange
+	// BelowYield leaves out the listings that cost more than their yield
+	BelowYield bool
+}
+
+// FindYieldListings values the buyouts matching q that can be broken down by
+// their expected yield at the cheapest buyouts of the materials in the same
+// house, most profitable first. houses supplies the consignment rates by
+// house ID.
+func FindYieldListings(auctions []AuctionItem, yields Yields, houses []AuctionHouse, q YieldQuery) []YieldListing {
+	cuts := make(map[int]int, len(houses))
+	for _, h := range houses {
+		cuts[h.ID] = h.ConsignmentRate
+	}
+	cheapest := make(map[[2]int]int)
+	for _, a := range auctions {
+		if a.BuyoutPrice <= 0 || a.Count <= 0 {
+			continue
+		}
+		key := [2]int{a.HouseID, a.ItemEntry}
+		if price, ok := cheapest[key]; !ok || a.UnitBuyout < price {
+			cheapest[key] = a.UnitBuyout
+		}
+	}
+
+	listings := []YieldListing{}
+	for _, a := range auctions {
+		if a.BuyoutPrice <= 0 || a.Count <= 0 {
+			continue
+		}
+		if (q.House != 0 && a.HouseID != q.House) || !q.Quality.contains(a.Quality) {
+			continue
+		}
+		method, batch, skill, loot, ok := yields.yield(a.ItemEntry)
+		if !ok || (q.Method != "" && method != q.Method) {
+			continue
+		}
+
+		l := YieldListing{AuctionItem: a, Method: method, RequiredSkill: skill, Outputs: []YieldOutput{}}
+		var value float64
+		for entry, perOperation := range loot {
+			output := YieldOutput{
+				Entry:     entry,
+				Expected:  perOperation * float64(a.Count) / float64(batch),
+				UnitPrice: cheapest[[2]int{a.HouseID, entry}],
+			}
+			value += output.Expected * float64(output.UnitPrice)
+			l.Outputs = append(l.Outputs, output)
+		}
+		sort.Slice(l.Outputs, func(i, j int) bool { return l.Outputs[i].Entry < l.Outputs[j].Entry })
+		l.YieldValue = int(value) - int(value)*cuts[a.HouseID]/100
+		l.Profit = l.YieldValue - a.BuyoutPrice
+		l.BelowYield = l.Profit > 0
+		if l.YieldValue > 0 {
+			l.PercentOfYield = a.BuyoutPrice * 100 / l.YieldValue
+		}
+		if q.BelowYield && !l.BelowYield {
+			continue
+		}
+		listings = append(listings, l)
+	}
+
+	sort.Slice(listings, func(i, j int) bool {
+		a, b := listings[i], listings[j]
+		if a.Profit != b.Profit {
+			return a.Profit > b.Profit
+		}
+		return a.ID < b.ID
+	})
+	return listings
+}
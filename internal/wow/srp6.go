@@ -0,0 +1,52 @@
+package wow
+
+import (
+	"crypto/sha1"
+	"crypto/subtle"
+	"math/big"
+	"strings"
+)
+
+// SRP6Size is the length in bytes of the salt and verifier acore_auth.account
+// stores for every account
+const SRP6Size = 32
+
+// The SRP6 group of the 3.3.5 client
+var (
+	srp6N, _ = new(big.Int).SetString("894B645E89E1535BBDAD5B8B290650530801B18EBFBF5E8FAB3C82872A3E9BB7", 16)
+	srp6G    = big.NewInt(7)
+)
+
+// SRP6Verifier computes the verifier the auth server stores for an account,
+// the way AzerothCore does when an account is created or its password set:
+// g^x mod N, where x is SHA1(salt | SHA1(USERNAME ":" PASSWORD)). The salt,
+// x and the verifier are little-endian numbers.
+func SRP6Verifier(username, password string, salt []byte) []byte {
+	credentials := sha1.Sum([]byte(strings.ToUpper(username) + ":" + strings.ToUpper(password)))
+	h := sha1.New()
+	h.Write(salt)
+	h.Write(credentials[:])
+
+	x := new(big.Int).SetBytes(reversed(h.Sum(nil)))
+	v := new(big.Int).Exp(srp6G, x, srp6N)
+
+	verifier := make([]byte, SRP6Size)
+	v.FillBytes(verifier)
+	return reversed(verifier)
+}
+
+// CheckSRP6Password reports whether password is the password of the account
+// with the given username, salt and verifier
+func CheckSRP6Password(username, password string, salt, verifier []byte) bool {
+	return subtle.ConstantTimeCompare(SRP6Verifier(username, password, salt), verifier) == 1
+}
+
+// reversed returns a copy of b in reverse order, converting between the
+// little-endian numbers of the auth database and big.Int's big-endian ones
+func reversed(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i, c := range b {
+		r[len(b)-1-i] = c
+	}
+	return r
+}
@@ -0,0 +1,135 @@
+// Package wow holds game constants and the formatting the in-game auction
+// house uses, shared by the store, the API and background jobs.
+package wow
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Auction house IDs used by the worldserver in auctionhouse.houseid
+const (
+	HouseAlliance = 2
+	HouseHorde    = 6
+	HouseNeutral  = 7
+)
+
+// ErrInvalidHouse is returned by ParseHouse for unknown house names
+var ErrInvalidHouse = errors.New("invalid house")
+
+// ParseHouse reads a house parameter. It accepts a numeric house ID or one of
+// "alliance", "horde" and "neutral", and returns 0 when value selects every
+// house.
+func ParseHouse(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "", "all":
+		return 0, nil
+	case "alliance":
+		return HouseAlliance, nil
+	case "horde":
+		return HouseHorde, nil
+	case "neutral", "goblin":
+		return HouseNeutral, nil
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidHouse
+	}
+	return id, nil
+}
+
+// ItemClassTradeGoods is the item_template.class of trade goods, which
+// mod_auctionhousebot stocks apart from other items
+const ItemClassTradeGoods = 7
+
+var qualityNames = []string{"Poor", "Common", "Uncommon", "Rare", "Epic", "Legendary", "Artifact", "Heirloom"}
+
+// QualityName returns the display name of an item quality
+func QualityName(quality int) string {
+	if quality < 0 || quality >= len(qualityNames) {
+		return "Unknown"
+	}
+	return qualityNames[quality]
+}
+
+// QualityColors are the in-game item quality colours as RGB integers
+var QualityColors = map[int]int{
+	0: 0x9d9d9d,
+	1: 0xffffff,
+	2: 0x1eff00,
+	3: 0x0070dd,
+	4: 0xa335ee,
+	5: 0xff8000,
+	6: 0xe6cc80,
+	7: 0xe6cc80,
+}
+
+// FormatGold formats a copper amount the way the game does, e.g. "12g 3s 5c"
+func FormatGold(copper int) string {
+	gold := copper / 10000
+	silver := (copper % 10000) / 100
+	copperRemainder := copper % 100
+
+	var parts []string
+	if gold > 0 {
+		parts = append(parts, fmt.Sprintf("%dg", gold))
+	}
+	if silver > 0 {
+		parts = append(parts, fmt.Sprintf("%ds", silver))
+	}
+	if copperRemainder > 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%dc", copperRemainder))
+	}
+	return strings.Join(parts, " ")
+}
+
+// FormatTimeLeft formats the seconds until an auction expires
+func FormatTimeLeft(seconds int) string {
+	if seconds <= 0 {
+		return "Expired"
+	}
+
+	days := seconds / 86400
+	hours := (seconds % 86400) / 3600
+	minutes := (seconds % 3600) / 60
+
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	} else if hours > 0 {
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	} else {
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// DefaultLocale is the locale of the untranslated item_template columns
+const DefaultLocale = "enUS"
+
+// Locales are the client locales AzerothCore stores translations for, in
+// locale index order
+var Locales = []string{"enUS", "koKR", "frFR", "deDE", "zhCN", "zhTW", "esES", "esMX", "ruRU"}
+
+// ParseLocale returns the game locale matching a locale code such as "deDE",
+// "de-DE" or "de", ignoring case. A bare language, or a region the game has
+// no locale for, selects the first locale of that language.
+func ParseLocale(value string) (string, bool) {
+	code := strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(strings.TrimSpace(value)))
+	for _, locale := range Locales {
+		if strings.ToLower(locale) == code {
+			return locale, true
+		}
+	}
+	if len(code) < 2 {
+		return "", false
+	}
+	for _, locale := range Locales {
+		if strings.ToLower(locale[:2]) == code[:2] && (len(code) == 2 || len(code) == 4) {
+			return locale, true
+		}
+	}
+	return "", false
+}
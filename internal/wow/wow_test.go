@@ -0,0 +1,112 @@
+package wow
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestFormatGold(t *testing.T) {
+	tests := []struct {
+		copper int
+		want   string
+	}{
+		{0, "0c"},
+		{5, "5c"},
+		{100, "1s"},
+		{10000, "1g"},
+		{120305, "12g 3s 5c"},
+		{10005, "1g 5c"},
+	}
+	for _, tt := range tests {
+		if got := FormatGold(tt.copper); got != tt.want {
+			t.Errorf("FormatGold(%d) = %q, want %q", tt.copper, got, tt.want)
+		}
+	}
+}
+
+func TestFormatTimeLeft(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    string
+	}{
+		{-10, "Expired"},
+		{0, "Expired"},
+		{59, "0m"},
+		{1800, "30m"},
+		{7260, "2h 1m"},
+		{90000, "1d 1h"},
+	}
+	for _, tt := range tests {
+		if got := FormatTimeLeft(tt.seconds); got != tt.want {
+			t.Errorf("FormatTimeLeft(%d) = %q, want %q", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestParseHouse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"all", 0, false},
+		{"Alliance", HouseAlliance, false},
+		{"horde", HouseHorde, false},
+		{" neutral ", HouseNeutral, false},
+		{"goblin", HouseNeutral, false},
+		{"6", 6, false},
+		{"0", 0, true},
+		{"-2", 0, true},
+		{"gnome", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseHouse(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseHouse(%q) = %d, %v; want %d, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{"deDE", "deDE", true},
+		{"fr-FR", "frFR", true},
+		{"es_mx", "esMX", true},
+		{"de", "deDE", true},
+		{"en-GB", "enUS", true},
+		{"pt-BR", "", false},
+		{"english", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseLocale(tt.value)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseLocale(%q) = %q, %v; want %q, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSRP6Verifier(t *testing.T) {
+	salt := make([]byte, SRP6Size)
+	for i := range salt {
+		salt[i] = byte(i + 1)
+	}
+	want, _ := hex.DecodeString("f98660f1b3718dbe569e0a0dda7fe59bd8b3c656ca1383df4a166da916781956")
+
+	// Usernames and passwords are upper-cased before hashing
+	if got := SRP6Verifier("player", "hunter2", salt); !bytes.Equal(got, want) {
+		t.Errorf("SRP6Verifier() = %x, want %x", got, want)
+	}
+	if !CheckSRP6Password("Player", "Hunter2", salt, want) {
+		t.Error("CheckSRP6Password() rejected the right password")
+	}
+	if CheckSRP6Password("player", "hunter3", salt, want) || CheckSRP6Password("player", "hunter2", salt[1:], want) {
+		t.Error("CheckSRP6Password() accepted a wrong password or salt")
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/scottjab/azerothcore-web-ah/internal/metrics"
+	"github.com/scottjab/azerothcore-web-ah/internal/store"
+)
+
+// startItemCacheRefresher reloads the item template cache every interval,
+// picking up changes to the world database. It returns immediately.
+func startItemCacheRefresher(items *store.ItemCache, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			refreshItemCache(context.Background(), items)
+		}
+	}()
+}
+
+func refreshItemCache(ctx context.Context, items *store.ItemCache) {
+	start := time.Now()
+	status, err := items.Refresh(ctx)
+	if err != nil {
+		metrics.QueryErrors.Inc("item_cache")
+		log.Printf("Error loading item templates: %v", err)
+		return
+	}
+	log.Printf("Loaded %d item templates in %s", status.Items, time.Since(start).Round(time.Millisecond))
+}
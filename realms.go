@@ -0,0 +1,80 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// realmConfig is a realm and the characters database holding its data
+type realmConfig struct {
+	ID     int
+	Name   string
+	DBName string
+}
+
+// DSN returns the connection string of the realm's characters database.
+// Connection settings are read from REALM_<id>_DB_HOST and friends, falling
+// back to the shared DB_ settings.
+func (rc realmConfig) DSN() string {
+	return buildDSN(fmt.Sprintf("REALM_%d_DB_", rc.ID), rc.DBName)
+}
+
+// loadRealms lists the realms of the auth database's realmlist. When the
+// auth database, which may be nil, cannot be read, the characters database
+// named by DB_NAME is served as the only realm.
+func loadRealms(authDB *sql.DB) []realmConfig {
+	fallback := []realmConfig{{ID: 1, Name: "Realm", DBName: getEnv("DB_NAME", "acore_characters")}}
+	if authDB == nil {
+		log.Println("Serving a single realm without the auth database")
+		return fallback
+	}
+
+	rows, err := authDB.Query(`SELECT id, name FROM realmlist ORDER BY id`)
+	if err != nil {
+		log.Printf("Serving a single realm, could not read realmlist: %v", err)
+		return fallback
+	}
+	defer rows.Close()
+
+	var listed []realmConfig
+	for rows.Next() {
+		var rc realmConfig
+		if err := rows.Scan(&rc.ID, &rc.Name); err != nil {
+			log.Printf("Serving a single realm, could not read realmlist: %v", err)
+			return fallback
+		}
+		listed = append(listed, rc)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Serving a single realm, could not read realmlist: %v", err)
+		return fallback
+	}
+
+	realms := configureRealms(listed)
+	if len(realms) == 0 {
+		log.Println("Serving a single realm, realmlist is empty")
+		return fallback
+	}
+	return realms
+}
+
+// configureRealms names the characters database of every listed realm from
+// REALM_<id>_DB_NAME. The first realm defaults to DB_NAME; the others are
+// skipped unless configured.
+func configureRealms(listed []realmConfig) []realmConfig {
+	var realms []realmConfig
+	for i, rc := range listed {
+		fallback := ""
+		if i == 0 {
+			fallback = getEnv("DB_NAME", "acore_characters")
+		}
+		rc.DBName = getEnv(fmt.Sprintf("REALM_%d_DB_NAME", rc.ID), fallback)
+		if rc.DBName == "" {
+			log.Printf("Skipping realm %d (%s), REALM_%d_DB_NAME is not set", rc.ID, rc.Name, rc.ID)
+			continue
+		}
+		realms = append(realms, rc)
+	}
+	return realms
+}
@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestConfigureRealms(t *testing.T) {
+	t.Setenv("DB_NAME", "chars_main")
+	t.Setenv("DB_USER", "ah")
+	t.Setenv("DB_PASSWORD", "")
+	t.Setenv("DB_PORT", "3306")
+	t.Setenv("REALM_3_DB_NAME", "chars_ptr")
+	t.Setenv("REALM_3_DB_HOST", "ptr.example")
+
+	realms := configureRealms([]realmConfig{{ID: 1, Name: "Main"}, {ID: 2, Name: "Unmapped"}, {ID: 3, Name: "PTR"}})
+	want := []realmConfig{{ID: 1, Name: "Main", DBName: "chars_main"}, {ID: 3, Name: "PTR", DBName: "chars_ptr"}}
+	if len(realms) != len(want) || realms[0] != want[0] || realms[1] != want[1] {
+		t.Fatalf("realms = %+v, want %+v", realms, want)
+	}
+
+	if dsn, want := realms[1].DSN(), "ah:@tcp(ptr.example:3306)/chars_ptr?parseTime=true&loc=Local"; dsn != want {
+		t.Errorf("DSN() = %q, want %q", dsn, want)
+	}
+}